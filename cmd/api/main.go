@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"log"
 
+	"github.com/KoLili12/bulb-server/internal/contentfilter"
 	"github.com/KoLili12/bulb-server/internal/database"
 	"github.com/KoLili12/bulb-server/internal/handlers"
+	"github.com/KoLili12/bulb-server/internal/jobs"
 	"github.com/KoLili12/bulb-server/internal/middleware"
 	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/push"
+	"github.com/KoLili12/bulb-server/internal/realtime"
 	"github.com/KoLili12/bulb-server/internal/repository"
 	"github.com/KoLili12/bulb-server/internal/services"
 	"github.com/KoLili12/bulb-server/pkg/config"
@@ -58,6 +63,81 @@ func main() {
 		log.Fatalf("❌ Failed to migrate Action: %v", err)
 	}
 
+	log.Println("  📝 Migrating DeckMixSource model...")
+	if err := db.AutoMigrate(&models.DeckMixSource{}); err != nil {
+		log.Fatalf("❌ Failed to migrate DeckMixSource: %v", err)
+	}
+
+	log.Println("  📝 Migrating game session models...")
+	if err := db.AutoMigrate(&models.GameSession{}, &models.GamePlayer{}, &models.GameDraw{}, &models.GameResult{}, &models.CardView{}); err != nil {
+		log.Fatalf("❌ Failed to migrate game sessions: %v", err)
+	}
+
+	log.Println("  📝 Migrating room models...")
+	if err := db.AutoMigrate(&models.Room{}, &models.RoomMember{}); err != nil {
+		log.Fatalf("❌ Failed to migrate rooms: %v", err)
+	}
+
+	log.Println("  📝 Migrating play analytics models...")
+	if err := db.AutoMigrate(&models.PlayEvent{}, &models.PlayDailyStat{}); err != nil {
+		log.Fatalf("❌ Failed to migrate play analytics: %v", err)
+	}
+
+	log.Println("  📝 Migrating TrendingScore model...")
+	if err := db.AutoMigrate(&models.TrendingScore{}); err != nil {
+		log.Fatalf("❌ Failed to migrate trending scores: %v", err)
+	}
+
+	log.Println("  📝 Migrating like and favorite models...")
+	if err := db.AutoMigrate(&models.CollectionLike{}, &models.CollectionFavorite{}); err != nil {
+		log.Fatalf("❌ Failed to migrate likes and favorites: %v", err)
+	}
+
+	log.Println("  📝 Migrating CollectionReview model...")
+	if err := db.AutoMigrate(&models.CollectionReview{}); err != nil {
+		log.Fatalf("❌ Failed to migrate reviews: %v", err)
+	}
+
+	log.Println("  📝 Migrating ActionVote model...")
+	if err := db.AutoMigrate(&models.ActionVote{}); err != nil {
+		log.Fatalf("❌ Failed to migrate card votes: %v", err)
+	}
+
+	log.Println("  📝 Migrating Comment model...")
+	if err := db.AutoMigrate(&models.Comment{}); err != nil {
+		log.Fatalf("❌ Failed to migrate comments: %v", err)
+	}
+
+	log.Println("  📝 Migrating CardSuggestion model...")
+	if err := db.AutoMigrate(&models.CardSuggestion{}); err != nil {
+		log.Fatalf("❌ Failed to migrate card suggestions: %v", err)
+	}
+
+	log.Println("  📝 Migrating Report and ModerationLog models...")
+	if err := db.AutoMigrate(&models.Report{}, &models.ModerationLog{}); err != nil {
+		log.Fatalf("❌ Failed to migrate moderation: %v", err)
+	}
+
+	log.Println("  📝 Migrating UserFollow model...")
+	if err := db.AutoMigrate(&models.UserFollow{}); err != nil {
+		log.Fatalf("❌ Failed to migrate follows: %v", err)
+	}
+
+	log.Println("  📝 Migrating UserBlock model...")
+	if err := db.AutoMigrate(&models.UserBlock{}); err != nil {
+		log.Fatalf("❌ Failed to migrate blocks: %v", err)
+	}
+
+	log.Println("  📝 Migrating Notification model...")
+	if err := db.AutoMigrate(&models.Notification{}, &models.NotificationActor{}); err != nil {
+		log.Fatalf("❌ Failed to migrate notifications: %v", err)
+	}
+
+	log.Println("  📝 Migrating DeviceToken model...")
+	if err := db.AutoMigrate(&models.DeviceToken{}); err != nil {
+		log.Fatalf("❌ Failed to migrate device tokens: %v", err)
+	}
+
 	log.Println("✅ Database migrations completed successfully")
 
 	// Инициализация Gin
@@ -68,8 +148,8 @@ func main() {
 	log.Println("🔧 Setting up CORS middleware...")
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-Member-Token, Last-Event-ID")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -84,22 +164,99 @@ func main() {
 	userRepo := repository.NewUserRepository(db)
 	collectionRepo := repository.NewCollectionRepository(db)
 	actionRepo := repository.NewActionRepository(db)
+	gameRepo := repository.NewGameRepository(db)
+	roomRepo := repository.NewRoomRepository(db)
+	historyRepo := repository.NewHistoryRepository(db)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
+	trendingRepo := repository.NewTrendingRepository(db)
+	likeRepo := repository.NewLikeRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	feedbackRepo := repository.NewCardFeedbackRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	suggestionRepo := repository.NewSuggestionRepository(db)
+	moderationRepo := repository.NewModerationRepository(db)
+	followRepo := repository.NewFollowRepository(db)
+	blockRepo := repository.NewBlockRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	deviceTokenRepo := repository.NewDeviceTokenRepository(db)
+
+	// Рейтинг коллекций, созданных до появления возрастных ограничений
+	if updated, err := collectionRepo.BackfillAgeRatings(); err != nil {
+		log.Fatalf("❌ Failed to backfill collection age ratings: %v", err)
+	} else if updated > 0 {
+		log.Printf("🔞 Age ratings updated for %d collections", updated)
+	}
 
 	// Инициализация сервисов
 	log.Println("⚙️  Initializing services...")
 	userService := services.NewUserService(userRepo)
 	authService := services.NewAuthService(cfg)
-	collectionService := services.NewCollectionService(collectionRepo, actionRepo, userRepo)
+	filterMode := contentfilter.Mode(cfg.ContentFilter.Mode)
+	if !filterMode.IsValid() {
+		log.Fatalf("❌ Invalid content filter mode: %s", cfg.ContentFilter.Mode)
+	}
+	contentFilter, err := contentfilter.New(cfg.ContentFilter)
+	if err != nil {
+		log.Fatalf("❌ Failed to load content filter word lists: %v", err)
+	}
+	log.Printf("🧹 Content filter mode: %s", filterMode)
+	contentPolicy := services.NewContentPolicy(contentFilter, filterMode, moderationRepo)
+	pushSenders, err := push.NewSenders(cfg.Push)
+	if err != nil {
+		log.Fatalf("❌ Failed to configure push delivery: %v", err)
+	}
+	log.Printf("📱 Push delivery: FCM configured=%t, APNs configured=%t", pushSenders.FCM != nil, pushSenders.APNs != nil)
+	pushService := services.NewPushService(deviceTokenRepo, userRepo, pushSenders, push.NewQueueConfig(cfg.Push))
+	notificationService := services.NewNotificationService(notificationRepo, userRepo, blockRepo, pushService)
+	collectionService := services.NewCollectionService(collectionRepo, actionRepo, userRepo, contentPolicy)
+	deckService := services.NewDeckService(collectionRepo, actionRepo, userRepo, blockRepo, notificationService)
+	gameService := services.NewGameService(gameRepo, historyRepo, feedbackRepo, collectionService)
+	roomBroker := realtime.NewMemoryBroker()
+	historyService := services.NewHistoryService(historyRepo, collectionService)
+	analyticsService := services.NewAnalyticsService(analyticsRepo, collectionService)
+	trendingService := services.NewTrendingService(trendingRepo, cfg.Trending)
+	likeService := services.NewLikeService(likeRepo, collectionRepo, notificationService)
+	reviewService := services.NewReviewService(reviewRepo, collectionRepo)
+	feedbackService := services.NewCardFeedbackService(feedbackRepo, actionRepo, collectionService)
+	commentService := services.NewCommentService(commentRepo, collectionRepo, userRepo, blockRepo, notificationService)
+	suggestionService := services.NewSuggestionService(suggestionRepo, collectionRepo, blockRepo, collectionService, notificationService)
+	moderationService := services.NewModerationService(moderationRepo, userRepo, collectionRepo, actionRepo, commentRepo)
+	followService := services.NewFollowService(followRepo, userRepo, blockRepo, notificationService)
+	profileService := services.NewProfileService(userRepo, collectionRepo, followRepo, blockRepo)
+	roomService := services.NewRoomService(roomRepo, userRepo, historyRepo, blockRepo, followRepo, collectionService, gameService, pushService, roomBroker)
+	blockService := services.NewBlockService(blockRepo, followRepo, userRepo, roomService)
 
 	// Инициализация обработчиков
 	log.Println("🎯 Initializing handlers...")
 	authHandler := handlers.NewAuthHandler(userService, authService)
-	userHandler := handlers.NewUserHandler(userService)
-	collectionHandler := handlers.NewCollectionHandler(collectionService)
+	userHandler := handlers.NewUserHandler(userService, profileService, likeService)
+	collectionHandler := handlers.NewCollectionHandler(collectionService, likeService, feedbackService)
+	deckHandler := handlers.NewDeckHandler(deckService)
+	gameHandler := handlers.NewGameHandler(gameService)
+	roomHandler := handlers.NewRoomHandler(roomService)
+	historyHandler := handlers.NewHistoryHandler(historyService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	likeHandler := handlers.NewLikeHandler(likeService)
+	reviewHandler := handlers.NewReviewHandler(reviewService)
+	feedbackHandler := handlers.NewCardFeedbackHandler(feedbackService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	suggestionHandler := handlers.NewSuggestionHandler(suggestionService)
+	moderationHandler := handlers.NewModerationHandler(moderationService)
+	followHandler := handlers.NewFollowHandler(followService, likeService)
+	blockHandler := handlers.NewBlockHandler(blockService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	deviceHandler := handlers.NewDeviceHandler(pushService)
+
+	// Фоновые задачи
+	log.Println("⏱️  Starting background jobs...")
+	go jobs.NewAnalyticsJob(gameService, analyticsService).Start(context.Background())
+	go jobs.NewTrendingJob(trendingService).Start(context.Background())
+	go pushService.Start(context.Background())
 
 	// Middleware
 	log.Println("🔐 Setting up authentication middleware...")
-	authMiddleware := middleware.NewAuthMiddleware(authService)
+	authMiddleware := middleware.NewAuthMiddleware(authService, userService)
+	ageRatingMiddleware := middleware.NewAgeRatingMiddleware(userService)
 
 	// Настройка маршрутов
 	log.Println("🛣️  Setting up routes...")
@@ -126,20 +283,65 @@ func main() {
 			auth.POST("/refresh", authHandler.RefreshToken)
 		}
 
-		// Публичные коллекции (просмотр без авторизации)
+		// Публичные коллекции (просмотр без авторизации, с токеном - отметки пользователя).
+		// Семейный режим (X-Safe-Mode или ?safeMode=true) и возраст пользователя скрывают взрослые коллекции
 		collections := api.Group("/collections")
+		collections.Use(authMiddleware.OptionalAuth(), ageRatingMiddleware.Limit())
 		{
 			collections.GET("", collectionHandler.List)                    // Список всех коллекций
 			collections.GET("/trending", collectionHandler.GetTrending)    // Популярные коллекции
 			collections.GET("/:id", collectionHandler.GetByID)             // Коллекция по ID
 			collections.GET("/:id/actions", collectionHandler.GetActions)  // Карточки коллекции
 			collections.GET("/:id/stats", collectionHandler.GetCollectionStats) // Статистика коллекции
+			collections.GET("/:id/reviews", reviewHandler.List)                // Отзывы о коллекции
+			collections.GET("/:id/comments", commentHandler.List)              // Ветки комментариев
+			collections.POST("/:id/render", collectionHandler.RenderActions)   // Подстановка игроков в карточки
 		}
 
-		// Публичная информация о пользователях
+		// Ответы в ветке комментариев (с токеном - без ответов заблокированных пользователей)
+		api.GET("/comments/:id/replies", authMiddleware.OptionalAuth(), commentHandler.ListReplies)
+
+		// Смешивание коллекций (источники с рейтингом выше допустимого пропускаются)
+		api.POST("/decks/mix", authMiddleware.OptionalAuth(), ageRatingMiddleware.Limit(), deckHandler.Mix)
+
+		// Игровые сессии (доступны и без авторизации)
+		games := api.Group("/games")
+		games.Use(authMiddleware.OptionalAuth(), ageRatingMiddleware.Limit())
+		{
+			games.POST("", gameHandler.Start)                  // Начало игры
+			games.GET("/:id", gameHandler.GetByID)             // Состояние игры
+			games.POST("/:id/players", gameHandler.AddPlayer)  // Добавление игрока
+			games.POST("/:id/draw", gameHandler.Draw)          // Следующая карточка
+			games.POST("/:id/complete", gameHandler.Complete)  // Карточка выполнена
+			games.POST("/:id/skip", gameHandler.Skip)          // Карточка пропущена
+			games.POST("/:id/end", gameHandler.End)            // Завершение игры
+		}
+
+		// Игровые комнаты для игры с нескольких устройств
+		rooms := api.Group("/rooms")
+		rooms.Use(authMiddleware.OptionalAuth(), ageRatingMiddleware.Limit())
+		{
+			rooms.POST("", roomHandler.Create)              // Создание комнаты
+			rooms.GET("/:code", roomHandler.GetByCode)      // Состояние комнаты
+			rooms.POST("/:code/join", roomHandler.Join)     // Вход в комнату
+			rooms.GET("/:code/ws", roomHandler.WebSocket)   // WebSocket-подключение участника
+			rooms.GET("/:code/events", roomHandler.Events)  // Поток событий SSE (если WebSocket недоступен)
+			rooms.POST("/:code/commands", roomHandler.Command) // Команда участника по HTTP
+			rooms.POST("/:code/invite", roomHandler.Invite)    // Push-приглашение пользователю
+		}
+
+		// Справочник типов карточек
+		api.GET("/action-types", collectionHandler.GetActionTypes)
+
+		// Публичные профили авторов (с токеном - подписан ли на них пользователь).
+		// Скрытое настройками приватности видит только владелец профиля
 		users := api.Group("/users")
+		users.Use(authMiddleware.OptionalAuth(), ageRatingMiddleware.Limit())
 		{
-			users.GET("/:id", userHandler.GetUserByID) // Публичная информация о пользователе
+			users.GET("/:id", userHandler.GetUserByID)                // Публичный профиль автора
+			users.GET("/:id/collections", userHandler.GetCollections) // Коллекции автора
+			users.GET("/:id/followers", followHandler.GetFollowers)  // Подписчики
+			users.GET("/:id/following", followHandler.GetFollowing)  // Подписки
 		}
 
 		// ===== ЗАЩИЩЕННЫЕ МАРШРУТЫ (требуют аутентификации) =====
@@ -150,7 +352,12 @@ func main() {
 			// Профиль пользователя
 			protected.GET("/me", userHandler.GetProfile)                    // Текущий пользователь
 			protected.GET("/user/profile", userHandler.GetProfile)          // Альтернативный эндпоинт
+			protected.GET("/me/game-results", gameHandler.GetMyResults)     // Результаты игр пользователя
+			protected.GET("/me/history", historyHandler.GetMyHistory)       // Просмотренные карточки
+			protected.DELETE("/me/history", historyHandler.ResetMyHistory)  // Очистка истории
+			protected.GET("/me/favorites", likeHandler.GetMyFavorites)      // Избранные коллекции
 			protected.PUT("/user/profile", userHandler.UpdateProfile)       // Обновление профиля
+			protected.PUT("/me/privacy", userHandler.UpdatePrivacy)         // Что скрыть в публичном профиле
 
 			// Коллекции пользователя
 			protected.GET("/user/collections", collectionHandler.GetUserCollections) // Коллекции пользователя
@@ -160,10 +367,77 @@ func main() {
 			protected.POST("/collections/with-actions", collectionHandler.CreateWithActions) // Создание коллекции с карточками
 			protected.PUT("/collections/:id", collectionHandler.Update)                     // Обновление коллекции
 			protected.DELETE("/collections/:id", collectionHandler.Delete)                  // Удаление коллекции
+			protected.GET("/collections/:id/analytics", analyticsHandler.GetCollectionAnalytics) // Статистика партий для владельца
+
+			// Лайки и избранное (идемпотентные)
+			protected.PUT("/collections/:id/like", likeHandler.Like)
+			protected.DELETE("/collections/:id/like", likeHandler.Unlike)
+			protected.PUT("/collections/:id/favorite", likeHandler.Favorite)
+			protected.DELETE("/collections/:id/favorite", likeHandler.Unfavorite)
+
+			// Оценки и отзывы (один отзыв пользователя на коллекцию)
+			protected.GET("/collections/:id/review", reviewHandler.GetMine)
+			protected.PUT("/collections/:id/review", reviewHandler.Save)
+			protected.DELETE("/collections/:id/review", reviewHandler.Delete)
+			protected.PUT("/reviews/:id/reply", reviewHandler.Reply) // Ответ владельца коллекции
+
+			// Комментарии
+			protected.POST("/collections/:id/comments", commentHandler.Create)
+			protected.PUT("/comments/:id", commentHandler.Update)
+			protected.DELETE("/comments/:id", commentHandler.Delete)
+			protected.PUT("/comments/:id/pin", commentHandler.Pin) // Закрепление владельцем коллекции
+
+			// Предложенные карточки
+			protected.POST("/collections/:id/suggestions", suggestionHandler.Suggest)
+			protected.GET("/me/suggestions", suggestionHandler.GetMySuggestions)    // Свои предложения и их статусы
+			protected.GET("/me/suggestions/inbox", suggestionHandler.GetInbox)      // Предложения к своим коллекциям
+			protected.POST("/suggestions/:id/accept", suggestionHandler.Accept)
+			protected.POST("/suggestions/:id/reject", suggestionHandler.Reject)
+
+			// Подписки на авторов (идемпотентные) и лента новых и измененных коллекций из подписок
+			protected.PUT("/users/:id/follow", followHandler.Follow)
+			protected.DELETE("/users/:id/follow", followHandler.Unfollow)
+			protected.GET("/feed", ageRatingMiddleware.Limit(), followHandler.GetFeed) // ?cursor=&size=
+
+			// Блокировка пользователей (идемпотентная): скрывает их контент и запрещает им взаимодействие
+			protected.PUT("/users/:id/block", blockHandler.Block)
+			protected.DELETE("/users/:id/block", blockHandler.Unblock)
+			protected.GET("/me/blocked", blockHandler.GetBlocked)
+
+			// Уведомления: однотипные события объединяются до прочтения
+			protected.GET("/me/notifications", notificationHandler.List) // ?page=&size=&unreadOnly=true
+			protected.GET("/me/notifications/unread-count", notificationHandler.UnreadCount)
+			protected.POST("/me/notifications/read-all", notificationHandler.MarkAllRead)
+			protected.POST("/me/notifications/:id/read", notificationHandler.MarkRead)
+			protected.PUT("/me/notification-preferences", notificationHandler.UpdatePreferences)
+
+			// Push-токены устройств: регистрация после входа, удаление при выходе
+			protected.PUT("/me/devices", deviceHandler.Register)
+			protected.DELETE("/me/devices", deviceHandler.Unregister)
+
+			// Жалобы на контент и пользователей
+			protected.POST("/collections/:id/report", moderationHandler.ReportCollection)
+			protected.POST("/actions/:id/report", moderationHandler.ReportAction)
+			protected.POST("/comments/:id/report", moderationHandler.ReportComment)
+			protected.POST("/users/:id/report", moderationHandler.ReportUser)
+
+			// Очередь модерации (только для модераторов)
+			protected.GET("/moderation/reports", moderationHandler.GetQueue)                 // ?status=&targetType=&mine=true
+			protected.POST("/moderation/reports/:id/claim", moderationHandler.Claim)
+			protected.POST("/moderation/reports/:id/release", moderationHandler.Release)
+			protected.POST("/moderation/reports/:id/resolve", moderationHandler.Resolve)
+			protected.POST("/moderation/collections/:id/unhide", moderationHandler.UnhideCollection) // Отмена скрытия
+			protected.GET("/moderation/audit", moderationHandler.GetAuditLog)                // Журнал решений
+
+			// Сохранение микса как виртуальной коллекции
+			protected.POST("/decks", ageRatingMiddleware.Limit(), deckHandler.SaveMix)
 
 			// Управление карточками
 			protected.POST("/collections/:id/actions", collectionHandler.AddAction)  // Добавление карточки
+			protected.PATCH("/collections/:id/actions", collectionHandler.BatchActions) // Пакетное изменение карточек
 			protected.DELETE("/actions/:id", collectionHandler.RemoveAction)         // Удаление карточки
+			protected.PUT("/actions/:id/vote", feedbackHandler.Vote)                 // Голос за карточку
+			protected.DELETE("/actions/:id/vote", feedbackHandler.RemoveVote)        // Отзыв голоса
 		}
 	}
 
@@ -176,23 +450,103 @@ func main() {
 	log.Println("    POST /api/auth/refresh")
 	log.Println("  👥 Users:")
 	log.Println("    GET  /api/users/:id")
+	log.Println("    GET  /api/users/:id/collections?sort=newest|popular|rating")
+	log.Println("    GET  /api/users/:id/followers")
+	log.Println("    GET  /api/users/:id/following")
+	log.Println("    PUT  /api/users/:id/follow (protected)")
+	log.Println("    DELETE /api/users/:id/follow (protected)")
+	log.Println("    PUT  /api/users/:id/block (protected)")
+	log.Println("    DELETE /api/users/:id/block (protected)")
+	log.Println("    GET  /api/me/blocked (protected)")
+	log.Println("    GET  /api/feed?cursor=&size=&safeMode=true (protected)")
 	log.Println("    GET  /api/me (protected)")
 	log.Println("    GET  /api/user/profile (protected)")
 	log.Println("    PUT  /api/user/profile (protected)")
+	log.Println("    PUT  /api/me/privacy (protected)")
+	log.Println("  🔔 Notifications:")
+	log.Println("    GET  /api/me/notifications?page=&size=&unreadOnly=true (protected)")
+	log.Println("    GET  /api/me/notifications/unread-count (protected)")
+	log.Println("    POST /api/me/notifications/read-all (protected)")
+	log.Println("    POST /api/me/notifications/:id/read (protected)")
+	log.Println("    PUT  /api/me/notification-preferences (protected)")
+	log.Println("    PUT  /api/me/devices (protected)")
+	log.Println("    DELETE /api/me/devices (protected)")
 	log.Println("  📚 Collections:")
-	log.Println("    GET  /api/collections")
-	log.Println("    GET  /api/collections/trending")
+	log.Println("    GET  /api/collections?sort=newest|popular|rating&safeMode=true")
+	log.Println("    GET  /api/collections/trending?window=day|week|month|all")
 	log.Println("    GET  /api/collections/:id")
 	log.Println("    GET  /api/collections/:id/actions")
 	log.Println("    GET  /api/collections/:id/stats")
+	log.Println("    GET  /api/collections/:id/reviews")
+	log.Println("    POST /api/collections/:id/render")
 	log.Println("    GET  /api/user/collections (protected)")
 	log.Println("    POST /api/collections (protected)")
 	log.Println("    POST /api/collections/with-actions (protected)")
 	log.Println("    PUT  /api/collections/:id (protected)")
 	log.Println("    DELETE /api/collections/:id (protected)")
+	log.Println("    GET  /api/collections/:id/analytics (protected)")
+	log.Println("    PUT  /api/collections/:id/like (protected)")
+	log.Println("    DELETE /api/collections/:id/like (protected)")
+	log.Println("    PUT  /api/collections/:id/favorite (protected)")
+	log.Println("    DELETE /api/collections/:id/favorite (protected)")
+	log.Println("    GET  /api/me/favorites (protected)")
+	log.Println("    GET  /api/collections/:id/review (protected)")
+	log.Println("    PUT  /api/collections/:id/review (protected)")
+	log.Println("    DELETE /api/collections/:id/review (protected)")
+	log.Println("    PUT  /api/reviews/:id/reply (protected)")
+	log.Println("  💬 Comments:")
+	log.Println("    GET  /api/collections/:id/comments")
+	log.Println("    GET  /api/comments/:id/replies")
+	log.Println("    POST /api/collections/:id/comments (protected)")
+	log.Println("    PUT  /api/comments/:id (protected)")
+	log.Println("    DELETE /api/comments/:id (protected)")
+	log.Println("    PUT  /api/comments/:id/pin (protected)")
+	log.Println("  💡 Suggestions:")
+	log.Println("    POST /api/collections/:id/suggestions (protected)")
+	log.Println("    GET  /api/me/suggestions (protected)")
+	log.Println("    GET  /api/me/suggestions/inbox (protected)")
+	log.Println("    POST /api/suggestions/:id/accept (protected)")
+	log.Println("    POST /api/suggestions/:id/reject (protected)")
+	log.Println("  🚩 Moderation:")
+	log.Println("    POST /api/collections/:id/report (protected)")
+	log.Println("    POST /api/actions/:id/report (protected)")
+	log.Println("    POST /api/comments/:id/report (protected)")
+	log.Println("    POST /api/users/:id/report (protected)")
+	log.Println("    GET  /api/moderation/reports (moderator)")
+	log.Println("    POST /api/moderation/reports/:id/claim (moderator)")
+	log.Println("    POST /api/moderation/reports/:id/release (moderator)")
+	log.Println("    POST /api/moderation/reports/:id/resolve (moderator)")
+	log.Println("    POST /api/moderation/collections/:id/unhide (moderator)")
+	log.Println("    GET  /api/moderation/audit (moderator)")
+	log.Println("  🔀 Decks:")
+	log.Println("    POST /api/decks/mix")
+	log.Println("    POST /api/decks (protected)")
+	log.Println("  🎲 Games:")
+	log.Println("    POST /api/games")
+	log.Println("    GET  /api/games/:id")
+	log.Println("    POST /api/games/:id/players")
+	log.Println("    POST /api/games/:id/draw")
+	log.Println("    POST /api/games/:id/complete")
+	log.Println("    POST /api/games/:id/skip")
+	log.Println("    POST /api/games/:id/end")
+	log.Println("    GET  /api/me/game-results (protected)")
+	log.Println("    GET  /api/me/history (protected)")
+	log.Println("    DELETE /api/me/history (protected)")
+	log.Println("  🏠 Rooms:")
+	log.Println("    POST /api/rooms")
+	log.Println("    GET  /api/rooms/:code")
+	log.Println("    POST /api/rooms/:code/join")
+	log.Println("    GET  /api/rooms/:code/ws (WebSocket)")
+	log.Println("    GET  /api/rooms/:code/events (SSE)")
+	log.Println("    POST /api/rooms/:code/commands")
+	log.Println("    POST /api/rooms/:code/invite")
 	log.Println("  🃏 Actions:")
+	log.Println("    GET  /api/action-types")
 	log.Println("    POST /api/collections/:id/actions (protected)")
+	log.Println("    PATCH /api/collections/:id/actions (protected)")
 	log.Println("    DELETE /api/actions/:id (protected)")
+	log.Println("    PUT  /api/actions/:id/vote (protected)")
+	log.Println("    DELETE /api/actions/:id/vote (protected)")
 
 	// Запуск сервера
 	serverAddr := ":" + cfg.Server.Port
@@ -0,0 +1,256 @@
+package contentfilter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/KoLili12/bulb-server/pkg/config"
+)
+
+// Mode определяет, что делать с контентом, в котором фильтр нашел запрещенные слова
+type Mode string
+
+const (
+	ModeOff    Mode = "off"    // Фильтр отключен
+	ModeReject Mode = "reject" // Отклонить сохранение
+	ModeFlag   Mode = "flag"   // Сохранить и поставить в очередь модерации
+	ModeAdult  Mode = "adult"  // Сохранить и пометить как 18+
+)
+
+// IsValid проверяет, что режим фильтра известен
+func (m Mode) IsValid() bool {
+	switch m {
+	case ModeOff, ModeReject, ModeFlag, ModeAdult:
+		return true
+	}
+	return false
+}
+
+// Language определяет язык словаря, от него зависят окончания и приставки при сравнении
+type Language string
+
+const (
+	LanguageRussian Language = "ru"
+	LanguageEnglish Language = "en"
+)
+
+// Match описывает найденное в тексте запрещенное слово
+type Match struct {
+	List string // Название словаря
+	Term string // Шаблон из словаря
+	Word string // Слово в том виде, как оно написано в тексте
+}
+
+// Filter определяет проверку текста на запрещенные слова.
+// Реализацию на словарях можно заменить внешним сервисом модерации.
+type Filter interface {
+	// Check возвращает найденные в тексте запрещенные слова, nil - текст чистый
+	Check(text string) []Match
+}
+
+// WordList представляет словарь шаблонов запрещенных слов. Синтаксис шаблона:
+//
+//	word    - слово целиком, в том числе с окончаниями языка (fucks, суки)
+//	stem*   - слово, начинающееся с основы; в русском допускается глагольная приставка (заебал)
+//	*root*  - корень в любом месте слова
+//
+// Регистр, ё/е, повторы букв, leetspeak и похожие буквы другого алфавита не важны.
+type WordList struct {
+	Name     string
+	Language Language
+	Terms    []string
+}
+
+// WordListFilter реализует Filter на словарях с учетом морфологии и leetspeak
+type WordListFilter struct {
+	terms []*term
+}
+
+// term представляет скомпилированный шаблон словаря
+type term struct {
+	list    string
+	pattern string
+	re      *regexp.Regexp
+}
+
+// Окончания, с которыми совпадает шаблон целого слова
+var inflections = map[Language][]string{
+	LanguageEnglish: {"s", "es", "ed", "ing"},
+	LanguageRussian: {"а", "я", "ы", "и", "е", "у", "ю", "о", "ь", "ой", "ей", "ом", "ем", "ам", "ям", "ами", "ями", "ах", "ях", "ов", "ев"},
+}
+
+// Приставки, которые допускаются перед русской основой: заебал, нахуй, охуеть.
+// Однобуквенные "в" и "с" не включены: с ними основы совпадают с обычными словами (себя, вебинар).
+var russianPrefixes = []string{
+	"вы", "въ", "вз", "взъ", "за", "на", "над", "надъ", "до", "по", "под", "подъ", "от", "отъ",
+	"о", "об", "обо", "объ", "раз", "разъ", "рас", "съ", "у", "при", "пере", "недо", "про", "из", "изъ", "ис",
+}
+
+// New создает фильтр по настройкам: встроенные словари (если не отключены) и словари из файлов
+func New(cfg config.ContentFilterConfig) (*WordListFilter, error) {
+	var lists []WordList
+	if !cfg.DisableDefaultLists {
+		lists = append(lists, DefaultWordLists()...)
+	}
+	for _, path := range cfg.WordLists {
+		list, err := LoadWordList(path)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+	return NewWordListFilter(lists...)
+}
+
+// NewWordListFilter создает фильтр по словарям
+func NewWordListFilter(lists ...WordList) (*WordListFilter, error) {
+	filter := &WordListFilter{}
+	for _, list := range lists {
+		for _, pattern := range list.Terms {
+			compiled, err := compileTerm(list.Language, pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid term %q in word list %s: %w", pattern, list.Name, err)
+			}
+			if compiled == nil {
+				continue
+			}
+			filter.terms = append(filter.terms, &term{list: list.Name, pattern: pattern, re: compiled})
+		}
+	}
+	return filter, nil
+}
+
+// LoadWordList читает словарь из файла: один шаблон на строку, # - комментарий.
+// Язык определяется по суффиксу имени файла (profanity.ru.txt), иначе по алфавиту шаблонов.
+func LoadWordList(path string) (WordList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return WordList{}, fmt.Errorf("failed to open word list: %w", err)
+	}
+	defer file.Close()
+
+	name := filepath.Base(path)
+	list, err := ParseWordList(name, file)
+	if err != nil {
+		return WordList{}, fmt.Errorf("failed to read word list %s: %w", name, err)
+	}
+	switch {
+	case strings.Contains(name, ".ru."):
+		list.Language = LanguageRussian
+	case strings.Contains(name, ".en."):
+		list.Language = LanguageEnglish
+	}
+	return list, nil
+}
+
+// ParseWordList читает словарь из r. Язык определяется по алфавиту большинства шаблонов.
+func ParseWordList(name string, r io.Reader) (WordList, error) {
+	list := WordList{Name: name}
+	cyrillic := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if isCyrillicWord(line) {
+			cyrillic++
+		}
+		list.Terms = append(list.Terms, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return WordList{}, err
+	}
+
+	list.Language = LanguageEnglish
+	if cyrillic*2 > len(list.Terms) {
+		list.Language = LanguageRussian
+	}
+	return list, nil
+}
+
+// Check возвращает найденные в тексте запрещенные слова, каждый шаблон - не более одного раза
+func (f *WordListFilter) Check(text string) []Match {
+	if len(f.terms) == 0 {
+		return nil
+	}
+
+	var matches []Match
+	seen := make(map[*term]bool)
+	for _, word := range splitWords(text) {
+		normalized := normalizeWord(word)
+		if normalized == "" {
+			continue
+		}
+		for _, t := range f.terms {
+			if seen[t] || !t.re.MatchString(normalized) {
+				continue
+			}
+			seen[t] = true
+			matches = append(matches, Match{List: t.list, Term: t.pattern, Word: word})
+		}
+	}
+	return matches
+}
+
+// compileTerm превращает шаблон словаря в регулярное выражение над нормализованным словом.
+// Каждая буква может повторяться (fuuuck), поэтому повторы в тексте не схлопываются.
+func compileTerm(language Language, pattern string) (*regexp.Regexp, error) {
+	prefix := strings.HasPrefix(pattern, "*")
+	suffix := strings.HasSuffix(pattern, "*")
+	core := normalizeTerm(strings.Trim(pattern, "*"))
+	if core == "" {
+		return nil, nil
+	}
+
+	var body strings.Builder
+	for _, r := range core {
+		body.WriteString(regexp.QuoteMeta(string(r)))
+		body.WriteString("+")
+	}
+
+	var expr string
+	switch {
+	case prefix && suffix:
+		expr = body.String()
+	case suffix:
+		expr = "^" + body.String()
+		if language == LanguageRussian {
+			expr = "^(?:" + alternation(russianPrefixes) + ")?" + body.String()
+		}
+	case prefix:
+		expr = body.String() + "$"
+	default:
+		expr = "^" + body.String() + "(?:" + alternation(inflections[language]) + ")?$"
+	}
+	return regexp.Compile(expr)
+}
+
+// alternation собирает варианты в группу регулярного выражения
+func alternation(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = regexp.QuoteMeta(value)
+	}
+	return strings.Join(quoted, "|")
+}
+
+// isCyrillicWord проверяет, что в слове есть кириллица
+func isCyrillicWord(word string) bool {
+	for _, r := range word {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return true
+		}
+	}
+	return false
+}
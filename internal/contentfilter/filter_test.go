@@ -0,0 +1,197 @@
+package contentfilter
+
+import (
+	"strings"
+	"testing"
+)
+
+// newDefaultFilter создает фильтр со встроенными словарями
+func newDefaultFilter(t *testing.T) *WordListFilter {
+	t.Helper()
+	filter, err := NewWordListFilter(DefaultWordLists()...)
+	if err != nil {
+		t.Fatalf("NewWordListFilter() unexpected error: %v", err)
+	}
+	return filter
+}
+
+func TestWordListFilterMatches(t *testing.T) {
+	filter := newDefaultFilter(t)
+
+	tests := []struct {
+		name     string
+		text     string
+		wantTerm string
+		wantWord string
+	}{
+		// Морфология
+		{name: "ru whole word", text: "Ну ты и сука", wantTerm: "сука", wantWord: "сука"},
+		{name: "ru stem with ending", text: "Одни мудаки вокруг", wantTerm: "мудак*", wantWord: "мудаки"},
+		{name: "ru verb prefix", text: "Как же ты заебал", wantTerm: "еб*", wantWord: "заебал"},
+		{name: "ru verb prefix o", text: "Можно охуеть", wantTerm: "хуе*", wantWord: "охуеть"},
+		{name: "ru root inside word", text: "Полный распиздяй", wantTerm: "*пизд*", wantWord: "распиздяй"},
+		{name: "ru upper case and yo", text: "ПИЗДЁЖ", wantTerm: "*пизд*", wantWord: "ПИЗДЁЖ"},
+		{name: "ru repeated letters", text: "бляяяя", wantTerm: "бля*", wantWord: "бляяяя"},
+		{name: "en inflection", text: "Stupid bitches", wantTerm: "bitch*", wantWord: "bitches"},
+		{name: "en whole word with es", text: "Kick their asses", wantTerm: "ass", wantWord: "asses"},
+		{name: "en root inside word", text: "motherfucker", wantTerm: "*fuck*", wantWord: "motherfucker"},
+		{name: "en repeated letters", text: "FUUUUCK", wantTerm: "*fuck*", wantWord: "FUUUUCK"},
+		{name: "en trailing punctuation", text: "Oh shit!", wantTerm: "shit*", wantWord: "shit"},
+
+		// Leetspeak и похожие буквы
+		{name: "ru digit for letter", text: "6ля, опять", wantTerm: "бля*", wantWord: "6ля"},
+		{name: "ru digit for z", text: "3аебал уже", wantTerm: "еб*", wantWord: "3аебал"},
+		{name: "ru latin lookalike", text: "иди на xуй", wantTerm: "хуй*", wantWord: "xуй"},
+		{name: "en digit for letter", text: "holy sh1t", wantTerm: "shit*", wantWord: "sh1t"},
+		{name: "en symbol for letter", text: "$hit happens", wantTerm: "shit*", wantWord: "$hit"},
+		{name: "en at sign", text: "what an @ss", wantTerm: "ass", wantWord: "@ss"},
+		{name: "en cyrillic lookalike", text: "fuсk", wantTerm: "*fuck*", wantWord: "fuсk"},
+		{name: "en spelled out", text: "f u c k this", wantTerm: "*fuck*", wantWord: "fuck"},
+		{name: "ru spelled out", text: "с.у.к.а", wantTerm: "сука", wantWord: "сука"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := filter.Check(tt.text)
+			if len(matches) != 1 {
+				t.Fatalf("Check(%q) = %+v, want one match of %s", tt.text, matches, tt.wantTerm)
+			}
+			if matches[0].Term != tt.wantTerm || matches[0].Word != tt.wantWord {
+				t.Errorf("Check(%q) matched %q by %s, want %q by %s",
+					tt.text, matches[0].Word, matches[0].Term, tt.wantWord, tt.wantTerm)
+			}
+		})
+	}
+}
+
+func TestWordListFilterFalsePositives(t *testing.T) {
+	filter := newDefaultFilter(t)
+
+	texts := []string{
+		// Русские слова, содержащие основы словаря
+		"Посмотри на себя",
+		"Запишись на вебинар",
+		"Чистое небо",
+		"Что на обед?",
+		"Ребенок играет",
+		"Потребитель всегда прав",
+		"Академическая гребля",
+		"Сукно для стола",
+		"Страховка и скипидар",
+		// Английские слова, содержащие короткие шаблоны
+		"Class assistant in Scunthorpe",
+		"A classic passage to assess",
+		"Order a cocktail at Hancock's",
+		"Read Dickens and slice a cucumber",
+		"Document the shuttle schedule",
+		// Без букв
+		"123 456 !!! $$$",
+		"",
+	}
+
+	for _, text := range texts {
+		if matches := filter.Check(text); len(matches) > 0 {
+			t.Errorf("Check(%q) = %+v, want no matches", text, matches)
+		}
+	}
+}
+
+func TestWordListFilterReportsTermOnce(t *testing.T) {
+	filter := newDefaultFilter(t)
+	matches := filter.Check("сука, сука, суки и shit")
+	var terms []string
+	for _, match := range matches {
+		terms = append(terms, match.Term)
+	}
+	if got, want := strings.Join(terms, ","), "сука,суки,shit*"; got != want {
+		t.Errorf("Check() terms = %s, want %s", got, want)
+	}
+}
+
+func TestWordListFilterWithoutLists(t *testing.T) {
+	filter, err := NewWordListFilter()
+	if err != nil {
+		t.Fatalf("NewWordListFilter() unexpected error: %v", err)
+	}
+	if matches := filter.Check("fuck"); matches != nil {
+		t.Errorf("empty filter matched %+v", matches)
+	}
+}
+
+func TestParseWordList(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantTerms    []string
+		wantLanguage Language
+	}{
+		{
+			name:         "english with comments",
+			input:        "# custom words\nfoo*\n\n  *bar*  # inline comment\nbaz\n",
+			wantTerms:    []string{"foo*", "*bar*", "baz"},
+			wantLanguage: LanguageEnglish,
+		},
+		{
+			name:         "mostly russian",
+			input:        "слово*\nкорень\nword\n",
+			wantTerms:    []string{"слово*", "корень", "word"},
+			wantLanguage: LanguageRussian,
+		},
+		{
+			name:         "half russian stays english",
+			input:        "слово\nword\n",
+			wantTerms:    []string{"слово", "word"},
+			wantLanguage: LanguageEnglish,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := ParseWordList("custom", strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseWordList() unexpected error: %v", err)
+			}
+			if strings.Join(list.Terms, "|") != strings.Join(tt.wantTerms, "|") {
+				t.Errorf("terms = %q, want %q", list.Terms, tt.wantTerms)
+			}
+			if list.Language != tt.wantLanguage {
+				t.Errorf("language = %s, want %s", list.Language, tt.wantLanguage)
+			}
+		})
+	}
+}
+
+func TestCustomWordListMorphology(t *testing.T) {
+	filter, err := NewWordListFilter(
+		WordList{Name: "custom.en", Language: LanguageEnglish, Terms: []string{"darn", "heck*", "*bloop*"}},
+		WordList{Name: "custom.ru", Language: LanguageRussian, Terms: []string{"дурак", "вред*"}},
+	)
+	if err != nil {
+		t.Fatalf("NewWordListFilter() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"darn", true},
+		{"darned", true},
+		{"darning", true},
+		{"darnit", false}, // Шаблон целого слова допускает только окончания
+		{"hecking", true},
+		{"checking", false}, // Основа должна стоять в начале слова
+		{"kabloopy", true},
+		{"дураки", true},
+		{"дураком", true},
+		{"дурачок", false},
+		{"навредил", true}, // Русская приставка перед основой
+		{"вредный", true},
+		{"свредный", false}, // Однобуквенные приставки не допускаются
+	}
+
+	for _, tt := range tests {
+		if got := len(filter.Check(tt.text)) > 0; got != tt.want {
+			t.Errorf("Check(%q) matched = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package contentfilter
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minSpelledLetters - сколько одиночных букв подряд считаются словом, написанным по буквам (f u c k)
+const minSpelledLetters = 3
+
+// leetSymbols - символы, которыми заменяют буквы внутри слова ($hit, @ss)
+const leetSymbols = "@$!|"
+
+// Латинские буквы, похожие на кириллические, в словах на кириллице (xуй, bля)
+var latinToCyrillic = map[rune]rune{
+	'a': 'а', 'b': 'б', 'c': 'с', 'e': 'е', 'h': 'н', 'k': 'к', 'm': 'м', 'n': 'п',
+	'o': 'о', 'p': 'р', 'r': 'г', 't': 'т', 'u': 'и', 'x': 'х', 'y': 'у',
+}
+
+// Кириллические буквы, похожие на латинские, в словах на латинице (fuсk)
+var cyrillicToLatin = map[rune]rune{
+	'а': 'a', 'в': 'b', 'е': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p',
+	'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'ь': 'b', 'і': 'i',
+}
+
+// Leetspeak для слов на кириллице (6ля, 3аебал) и на латинице (sh1t, $hit)
+var (
+	cyrillicLeet = map[rune]rune{'0': 'о', '3': 'з', '4': 'ч', '6': 'б', '8': 'в', '9': 'я', '@': 'а', '$': 'с'}
+	latinLeet    = map[rune]rune{
+		'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b', '9': 'g',
+		'@': 'a', '$': 's', '!': 'i', '|': 'i',
+	}
+)
+
+// splitWords разбивает текст на слова. Символы leetspeak считаются частью слова,
+// а слова, написанные по одной букве через пробел или точку, склеиваются.
+func splitWords(text string) []string {
+	tokens := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(leetSymbols, r)
+	})
+
+	words := make([]string, 0, len(tokens))
+	var spelled []string
+	flush := func() {
+		if len(spelled) >= minSpelledLetters {
+			words = append(words, strings.Join(spelled, ""))
+		}
+		spelled = spelled[:0]
+	}
+	for _, token := range tokens {
+		// ! и | в конце слова - это пунктуация, а не замена буквы
+		token = strings.TrimRight(token, "!|")
+		if token == "" {
+			continue
+		}
+		words = append(words, token)
+		if utf8.RuneCountInString(token) == 1 {
+			spelled = append(spelled, token)
+		} else {
+			flush()
+		}
+	}
+	flush()
+	return words
+}
+
+// normalizeWord приводит слово к виду, с которым сравниваются шаблоны словарей:
+// нижний регистр, ё как е, leetspeak и похожие буквы другого алфавита заменены.
+// Алфавит слова определяется по большинству букв. Слово без букв дает пустую строку.
+func normalizeWord(word string) string {
+	word = normalizeTerm(word)
+
+	cyrillic, latin := 0, 0
+	for _, r := range word {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	if cyrillic == 0 && latin == 0 {
+		return ""
+	}
+
+	lookalikes, leet := cyrillicToLatin, latinLeet
+	if cyrillic >= latin {
+		lookalikes, leet = latinToCyrillic, cyrillicLeet
+	}
+
+	var b strings.Builder
+	b.Grow(len(word))
+	for _, r := range word {
+		if mapped, ok := lookalikes[r]; ok {
+			r = mapped
+		} else if mapped, ok := leet[r]; ok {
+			r = mapped
+		}
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// normalizeTerm приводит шаблон словаря к нижнему регистру и заменяет ё на е
+func normalizeTerm(term string) string {
+	return strings.ReplaceAll(strings.ToLower(term), "ё", "е")
+}
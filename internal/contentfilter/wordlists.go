@@ -0,0 +1,50 @@
+package contentfilter
+
+// DefaultWordLists возвращает встроенные словари нецензурной лексики и оскорблений.
+// Словари намеренно небольшие: они покрывают основные корни, а формы слов
+// находятся за счет шаблонов. Дополнительные словари подключаются через конфигурацию.
+func DefaultWordLists() []WordList {
+	return []WordList{
+		{
+			Name:     "default.ru",
+			Language: LanguageRussian,
+			Terms: []string{
+				"хуй*", "хуе*", "хуя*", "хуи*", "хую*",
+				"*пизд*",
+				"еб*",
+				"бля*",
+				"сука", "суки", "суке", "суку", "сукой", "сучка*", "сучар*",
+				"мудак*", "мудил*", "мудозвон*",
+				"гандон*", "гондон*",
+				"залуп*",
+				"дроч*",
+				"шлюх*",
+				"пидор*", "пидар*", "пидр*",
+				"манда", "манды", "мандой",
+				"трахн*", "трахат*", "трахал*",
+				"говн*", "дерьм*",
+				"жоп*",
+				"сран*",
+			},
+		},
+		{
+			Name:     "default.en",
+			Language: LanguageEnglish,
+			Terms: []string{
+				"*fuck*",
+				"shit*", "bullshit*",
+				"bitch*",
+				"cunt*",
+				"cock", "dick", "dickhead*",
+				"pussy", "pussies",
+				"ass", "asshole*", "arsehole*",
+				"bastard*",
+				"whore*", "slut*",
+				"wank*", "twat*",
+				"nigger*", "nigga*", "faggot*", "fag", "retard",
+				"porn*", "dildo*", "blowjob*", "handjob*",
+				"cum", "cumming",
+			},
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// analyticsDateLayout - формат дат в параметрах и ответе аналитики
+	analyticsDateLayout = "2006-01-02"
+	// defaultAnalyticsDays - длина периода по умолчанию
+	defaultAnalyticsDays = 30
+)
+
+// AnalyticsHandler обрабатывает запросы статистики партий
+type AnalyticsHandler struct {
+	analyticsService services.AnalyticsService
+}
+
+// NewAnalyticsHandler создает новый обработчик статистики партий
+func NewAnalyticsHandler(analyticsService services.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetCollectionAnalytics обрабатывает запрос владельца на статистику коллекции по дням.
+// Параметры from и to (YYYY-MM-DD) необязательны, по умолчанию - последние 30 дней.
+func (h *AnalyticsHandler) GetCollectionAnalytics(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid collection ID"})
+		return
+	}
+
+	to := time.Now().UTC()
+	if value := c.Query("to"); value != "" {
+		if to, err = time.Parse(analyticsDateLayout, value); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid 'to' date, expected YYYY-MM-DD"})
+			return
+		}
+	}
+	from := to.AddDate(0, 0, -(defaultAnalyticsDays - 1))
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse(analyticsDateLayout, value); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid 'from' date, expected YYYY-MM-DD"})
+			return
+		}
+	}
+
+	analytics, err := h.analyticsService.GetCollectionAnalytics(uint(id), userID, from, to)
+	if err != nil {
+		switch err {
+		case services.ErrCollectionNotFound:
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
+		case services.ErrNotCollectionOwner:
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "You are not the owner of this collection"})
+		case services.ErrInvalidAnalyticsRange:
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid date range, maximum is 366 days"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get analytics"})
+		}
+		return
+	}
+
+	days := make([]DailyPlayStatsResponse, 0, len(analytics.Days))
+	for _, day := range analytics.Days {
+		days = append(days, DailyPlayStatsResponse{
+			Date:                   day.Day.Format(analyticsDateLayout),
+			Plays:                  day.Plays,
+			CompletedPlays:         day.CompletedPlays,
+			AnonymousPlays:         day.AnonymousPlays,
+			UniqueUsers:            day.UniqueUsers,
+			CardsDrawn:             day.CardsDrawn,
+			AverageDurationSeconds: day.AverageDurationSeconds,
+		})
+	}
+
+	c.JSON(http.StatusOK, CollectionAnalyticsResponse{
+		CollectionID:           analytics.CollectionID,
+		From:                   analytics.From.Format(analyticsDateLayout),
+		To:                     analytics.To.Format(analyticsDateLayout),
+		TotalPlays:             analytics.TotalPlays,
+		CompletedPlays:         analytics.CompletedPlays,
+		AnonymousPlays:         analytics.AnonymousPlays,
+		UniqueUsers:            analytics.UniqueUsers,
+		CardsDrawn:             analytics.CardsDrawn,
+		AverageDurationSeconds: analytics.AverageDurationSeconds,
+		CompletionRate:         analytics.CompletionRate,
+		AllTimePlayCount:       analytics.AllTimePlayCount,
+		Days:                   days,
+	})
+}
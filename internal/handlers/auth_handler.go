@@ -31,6 +31,12 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	birthDate, err := parseBirthDate(req.BirthDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
 	// Создаем нового пользователя
 	user := &models.User{
 		Name:      req.Name,
@@ -38,6 +44,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		Email:     req.Email,
 		Password:  req.Password,
 		Phone:     req.Phone,
+		BirthDate: birthDate,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -48,6 +55,10 @@ func (h *AuthHandler) Register(c *gin.Context) {
 			c.JSON(http.StatusConflict, ErrorResponse{Error: "Email already exists"})
 			return
 		}
+		if err == services.ErrInvalidBirthDate {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid birth date"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create user"})
 		return
 	}
@@ -82,6 +93,10 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid credentials"})
 			return
 		}
+		if err == services.ErrUserSuspended {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "User is suspended"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Authentication failed"})
 		return
 	}
@@ -122,6 +137,10 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "User not found"})
 		return
 	}
+	if user.IsSuspended(time.Now()) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "User is suspended"})
+		return
+	}
 
 	// Генерируем новые токены
 	td, err := h.authService.CreateToken(user)
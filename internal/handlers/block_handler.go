@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// BlockHandler обрабатывает запросы, связанные с блокировкой пользователей
+type BlockHandler struct {
+	blockService services.BlockService
+}
+
+// NewBlockHandler создает новый обработчик блокировок
+func NewBlockHandler(blockService services.BlockService) *BlockHandler {
+	return &BlockHandler{
+		blockService: blockService,
+	}
+}
+
+// Block обрабатывает запрос на блокировку пользователя (повторный запрос ничего не меняет)
+func (h *BlockHandler) Block(c *gin.Context) {
+	h.change(c, h.blockService.Block, true)
+}
+
+// Unblock обрабатывает запрос на снятие блокировки (повторный запрос ничего не меняет)
+func (h *BlockHandler) Unblock(c *gin.Context) {
+	h.change(c, h.blockService.Unblock, false)
+}
+
+// GetBlocked обрабатывает запрос на получение пользователей, заблокированных текущим пользователем
+func (h *BlockHandler) GetBlocked(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	page, size := parsePagination(c)
+	users, total, err := h.blockService.GetBlocked(userID, page, size)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	items := make([]PublicUserResponse, 0, len(users))
+	for _, user := range users {
+		items = append(items, newPublicUserResponse(user))
+	}
+
+	c.JSON(http.StatusOK, UserListResponse{
+		Total: total,
+		Page:  page,
+		Size:  size,
+		Items: items,
+	})
+}
+
+// change выполняет операцию над блокировкой пользователя из пути запроса
+func (h *BlockHandler) change(c *gin.Context, operation func(blockerID, blockedID uint) error, blocked bool) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	if err := operation(userID, id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, BlockResponse{
+		UserID:  id,
+		Blocked: blocked,
+	})
+}
+
+// handleError преобразует ошибки сервиса блокировок в HTTP-ответы
+func (h *BlockHandler) handleError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrUserNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+	case services.ErrCannotBlockSelf:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "You cannot block yourself"})
+	case services.ErrInvalidUserID:
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process block request"})
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// CardFeedbackHandler обрабатывает голоса за карточки
+type CardFeedbackHandler struct {
+	feedbackService services.CardFeedbackService
+}
+
+// NewCardFeedbackHandler создает новый обработчик голосов за карточки
+func NewCardFeedbackHandler(feedbackService services.CardFeedbackService) *CardFeedbackHandler {
+	return &CardFeedbackHandler{
+		feedbackService: feedbackService,
+	}
+}
+
+// Vote обрабатывает голос за карточку ({"value": 1} или {"value": -1}); повторный голос заменяет предыдущий
+func (h *CardFeedbackHandler) Vote(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	actionID, ok := parseIDParam(c, "id", "Invalid action ID")
+	if !ok {
+		return
+	}
+
+	var req CardVoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	quality, err := h.feedbackService.Vote(userID, actionID, req.Value)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCardQualityResponse(nil, quality))
+}
+
+// RemoveVote обрабатывает отзыв голоса за карточку
+func (h *CardFeedbackHandler) RemoveVote(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	actionID, ok := parseIDParam(c, "id", "Invalid action ID")
+	if !ok {
+		return
+	}
+
+	quality, err := h.feedbackService.RemoveVote(userID, actionID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCardQualityResponse(nil, quality))
+}
+
+// handleError преобразует ошибки сервиса обратной связи в HTTP-ответ
+func (h *CardFeedbackHandler) handleError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrActionNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Action not found"})
+	case services.ErrInvalidVote:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case services.ErrInvalidUserID:
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to save vote"})
+	}
+}
+
+// newCardQualityResponse преобразует обратную связь по карточке в ответ (action может быть nil)
+func newCardQualityResponse(action *models.Action, quality *models.CardQuality) CardQualityResponse {
+	response := CardQualityResponse{
+		ActionID:  quality.ActionID,
+		Upvotes:   quality.Upvotes,
+		Downvotes: quality.Downvotes,
+		Draws:     quality.Draws,
+		Completed: quality.Completed,
+		Skipped:   quality.Skipped,
+		SkipRate:  quality.SkipRate(),
+		Score:     quality.Score(),
+	}
+	if action != nil {
+		response.Type = string(action.Type)
+		response.Text = action.Text
+	}
+	return response
+}
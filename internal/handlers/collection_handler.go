@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -13,12 +15,20 @@ import (
 // CollectionHandler обрабатывает запросы, связанные с коллекциями
 type CollectionHandler struct {
 	collectionService services.CollectionService
+	likeService       services.LikeService
+	feedbackService   services.CardFeedbackService
 }
 
 // NewCollectionHandler создает новый обработчик коллекций
-func NewCollectionHandler(collectionService services.CollectionService) *CollectionHandler {
+func NewCollectionHandler(
+	collectionService services.CollectionService,
+	likeService services.LikeService,
+	feedbackService services.CardFeedbackService,
+) *CollectionHandler {
 	return &CollectionHandler{
 		collectionService: collectionService,
+		likeService:       likeService,
+		feedbackService:   feedbackService,
 	}
 }
 
@@ -33,7 +43,7 @@ func (h *CollectionHandler) GetActions(c *gin.Context) {
 	collectionID := uint(id)
 
 	// Сначала проверяем, существует ли коллекция
-	_, err = h.collectionService.GetByID(collectionID)
+	collection, err := h.collectionService.GetByID(collectionID)
 	if err != nil {
 		if err == services.ErrCollectionNotFound {
 			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
@@ -42,10 +52,24 @@ func (h *CollectionHandler) GetActions(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to verify collection"})
 		return
 	}
+	if !canViewCollection(c, collection) {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
+		return
+	}
+
+	filter, err := parseActionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
 
 	// Затем получаем действия
-	actions, err := h.collectionService.GetActions(collectionID)
+	actions, err := h.collectionService.FilterActions(collectionID, filter)
 	if err != nil {
+		if errors.Is(err, services.ErrMixSourceNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get actions"})
 		return
 	}
@@ -53,13 +77,7 @@ func (h *CollectionHandler) GetActions(c *gin.Context) {
 	// Преобразуем действия в ответ с типами
 	items := make([]ActionResponseWithType, 0, len(actions))
 	for _, action := range actions {
-		actionResponse := ActionResponseWithType{
-			ID:    action.ID,
-			Text:  action.Text,
-			Type:  string(action.Type), // Убеждаемся что Type корректно преобразуется в строку
-			Order: action.Order,
-		}
-		items = append(items, actionResponse)
+		items = append(items, newActionResponse(action))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -84,33 +102,27 @@ func (h *CollectionHandler) GetByID(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get collection"})
 		return
 	}
+	if !canViewCollection(c, collection) {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
+		return
+	}
 
 	// Преобразуем коллекцию в ответ
 	actions := make([]ActionResponseWithType, 0, len(collection.Actions))
 	for _, action := range collection.Actions {
-		actions = append(actions, ActionResponseWithType{
-			ID:    action.ID,
-			Text:  action.Text,
-			Type:  string(action.Type),
-			Order: action.Order,
-		})
+		actions = append(actions, newActionResponse(action))
 	}
 
-	response := CollectionResponse{
-		ID:          collection.ID,
-		Name:        collection.Name,
-		Description: collection.Description,
-		ImageURL:    collection.ImageURL,
-		UserID:      collection.UserID,
-		PlayCount:   collection.PlayCount,
-		Actions:     actions,
-		CreatedAt:   collection.CreatedAt.Format("2006-01-02T15:04:05Z"),
-	}
+	response := newCollectionResponse(collection)
+	response.Actions = actions
 
-	c.JSON(http.StatusOK, response)
+	responses := []CollectionResponse{response}
+	applyReactions(c, h.likeService, responses)
+
+	c.JSON(http.StatusOK, responses[0])
 }
 
-// GetTrending обрабатывает запрос на получение популярных коллекций
+// GetTrending обрабатывает запрос на получение популярных коллекций за период (?window=day|week|month|all)
 func (h *CollectionHandler) GetTrending(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "10")
 	limit, err := strconv.Atoi(limitStr)
@@ -118,8 +130,13 @@ func (h *CollectionHandler) GetTrending(c *gin.Context) {
 		limit = 10
 	}
 
-	collections, err := h.collectionService.GetTrending(limit)
+	window := models.TrendingWindow(c.DefaultQuery("window", string(models.TrendingWindowWeek)))
+	collections, err := h.collectionService.GetTrending(window, limit, collectionVisibility(c))
 	if err != nil {
+		if err == services.ErrInvalidTrendingWindow {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid window, expected day, week, month or all"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get trending collections"})
 		return
 	}
@@ -127,16 +144,9 @@ func (h *CollectionHandler) GetTrending(c *gin.Context) {
 	// Преобразуем коллекции в ответ
 	items := make([]CollectionResponse, 0, len(collections))
 	for _, collection := range collections {
-		items = append(items, CollectionResponse{
-			ID:          collection.ID,
-			Name:        collection.Name,
-			Description: collection.Description,
-			ImageURL:    collection.ImageURL,
-			UserID:      collection.UserID,
-			PlayCount:   collection.PlayCount,
-			CreatedAt:   collection.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		})
+		items = append(items, newCollectionResponse(collection))
 	}
+	applyReactions(c, h.likeService, items)
 
 	c.JSON(http.StatusOK, gin.H{
 		"items": items,
@@ -158,13 +168,18 @@ func (h *CollectionHandler) Create(c *gin.Context) {
 	}
 
 	collection := &models.Collection{
-		Name:        req.Name,
-		Description: req.Description,
-		ImageURL:    req.ImageURL,
-		UserID:      userID,
+		Name:              req.Name,
+		Description:       req.Description,
+		ImageURL:          req.ImageURL,
+		UserID:            userID,
+		DeclaredAgeRating: req.AgeRating,
 	}
 
 	if err := h.collectionService.Create(collection); err != nil {
+		if errors.Is(err, services.ErrContentRejected) || err == services.ErrInvalidAgeRating {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create collection"})
 		return
 	}
@@ -188,41 +203,47 @@ func (h *CollectionHandler) CreateWithActions(c *gin.Context) {
 
 	// Создаем коллекцию
 	collection := &models.Collection{
-		Name:        req.Name,
-		Description: req.Description,
-		ImageURL:    req.ImageURL,
-		UserID:      userID,
+		Name:              req.Name,
+		Description:       req.Description,
+		ImageURL:          req.ImageURL,
+		UserID:            userID,
+		DeclaredAgeRating: req.AgeRating,
 	}
 
 	// Преобразуем действия из запроса в модель
 	var actions []*models.Action
 	for _, actionReq := range req.Actions {
-		// Валидируем тип действия
-		var actionType models.ActionType
-		switch actionReq.Type {
-		case "truth":
-			actionType = models.ActionTypeTruth
-		case "dare":
-			actionType = models.ActionTypeDare
-		default:
+		// Валидируем тип действия по реестру
+		actionType := models.ActionType(actionReq.Type)
+		if !models.IsValidActionType(actionType) {
 			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid action type: " + actionReq.Type})
 			return
 		}
 
 		action := &models.Action{
-			Text:  actionReq.Text,
-			Type:  actionType,
-			Order: actionReq.Order,
+			Text:         actionReq.Text,
+			Type:         actionType,
+			Extra:        actionReq.Extra,
+			Order:        actionReq.Order,
+			Difficulty:   actionReq.Difficulty,
+			Spiciness:    actionReq.Spiciness,
+			TimerSeconds: actionReq.TimerSeconds,
+			Points:       actionReq.Points,
 		}
 		actions = append(actions, action)
 	}
 
 	// Создаем коллекцию с действиями
 	if err := h.collectionService.CreateWithActions(collection, actions); err != nil {
-		if err == services.ErrInvalidActionType {
+		if errors.Is(err, services.ErrInvalidActionType) {
 			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid action type"})
 			return
 		}
+		if errors.Is(err, services.ErrInvalidActionData) || errors.Is(err, services.ErrInvalidActionTemplate) ||
+			errors.Is(err, services.ErrContentRejected) || err == services.ErrInvalidAgeRating {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create collection with actions"})
 		return
 	}
@@ -230,15 +251,19 @@ func (h *CollectionHandler) CreateWithActions(c *gin.Context) {
 	c.JSON(http.StatusCreated, SuccessResponse{Message: "Collection with actions created successfully"})
 }
 
-// GetCollectionStats возвращает статистику коллекции
+// GetCollectionStats возвращает статистику коллекции. Владельцу коллекции
+// дополнительно возвращается отчет о качестве карточек.
 func (h *CollectionHandler) GetCollectionStats(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid collection ID"})
 		return
 	}
+	if _, ok := h.viewableCollection(c, uint(id)); !ok {
+		return
+	}
 
-	truthCount, dareCount, total, err := h.collectionService.GetActionCounts(uint(id))
+	stats, err := h.collectionService.GetStats(uint(id))
 	if err != nil {
 		if err == services.ErrCollectionNotFound {
 			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
@@ -248,13 +273,40 @@ func (h *CollectionHandler) GetCollectionStats(c *gin.Context) {
 		return
 	}
 
-	stats := CollectionStatsResponse{
-		TotalActions: total,
-		TruthCount:   truthCount,
-		DareCount:    dareCount,
+	typeCounts := make(map[string]int, len(stats.TypeCounts))
+	for actionType, count := range stats.TypeCounts {
+		typeCounts[string(actionType)] = count
 	}
 
-	c.JSON(http.StatusOK, stats)
+	response := CollectionStatsResponse{
+		TotalActions:           stats.TotalActions,
+		TruthCount:             stats.TruthCount,
+		DareCount:              stats.DareCount,
+		TypeCounts:             typeCounts,
+		DifficultyDistribution: stats.DifficultyCounts,
+		SpicinessDistribution:  stats.SpicinessCounts,
+		TimedActions:           stats.TimedActions,
+		AverageTimerSeconds:    stats.AverageTimerSeconds,
+		TotalPoints:            stats.TotalPoints,
+	}
+
+	if userID := middleware.GetOptionalUserID(c); userID != nil {
+		report, err := h.feedbackService.GetCollectionQuality(uint(id), *userID)
+		switch err {
+		case nil:
+			response.CardQuality = make([]CardQualityResponse, 0, len(report))
+			for _, item := range report {
+				response.CardQuality = append(response.CardQuality, newCardQualityResponse(item.Action, item.Quality))
+			}
+		case services.ErrNotCollectionOwner:
+			// Чужая коллекция: отчет о качестве не показываем
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get card quality"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // GetUserCollections обрабатывает запрос на получение коллекций пользователя
@@ -274,39 +326,28 @@ func (h *CollectionHandler) GetUserCollections(c *gin.Context) {
 	// Преобразуем коллекции в ответ
 	items := make([]CollectionResponse, 0, len(collections))
 	for _, collection := range collections {
-		items = append(items, CollectionResponse{
-			ID:          collection.ID,
-			Name:        collection.Name,
-			Description: collection.Description,
-			ImageURL:    collection.ImageURL,
-			UserID:      collection.UserID,
-			PlayCount:   collection.PlayCount,
-			CreatedAt:   collection.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		})
+		items = append(items, newCollectionResponse(collection))
 	}
+	applyReactions(c, h.likeService, items)
 
 	c.JSON(http.StatusOK, gin.H{
 		"items": items,
 	})
 }
 
-// List обрабатывает запрос на получение списка коллекций
+// List обрабатывает запрос на получение списка коллекций (?sort=newest|popular|rating).
+// В семейном режиме и для несовершеннолетних взрослые коллекции не показываются,
+// коллекции заблокированных пользователем авторов тоже.
 func (h *CollectionHandler) List(c *gin.Context) {
-	pageStr := c.DefaultQuery("page", "1")
-	sizeStr := c.DefaultQuery("size", "10")
-
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		page = 1
-	}
+	page, size := parsePagination(c)
+	sort := models.CollectionSort(c.Query("sort"))
 
-	size, err := strconv.Atoi(sizeStr)
-	if err != nil || size < 1 || size > 100 {
-		size = 10
-	}
-
-	collections, total, err := h.collectionService.List(page, size)
+	collections, total, err := h.collectionService.List(page, size, sort, collectionVisibility(c))
 	if err != nil {
+		if err == services.ErrInvalidCollectionSort {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid sort, expected newest, popular or rating"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get collections"})
 		return
 	}
@@ -314,16 +355,9 @@ func (h *CollectionHandler) List(c *gin.Context) {
 	// Преобразуем коллекции в ответ
 	items := make([]CollectionResponse, 0, len(collections))
 	for _, collection := range collections {
-		items = append(items, CollectionResponse{
-			ID:          collection.ID,
-			Name:        collection.Name,
-			Description: collection.Description,
-			ImageURL:    collection.ImageURL,
-			UserID:      collection.UserID,
-			PlayCount:   collection.PlayCount,
-			CreatedAt:   collection.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		})
+		items = append(items, newCollectionResponse(collection))
 	}
+	applyReactions(c, h.likeService, items)
 
 	c.JSON(http.StatusOK, PaginationResponse{
 		Total: total,
@@ -333,6 +367,21 @@ func (h *CollectionHandler) List(c *gin.Context) {
 	})
 }
 
+// parsePagination разбирает параметры page и size (по умолчанию 1 и 10, size не больше 100)
+func parsePagination(c *gin.Context) (int, int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	size, err := strconv.Atoi(c.DefaultQuery("size", "10"))
+	if err != nil || size < 1 || size > 100 {
+		size = 10
+	}
+
+	return page, size
+}
+
 // Update обрабатывает запрос на обновление коллекции
 func (h *CollectionHandler) Update(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -354,10 +403,11 @@ func (h *CollectionHandler) Update(c *gin.Context) {
 	}
 
 	collection := &models.Collection{
-		ID:          uint(id),
-		Name:        req.Name,
-		Description: req.Description,
-		ImageURL:    req.ImageURL,
+		ID:                uint(id),
+		Name:              req.Name,
+		Description:       req.Description,
+		ImageURL:          req.ImageURL,
+		DeclaredAgeRating: req.AgeRating,
 	}
 
 	if err := h.collectionService.Update(collection, userID); err != nil {
@@ -369,6 +419,14 @@ func (h *CollectionHandler) Update(c *gin.Context) {
 			c.JSON(http.StatusForbidden, ErrorResponse{Error: "You are not the owner of this collection"})
 			return
 		}
+		if errors.Is(err, services.ErrContentRejected) || err == services.ErrInvalidAgeRating {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrMixSourceNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update collection"})
 		return
 	}
@@ -426,22 +484,22 @@ func (h *CollectionHandler) AddAction(c *gin.Context) {
 		return
 	}
 
-	// Валидируем тип действия
-	var actionType models.ActionType
-	switch req.Type {
-	case "truth":
-		actionType = models.ActionTypeTruth
-	case "dare":
-		actionType = models.ActionTypeDare
-	default:
+	// Валидируем тип действия по реестру
+	actionType := models.ActionType(req.Type)
+	if !models.IsValidActionType(actionType) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid action type: " + req.Type})
 		return
 	}
 
 	action := &models.Action{
-		Text:  req.Text,
-		Type:  actionType,
-		Order: req.Order,
+		Text:         req.Text,
+		Type:         actionType,
+		Extra:        req.Extra,
+		Order:        req.Order,
+		Difficulty:   req.Difficulty,
+		Spiciness:    req.Spiciness,
+		TimerSeconds: req.TimerSeconds,
+		Points:       req.Points,
 	}
 
 	if err := h.collectionService.AddAction(uint(id), action); err != nil {
@@ -449,10 +507,19 @@ func (h *CollectionHandler) AddAction(c *gin.Context) {
 			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
 			return
 		}
-		if err == services.ErrInvalidActionType {
+		if errors.Is(err, services.ErrInvalidActionType) {
 			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid action type"})
 			return
 		}
+		if errors.Is(err, services.ErrInvalidActionData) || errors.Is(err, services.ErrInvalidActionTemplate) ||
+			errors.Is(err, services.ErrContentRejected) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if err == services.ErrVirtualCollection {
+			c.JSON(http.StatusConflict, ErrorResponse{Error: "Cards of a virtual collection cannot be edited"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to add action"})
 		return
 	}
@@ -490,12 +557,317 @@ func (h *CollectionHandler) RemoveAction(c *gin.Context) {
 	c.JSON(http.StatusOK, SuccessResponse{Message: "Action removed successfully"})
 }
 
+// BatchActions обрабатывает пакетный запрос на создание, изменение и удаление карточек коллекции
+func (h *CollectionHandler) BatchActions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid collection ID"})
+		return
+	}
+
+	var req BatchActionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	if len(req.Operations) > services.MaxActionBatchSize {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Too many operations in batch"})
+		return
+	}
+
+	// Преобразуем операции из запроса
+	ops := make([]services.ActionOperation, 0, len(req.Operations))
+	for _, opReq := range req.Operations {
+		ops = append(ops, services.ActionOperation{
+			Op:       services.ActionOperationType(opReq.Op),
+			ActionID: opReq.ID,
+			Action: &models.Action{
+				Text:         opReq.Text,
+				Type:         models.ActionType(opReq.Type),
+				Extra:        opReq.Extra,
+				Order:        opReq.Order,
+				Difficulty:   intValue(opReq.Difficulty),
+				Spiciness:    intValue(opReq.Spiciness),
+				TimerSeconds: intValue(opReq.TimerSeconds),
+				Points:       intValue(opReq.Points),
+			},
+			Metadata: services.ActionMetadataPatch{
+				Difficulty:   opReq.Difficulty,
+				Spiciness:    opReq.Spiciness,
+				TimerSeconds: opReq.TimerSeconds,
+				Points:       opReq.Points,
+			},
+		})
+	}
+
+	results, err := h.collectionService.ApplyActionBatch(uint(id), userID, ops)
+	if err != nil {
+		if err == services.ErrCollectionNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
+			return
+		}
+		if err == services.ErrNotCollectionOwner {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "You are not the owner of this collection"})
+			return
+		}
+		if err == services.ErrVirtualCollection {
+			c.JSON(http.StatusConflict, ErrorResponse{Error: "Cards of a virtual collection cannot be edited"})
+			return
+		}
+		if err == services.ErrInvalidActionBatch {
+			c.JSON(http.StatusBadRequest, BatchActionsResponse{
+				Error:   "Batch rejected due to validation errors",
+				Results: toBatchActionResults(results),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to apply actions batch"})
+		return
+	}
+
+	c.JSON(http.StatusOK, BatchActionsResponse{Results: toBatchActionResults(results)})
+}
+
+// toBatchActionResults преобразует результаты сервиса в ответ
+func toBatchActionResults(results []services.ActionOperationResult) []BatchActionResult {
+	items := make([]BatchActionResult, 0, len(results))
+	for _, result := range results {
+		items = append(items, BatchActionResult{
+			Index: result.Index,
+			Op:    string(result.Op),
+			ID:    result.ActionID,
+			Error: result.Error,
+		})
+	}
+	return items
+}
+
+// RenderActions возвращает карточки коллекции с подставленными именами игроков
+func (h *CollectionHandler) RenderActions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid collection ID"})
+		return
+	}
+
+	var req RenderActionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if _, ok := h.viewableCollection(c, uint(id)); !ok {
+		return
+	}
+
+	rendered, err := h.collectionService.RenderActions(uint(id), req.Players, req.CurrentPlayer, req.Seed)
+	if err != nil {
+		if err == services.ErrCollectionNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidPlayers) || errors.Is(err, services.ErrInvalidActionTemplate) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrMixSourceNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to render actions"})
+		return
+	}
+
+	items := make([]RenderedActionResponse, 0, len(rendered))
+	for _, item := range rendered {
+		items = append(items, RenderedActionResponse{
+			ID:           item.Action.ID,
+			Type:         string(item.Action.Type),
+			Text:         item.Text,
+			Template:     item.Action.Text,
+			Player:       item.Player,
+			Extra:        item.Extra,
+			Difficulty:   item.Action.Difficulty,
+			Spiciness:    item.Action.Spiciness,
+			TimerSeconds: item.Action.TimerSeconds,
+			Points:       item.Action.Points,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+	})
+}
+
+// GetActionTypes возвращает список поддерживаемых типов карточек
+func (h *CollectionHandler) GetActionTypes(c *gin.Context) {
+	defs := models.ActionTypes()
+
+	items := make([]ActionTypeResponse, 0, len(defs))
+	for _, def := range defs {
+		fields := make([]ActionTypeFieldResponse, 0, len(def.Fields))
+		for _, field := range def.Fields {
+			fields = append(fields, ActionTypeFieldResponse{
+				Name:        field.Name,
+				Kind:        string(field.Kind),
+				Required:    field.Required,
+				Description: field.Description,
+			})
+		}
+		items = append(items, ActionTypeResponse{
+			Type:        string(def.Type),
+			Name:        def.Name,
+			Description: def.Description,
+			Fields:      fields,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+	})
+}
+
+// canViewCollection проверяет, можно ли показать коллекцию в запросе: скрытую модератором
+// коллекцию и коллекцию с рейтингом выше допустимого (см. middleware.GetMaxAgeRating)
+// видит только ее владелец
+func canViewCollection(c *gin.Context, collection *models.Collection) bool {
+	return collectionVisibility(c).Allows(collection)
+}
+
+// viewableCollection загружает коллекцию и проверяет, что ее можно показать в запросе.
+// Если коллекцию показать нельзя, ответ уже отправлен и возвращается false.
+func (h *CollectionHandler) viewableCollection(c *gin.Context, id uint) (*models.Collection, bool) {
+	collection, err := h.collectionService.GetByID(id)
+	if err != nil {
+		if err == services.ErrCollectionNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get collection"})
+		return nil, false
+	}
+	if !canViewCollection(c, collection) {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
+		return nil, false
+	}
+	return collection, true
+}
+
+// collectionVisibility собирает ограничения публичных списков коллекций для запроса:
+// возрастной рейтинг и авторы, заблокированные текущим пользователем
+func collectionVisibility(c *gin.Context) models.CollectionVisibility {
+	return models.CollectionVisibility{
+		MaxAgeRating: middleware.GetMaxAgeRating(c),
+		ViewerID:     middleware.GetOptionalUserID(c),
+	}
+}
+
+// newCollectionResponse преобразует коллекцию в ответ (без карточек)
+func newCollectionResponse(collection *models.Collection) CollectionResponse {
+	return CollectionResponse{
+		ID:                collection.ID,
+		Name:              collection.Name,
+		Description:       collection.Description,
+		ImageURL:          collection.ImageURL,
+		UserID:            collection.UserID,
+		PlayCount:         collection.PlayCount,
+		LikeCount:         collection.LikeCount,
+		FavoriteCount:     collection.FavoriteCount,
+		Rating:            collection.BayesianRating(),
+		AverageRating:     collection.AverageRating(),
+		RatingCount:       collection.RatingCount,
+		IsVirtual:         collection.IsVirtual,
+		AgeRating:         collection.AgeRating,
+		DeclaredAgeRating: collection.DeclaredAgeRating,
+		CreatedAt:         collection.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// newActionResponse преобразует действие в ответ
+func newActionResponse(action *models.Action) ActionResponseWithType {
+	return ActionResponseWithType{
+		ID:           action.ID,
+		Text:         action.Text,
+		Type:         string(action.Type),
+		Extra:        action.Extra,
+		Order:        action.Order,
+		CollectionID: action.CollectionID,
+		Difficulty:   action.Difficulty,
+		Spiciness:    action.Spiciness,
+		TimerSeconds: action.TimerSeconds,
+		Points:       action.Points,
+		IsTemplate:   services.IsCardTemplate(action.Text),
+	}
+}
+
+// parseActionFilter разбирает параметры фильтрации карточек из строки запроса:
+// type, difficulty, minDifficulty, maxDifficulty, spiciness, minSpiciness, maxSpiciness, timed, minPoints
+func parseActionFilter(c *gin.Context) (models.ActionFilter, error) {
+	var filter models.ActionFilter
+
+	if t := c.Query("type"); t != "" {
+		if !models.IsValidActionType(models.ActionType(t)) {
+			return filter, fmt.Errorf("invalid action type: %s", t)
+		}
+		filter.Type = models.ActionType(t)
+	}
+
+	intParams := []struct {
+		name   string
+		assign func(v int)
+	}{
+		{"difficulty", func(v int) { filter.MinDifficulty, filter.MaxDifficulty = v, v }},
+		{"minDifficulty", func(v int) { filter.MinDifficulty = v }},
+		{"maxDifficulty", func(v int) { filter.MaxDifficulty = v }},
+		{"spiciness", func(v int) { filter.MinSpiciness, filter.MaxSpiciness = &v, &v }},
+		{"minSpiciness", func(v int) { filter.MinSpiciness = &v }},
+		{"maxSpiciness", func(v int) { filter.MaxSpiciness = &v }},
+		{"minPoints", func(v int) { filter.MinPoints = v }},
+	}
+	for _, param := range intParams {
+		raw := c.Query(param.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			return filter, fmt.Errorf("invalid %s: %s", param.name, raw)
+		}
+		param.assign(v)
+	}
+
+	if timed := c.Query("timed"); timed != "" {
+		v, err := strconv.ParseBool(timed)
+		if err != nil {
+			return filter, fmt.Errorf("invalid timed: %s", timed)
+		}
+		filter.TimedOnly = v
+	}
+
+	return filter, nil
+}
+
+// intValue возвращает значение указателя или 0
+func intValue(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
 // Collection response models
 
 type CollectionRequest struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description"`
 	ImageURL    string `json:"imageUrl"`
+	AgeRating   int    `json:"ageRating"` // Заявленный автором рейтинг: 0, 12, 16 или 18
 }
 
 type ActionRequest struct {
@@ -504,14 +876,24 @@ type ActionRequest struct {
 }
 
 type CollectionResponse struct {
-	ID          uint                     `json:"id"`
-	Name        string                   `json:"name"`
-	Description string                   `json:"description"`
-	ImageURL    string                   `json:"imageUrl"`
-	UserID      uint                     `json:"userId"`
-	PlayCount   int                      `json:"playCount"`
-	Actions     []ActionResponseWithType `json:"actions,omitempty"`
-	CreatedAt   string                   `json:"createdAt"`
+	ID                uint                     `json:"id"`
+	Name              string                   `json:"name"`
+	Description       string                   `json:"description"`
+	ImageURL          string                   `json:"imageUrl"`
+	UserID            uint                     `json:"userId"`
+	PlayCount         int                      `json:"playCount"`
+	LikeCount         int                      `json:"likeCount"`
+	FavoriteCount     int                      `json:"favoriteCount"`
+	LikedByMe         *bool                    `json:"likedByMe,omitempty"`     // Только для авторизованных запросов
+	FavoritedByMe     *bool                    `json:"favoritedByMe,omitempty"` // Только для авторизованных запросов
+	Rating            float64                  `json:"rating"`                  // Байесовское среднее, для сортировки и отображения
+	AverageRating     float64                  `json:"averageRating"`
+	RatingCount       int                      `json:"ratingCount"`
+	IsVirtual         bool                     `json:"isVirtual"`
+	AgeRating         int                      `json:"ageRating"`         // Итоговый рейтинг: 0, 12, 16 или 18
+	DeclaredAgeRating int                      `json:"declaredAgeRating"` // Рейтинг, заявленный автором
+	Actions           []ActionResponseWithType `json:"actions,omitempty"`
+	CreatedAt         string                   `json:"createdAt"`
 }
 
 type ActionResponse struct {
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// CommentHandler обрабатывает запросы, связанные с комментариями к коллекциям
+type CommentHandler struct {
+	commentService services.CommentService
+}
+
+// NewCommentHandler создает новый обработчик комментариев
+func NewCommentHandler(commentService services.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+// List обрабатывает запрос на получение веток комментариев коллекции (закрепленные первыми)
+func (h *CommentHandler) List(c *gin.Context) {
+	collectionID, ok := parseIDParam(c, "id", "Invalid collection ID")
+	if !ok {
+		return
+	}
+
+	page, size := parsePagination(c)
+	comments, total, err := h.commentService.ListThreads(collectionID, middleware.GetOptionalUserID(c), page, size)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCommentListResponse(comments, total, page, size))
+}
+
+// ListReplies обрабатывает запрос на получение ответов в ветке комментария
+func (h *CommentHandler) ListReplies(c *gin.Context) {
+	commentID, ok := parseIDParam(c, "id", "Invalid comment ID")
+	if !ok {
+		return
+	}
+
+	page, size := parsePagination(c)
+	comments, total, err := h.commentService.ListReplies(commentID, middleware.GetOptionalUserID(c), page, size)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCommentListResponse(comments, total, page, size))
+}
+
+// Create обрабатывает запрос на создание комментария или ответа (parentId)
+func (h *CommentHandler) Create(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	collectionID, ok := parseIDParam(c, "id", "Invalid collection ID")
+	if !ok {
+		return
+	}
+
+	var req CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	comment, err := h.commentService.Create(userID, collectionID, req.ParentID, services.CommentInput{
+		Text:     req.Text,
+		Mentions: req.Mentions,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newCommentResponse(comment))
+}
+
+// Update обрабатывает запрос автора на изменение комментария
+func (h *CommentHandler) Update(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	commentID, ok := parseIDParam(c, "id", "Invalid comment ID")
+	if !ok {
+		return
+	}
+
+	var req CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	comment, err := h.commentService.Update(userID, commentID, services.CommentInput{
+		Text:     req.Text,
+		Mentions: req.Mentions,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCommentResponse(comment))
+}
+
+// Delete обрабатывает запрос на удаление комментария автором или владельцем коллекции
+func (h *CommentHandler) Delete(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	commentID, ok := parseIDParam(c, "id", "Invalid comment ID")
+	if !ok {
+		return
+	}
+
+	if err := h.commentService.Delete(userID, commentID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
+
+// Pin обрабатывает запрос владельца коллекции на закрепление комментария ({"pinned": true|false})
+func (h *CommentHandler) Pin(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	commentID, ok := parseIDParam(c, "id", "Invalid comment ID")
+	if !ok {
+		return
+	}
+
+	var req PinCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	comment, err := h.commentService.SetPinned(userID, commentID, req.Pinned)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCommentResponse(comment))
+}
+
+// handleError преобразует ошибки сервиса комментариев в HTTP-ответ
+func (h *CommentHandler) handleError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrCollectionNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
+	case services.ErrCommentNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Comment not found"})
+	case services.ErrUserNotFound:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Mentioned user not found"})
+	case services.ErrInvalidComment, services.ErrCommentRemoved:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case services.ErrTooManyPinnedComments:
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case services.ErrNotCommentAuthor, services.ErrNotCollectionOwner, services.ErrUserBlocked:
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case services.ErrCommentRateLimited:
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{Error: err.Error()})
+	case services.ErrInvalidUserID:
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process comment"})
+	}
+}
+
+// newCommentListResponse преобразует страницу комментариев в ответ
+func newCommentListResponse(comments []*models.Comment, total int64, page, size int) CommentListResponse {
+	items := make([]CommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		items = append(items, newCommentResponse(comment))
+	}
+	return CommentListResponse{
+		Total: total,
+		Page:  page,
+		Size:  size,
+		Items: items,
+	}
+}
+
+// newCommentResponse преобразует комментарий в ответ. У удаленного комментария
+// скрываются текст и автор, но сохраняется место в ветке.
+func newCommentResponse(comment *models.Comment) CommentResponse {
+	response := CommentResponse{
+		ID:           comment.ID,
+		CollectionID: comment.CollectionID,
+		ParentID:     comment.ParentID,
+		RootID:       comment.RootID,
+		ReplyCount:   comment.ReplyCount,
+		Pinned:       comment.IsPinned,
+		Removed:      comment.IsRemoved(),
+		EditedAt:     comment.EditedAt,
+		CreatedAt:    comment.CreatedAt,
+	}
+	if comment.IsRemoved() {
+		return response
+	}
+
+	response.UserID = comment.UserID
+	response.UserName = comment.User.Name
+	response.UserImageURL = publicImageURL(&comment.User)
+	response.Text = comment.Text
+	for _, mention := range comment.Mentions {
+		response.Mentions = append(response.Mentions, CommentMentionResponse{UserID: mention.UserID, Name: mention.Name})
+	}
+	return response
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// DeckHandler обрабатывает запросы, связанные со смешанными колодами
+type DeckHandler struct {
+	deckService services.DeckService
+}
+
+// NewDeckHandler создает новый обработчик смешанных колод
+func NewDeckHandler(deckService services.DeckService) *DeckHandler {
+	return &DeckHandler{
+		deckService: deckService,
+	}
+}
+
+// Mix обрабатывает запрос на смешивание нескольких коллекций в одну колоду
+func (h *DeckHandler) Mix(c *gin.Context) {
+	var req DeckMixRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	deck, err := h.deckService.Mix(toDeckMixSpec(req), collectionVisibility(c))
+	if err != nil {
+		h.handleMixError(c, err, "Failed to mix collections")
+		return
+	}
+
+	items := make([]ActionResponseWithType, 0, len(deck.Actions))
+	for _, action := range deck.Actions {
+		items = append(items, newActionResponse(action))
+	}
+
+	c.JSON(http.StatusOK, DeckResponse{
+		Seed:  deck.Seed,
+		Count: len(items),
+		Items: items,
+	})
+}
+
+// SaveMix обрабатывает запрос на сохранение микса как виртуальной коллекции
+func (h *DeckHandler) SaveMix(c *gin.Context) {
+	var req SaveDeckMixRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	collection := &models.Collection{
+		Name:              req.Name,
+		Description:       req.Description,
+		ImageURL:          req.ImageURL,
+		UserID:            userID,
+		DeclaredAgeRating: req.AgeRating,
+	}
+
+	if err := h.deckService.SaveMix(collection, toDeckMixSpec(req.Mix), collectionVisibility(c)); err != nil {
+		h.handleMixError(c, err, "Failed to save mix")
+		return
+	}
+
+	c.JSON(http.StatusCreated, newCollectionResponse(collection))
+}
+
+// handleMixError преобразует ошибки сервиса смешанных колод в HTTP-ответ
+func (h *DeckHandler) handleMixError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidDeckMix), err == services.ErrNestedVirtualMixes,
+		err == services.ErrInvalidAgeRating:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrMixSourceNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case err == services.ErrUserBlocked:
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: fallback})
+	}
+}
+
+// toDeckMixSpec преобразует запрос в параметры микса
+func toDeckMixSpec(req DeckMixRequest) services.DeckMixSpec {
+	spec := services.DeckMixSpec{
+		Count:      req.Count,
+		TruthRatio: req.TruthRatio,
+		Seed:       req.Seed,
+	}
+	for _, source := range req.Collections {
+		spec.Sources = append(spec.Sources, services.DeckMixSourceSpec{
+			CollectionID: source.ID,
+			Weight:       source.Weight,
+		})
+	}
+	return spec
+}
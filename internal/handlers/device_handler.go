@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// DeviceHandler обрабатывает запросы, связанные с push-токенами устройств
+type DeviceHandler struct {
+	pushService services.PushService
+}
+
+// NewDeviceHandler создает новый обработчик устройств
+func NewDeviceHandler(pushService services.PushService) *DeviceHandler {
+	return &DeviceHandler{
+		pushService: pushService,
+	}
+}
+
+// Register обрабатывает регистрацию push-токена устройства текущего пользователя.
+// Повторная регистрация того же токена обновляет его и переносит к текущему пользователю.
+func (h *DeviceHandler) Register(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var req RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	device, err := h.pushService.RegisterDevice(userID, models.DevicePlatform(req.Platform), req.Token)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, DeviceResponse{
+		ID:         device.ID,
+		Platform:   string(device.Platform),
+		LastSeenAt: device.LastSeenAt,
+	})
+}
+
+// Unregister обрабатывает удаление push-токена устройства (например, при выходе из аккаунта)
+func (h *DeviceHandler) Unregister(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var req UnregisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.pushService.UnregisterDevice(userID, req.Token); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Device unregistered successfully"})
+}
+
+// handleError преобразует ошибки сервиса push-уведомлений в HTTP-ответы
+func (h *DeviceHandler) handleError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrInvalidDevicePlatform:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Platform must be android or ios"})
+	case services.ErrInvalidDeviceToken:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid device token"})
+	case services.ErrDeviceTokenNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Device not found"})
+	case services.ErrInvalidUserID:
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process device request"})
+	}
+}
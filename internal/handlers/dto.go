@@ -1,6 +1,11 @@
 package handlers
 
-import "time"
+import (
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/services"
+)
 
 // ErrorResponse представляет структуру ответа с ошибкой
 type ErrorResponse struct {
@@ -19,6 +24,8 @@ type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
 	Phone    string `json:"phone"`
+	// Дата рождения в формате YYYY-MM-DD, необязательна; ограничивает доступные коллекции по возрасту
+	BirthDate string `json:"birthDate"`
 }
 
 // LoginRequest представляет структуру запроса на вход
@@ -41,14 +48,19 @@ type RefreshTokenRequest struct {
 
 // UserResponse представляет структуру ответа с данными пользователя
 type UserResponse struct {
-	ID          uint      `json:"id"`
-	Name        string    `json:"name"`
-	Surname     string    `json:"surname"`
-	Email       string    `json:"email"`
-	Phone       string    `json:"phone,omitempty"`
-	ImageURL    string    `json:"imageUrl,omitempty"`
-	Description string    `json:"description,omitempty"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ID             uint                            `json:"id"`
+	Name           string                          `json:"name"`
+	Surname        string                          `json:"surname"`
+	Email          string                          `json:"email"`
+	Phone          string                          `json:"phone,omitempty"`
+	ImageURL       string                          `json:"imageUrl,omitempty"`
+	Description    string                          `json:"description,omitempty"`
+	BirthDate      string                          `json:"birthDate,omitempty"` // YYYY-MM-DD
+	FollowerCount  int                             `json:"followerCount"`
+	FollowingCount int                             `json:"followingCount"`
+	Privacy        ProfilePrivacySettings          `json:"privacy"`
+	Notifications  NotificationPreferencesSettings `json:"notifications"`
+	CreatedAt      time.Time                       `json:"createdAt"`
 }
 
 // UpdateProfileRequest представляет структуру запроса для обновления профиля
@@ -58,13 +70,62 @@ type UpdateProfileRequest struct {
 	Email       string `json:"email" binding:"required,email"`
 	Phone       string `json:"phone"`
 	Description string `json:"description"`
+	ImageURL    string `json:"imageUrl" binding:"omitempty,url"` // Аватар
+	BirthDate   string `json:"birthDate"`                        // YYYY-MM-DD; пустое значение не меняет дату
 }
 
-// PublicUserResponse представляет публичную информацию о пользователе
+// PublicUserResponse представляет краткую публичную информацию о пользователе в списках
 type PublicUserResponse struct {
-	ID      uint   `json:"id"`
-	Name    string `json:"name"`
-	Surname string `json:"surname"`
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Surname  string `json:"surname"`
+	ImageURL string `json:"imageUrl,omitempty"`
+}
+
+// PublicProfileResponse представляет публичный профиль автора.
+// Данные, скрытые настройками приватности, в ответ не попадают.
+type PublicProfileResponse struct {
+	ID             uint                    `json:"id"`
+	Name           string                  `json:"name"`
+	Surname        string                  `json:"surname"`
+	ImageURL       string                  `json:"imageUrl,omitempty"`
+	Description    string                  `json:"description,omitempty"`
+	JoinedAt       *time.Time              `json:"joinedAt,omitempty"`
+	FollowerCount  *int                    `json:"followerCount,omitempty"`
+	FollowingCount *int                    `json:"followingCount,omitempty"`
+	FollowedByMe   *bool                   `json:"followedByMe,omitempty"` // Только для авторизованного запроса
+	Stats          *CreatorStatsResponse   `json:"stats,omitempty"`
+	Collections    []CollectionResponse    `json:"collections,omitempty"` // Последние коллекции
+	Privacy        *ProfilePrivacySettings `json:"privacy,omitempty"`     // Только владельцу профиля
+}
+
+// CreatorStatsResponse представляет суммарную статистику коллекций автора
+type CreatorStatsResponse struct {
+	CollectionCount int64   `json:"collectionCount"`
+	TotalPlays      int64   `json:"totalPlays"`
+	AverageRating   float64 `json:"averageRating"`
+	RatingCount     int64   `json:"ratingCount"`
+}
+
+// ProfilePrivacySettings представляет настройки приватности профиля в запросах и ответах
+type ProfilePrivacySettings struct {
+	HideAvatar      bool `json:"hideAvatar"`
+	HideBio         bool `json:"hideBio"`
+	HideCollections bool `json:"hideCollections"`
+	HideStats       bool `json:"hideStats"`
+	HideFollows     bool `json:"hideFollows"`
+	HideJoinDate    bool `json:"hideJoinDate"`
+}
+
+// NotificationPreferencesSettings представляет отключенные типы уведомлений в запросах и ответах
+type NotificationPreferencesSettings struct {
+	MuteLikes       bool `json:"muteLikes"`
+	MuteForks       bool `json:"muteForks"`
+	MuteComments    bool `json:"muteComments"`
+	MuteReplies     bool `json:"muteReplies"`
+	MuteSuggestions bool `json:"muteSuggestions"`
+	MuteFollows     bool `json:"muteFollows"`
+	MutePush        bool `json:"mutePush"` // Только в приложении, без push
 }
 
 // CreateCollectionWithActionsRequest представляет запрос на создание коллекции с карточками
@@ -72,27 +133,633 @@ type CreateCollectionWithActionsRequest struct {
 	Name        string                `json:"name" binding:"required"`
 	Description string                `json:"description"`
 	ImageURL    string                `json:"imageUrl"`
+	AgeRating   int                   `json:"ageRating"` // Заявленный автором рейтинг: 0, 12, 16 или 18
 	Actions     []CreateActionRequest `json:"actions"`
 }
 
 // CreateActionRequest представляет структуру запроса для создания действия с типом
 type CreateActionRequest struct {
-	Text  string `json:"text" binding:"required"`
-	Type  string `json:"type" binding:"required"` // "truth" или "dare"
-	Order int    `json:"order"`
+	Text         string            `json:"text" binding:"required"`
+	Type         string            `json:"type" binding:"required"` // Тип из GET /api/action-types
+	Extra        map[string]string `json:"extra"`                   // Дополнительные поля, зависящие от типа
+	Order        int               `json:"order"`
+	Difficulty   int               `json:"difficulty"`   // 1-3, по умолчанию 1
+	Spiciness    int               `json:"spiciness"`    // 0-3, 3 - только 18+
+	TimerSeconds int               `json:"timerSeconds"` // 0 - без таймера
+	Points       int               `json:"points"`
 }
 
 // ActionResponseWithType представляет структуру ответа с данными действия включая тип
 type ActionResponseWithType struct {
-	ID    uint   `json:"id"`
-	Text  string `json:"text"`
-	Type  string `json:"type"`
-	Order int    `json:"order"`
+	ID           uint              `json:"id"`
+	Text         string            `json:"text"`
+	Type         string            `json:"type"`
+	Extra        map[string]string `json:"extra,omitempty"`
+	Order        int               `json:"order"`
+	CollectionID uint              `json:"collectionId"`
+	Difficulty   int               `json:"difficulty"`
+	Spiciness    int               `json:"spiciness"`
+	TimerSeconds int               `json:"timerSeconds"`
+	Points       int               `json:"points"`
+	IsTemplate   bool              `json:"isTemplate"` // Текст содержит подстановки игроков
+}
+
+// RenderActionsRequest представляет запрос на подстановку игроков в карточки коллекции
+type RenderActionsRequest struct {
+	Players       []string `json:"players" binding:"required,min=1"`
+	CurrentPlayer string   `json:"currentPlayer"` // Если не указан, карточки раздаются игрокам по очереди
+	Seed          int64    `json:"seed"`          // Одинаковый seed дает одинаковый результат
+}
+
+// RenderedActionResponse представляет карточку с подставленными значениями
+type RenderedActionResponse struct {
+	ID           uint              `json:"id"`
+	Type         string            `json:"type"`
+	Text         string            `json:"text"`
+	Template     string            `json:"template"`
+	Player       string            `json:"player"`
+	Extra        map[string]string `json:"extra,omitempty"`
+	Difficulty   int               `json:"difficulty"`
+	Spiciness    int               `json:"spiciness"`
+	TimerSeconds int               `json:"timerSeconds"`
+	Points       int               `json:"points"`
+}
+
+// ActionTypeResponse представляет описание типа карточки
+type ActionTypeResponse struct {
+	Type        string                    `json:"type"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	Fields      []ActionTypeFieldResponse `json:"fields"`
+}
+
+// ActionTypeFieldResponse представляет описание дополнительного поля типа карточки
+type ActionTypeFieldResponse struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	Required    bool   `json:"required"`
+	Description string `json:"description"`
 }
 
 // CollectionStatsResponse представляет статистику коллекции
 type CollectionStatsResponse struct {
-	TotalActions int `json:"totalActions"`
-	TruthCount   int `json:"truthCount"`
-	DareCount    int `json:"dareCount"`
-}
\ No newline at end of file
+	TotalActions           int            `json:"totalActions"`
+	TruthCount             int            `json:"truthCount"`
+	DareCount              int            `json:"dareCount"`
+	TypeCounts             map[string]int `json:"typeCounts"`
+	DifficultyDistribution map[int]int    `json:"difficultyDistribution"`
+	SpicinessDistribution  map[int]int    `json:"spicinessDistribution"`
+	TimedActions           int            `json:"timedActions"`
+	AverageTimerSeconds    float64        `json:"averageTimerSeconds"`
+	TotalPoints            int            `json:"totalPoints"`
+	// Отчет о качестве карточек, только для владельца коллекции (худшие карточки первыми)
+	CardQuality []CardQualityResponse `json:"cardQuality,omitempty"`
+}
+
+// CardQualityResponse представляет обратную связь по карточке
+type CardQualityResponse struct {
+	ActionID  uint    `json:"actionId"`
+	Type      string  `json:"type,omitempty"`
+	Text      string  `json:"text,omitempty"`
+	Upvotes   int     `json:"upvotes"`
+	Downvotes int     `json:"downvotes"`
+	Draws     int     `json:"draws"`
+	Completed int     `json:"completed"`
+	Skipped   int     `json:"skipped"`
+	SkipRate  float64 `json:"skipRate"`
+	Score     float64 `json:"score"` // От 0 до 1, 0.5 - нет обратной связи
+}
+
+// CardVoteRequest представляет голос за карточку
+type CardVoteRequest struct {
+	Value int `json:"value" binding:"required,oneof=1 -1"`
+}
+
+// BatchActionsRequest представляет пакетный запрос на изменение карточек коллекции
+type BatchActionsRequest struct {
+	Operations []BatchActionOperation `json:"operations" binding:"required,min=1"`
+}
+
+// BatchActionOperation представляет одну операцию пакетного запроса
+type BatchActionOperation struct {
+	Op    string            `json:"op" binding:"required"` // "create", "update" или "delete"
+	ID    uint              `json:"id"`                    // Для update и delete
+	Text  string            `json:"text"`
+	Type  string            `json:"type"`
+	Extra map[string]string `json:"extra"`
+	Order int               `json:"order"`
+
+	// Метаданные карточки; для update отсутствующее поле не меняет значение
+	Difficulty   *int `json:"difficulty"`
+	Spiciness    *int `json:"spiciness"`
+	TimerSeconds *int `json:"timerSeconds"`
+	Points       *int `json:"points"`
+}
+
+// BatchActionResult представляет результат одной операции пакетного запроса
+type BatchActionResult struct {
+	Index int    `json:"index"`
+	Op    string `json:"op"`
+	ID    uint   `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// BatchActionsResponse представляет ответ на пакетный запрос
+type BatchActionsResponse struct {
+	Error   string              `json:"error,omitempty"`
+	Results []BatchActionResult `json:"results"`
+}
+
+// DeckMixRequest представляет запрос на смешивание нескольких коллекций
+type DeckMixRequest struct {
+	Collections []DeckMixSourceRequest `json:"collections" binding:"required,min=1,dive"`
+	Count       int                    `json:"count" binding:"required,min=1"`
+	TruthRatio  *float64               `json:"truthRatio"` // Доля "правды" от 0 до 1; не указана - все типы карточек
+	Seed        int64                  `json:"seed"`       // 0 - случайный seed
+}
+
+// DeckMixSourceRequest представляет коллекцию-источник микса
+type DeckMixSourceRequest struct {
+	ID     uint    `json:"id" binding:"required"`
+	Weight float64 `json:"weight"` // По умолчанию 1
+}
+
+// SaveDeckMixRequest представляет запрос на сохранение микса как виртуальной коллекции
+type SaveDeckMixRequest struct {
+	Name        string         `json:"name" binding:"required"`
+	Description string         `json:"description"`
+	ImageURL    string         `json:"imageUrl"`
+	AgeRating   int            `json:"ageRating"` // Заявленный автором рейтинг: 0, 12, 16 или 18
+	Mix         DeckMixRequest `json:"mix" binding:"required"`
+}
+
+// DeckResponse представляет смешанную колоду
+type DeckResponse struct {
+	Seed  int64                    `json:"seed"`
+	Count int                      `json:"count"`
+	Items []ActionResponseWithType `json:"items"`
+}
+
+// StartGameRequest представляет запрос на начало игры
+type StartGameRequest struct {
+	CollectionID uint              `json:"collectionId" binding:"required"`
+	Players      []string          `json:"players"`
+	Seed         int64             `json:"seed"`         // 0 - случайный seed
+	Self         string            `json:"self"`         // Имя игрока, за которым сохраняется результат текущего пользователя
+	Rules        *models.GameRules `json:"rules"`        // Правила подсчета очков; пусто - по умолчанию
+	PreferUnseen bool              `json:"preferUnseen"` // Сначала карточки, которых игроки еще не видели
+	// Хорошо оцененные карточки выпадают раньше, плохо оцененные - позже
+	QualityWeighted bool `json:"qualityWeighted"`
+}
+
+// AddGamePlayerRequest представляет запрос на добавление игрока
+type AddGamePlayerRequest struct {
+	Name string `json:"name" binding:"required"`
+	Self bool   `json:"self"` // Игрок - текущий пользователь, его результат будет сохранен
+}
+
+// DrawCardRequest представляет запрос на вытягивание карточки
+type DrawCardRequest struct {
+	Type string `json:"type"` // Выбор игрока ("truth", "dare", ...); пусто - любая карточка
+}
+
+// GamePlayerResponse представляет участника игры
+type GamePlayerResponse struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	Position   int    `json:"position"`
+	Registered bool   `json:"registered"` // Игрок привязан к пользователю
+	Score      int    `json:"score"`
+	Completed  int    `json:"completed"`
+	Skipped    int    `json:"skipped"`
+}
+
+// ScoreEntryResponse представляет строку таблицы результатов
+type ScoreEntryResponse struct {
+	Rank   int                `json:"rank"`
+	Player GamePlayerResponse `json:"player"`
+}
+
+// GameSessionResponse представляет состояние игровой сессии
+type GameSessionResponse struct {
+	ID             string               `json:"id"`
+	CollectionID   uint                 `json:"collectionId"`
+	Status         string               `json:"status"`
+	Seed           int64                `json:"seed"`
+	Round          int                  `json:"round"`
+	Turn           int                  `json:"turn"`
+	Players        []GamePlayerResponse `json:"players"`
+	CurrentPlayer  *GamePlayerResponse  `json:"currentPlayer,omitempty"`
+	TotalCards     int                  `json:"totalCards"`
+	RemainingCards int                  `json:"remainingCards"` // Осталось в текущем круге
+	Rules          models.GameRules     `json:"rules"`
+	Scoreboard     []ScoreEntryResponse `json:"scoreboard"`
+	StartedAt      time.Time            `json:"startedAt"`
+	EndedAt        *time.Time           `json:"endedAt,omitempty"`
+}
+
+// DrawCardResponse представляет вытянутую карточку
+type DrawCardResponse struct {
+	Turn     int                    `json:"turn"`
+	Round    int                    `json:"round"`
+	NewRound bool                   `json:"newRound"` // Колода была перемешана заново
+	Player   GamePlayerResponse     `json:"player"`
+	Card     ActionResponseWithType `json:"card"`
+}
+
+// TurnResultResponse представляет исход хода после выполнения или пропуска карточки
+type TurnResultResponse struct {
+	Turn    int                     `json:"turn"`
+	Outcome string                  `json:"outcome"`
+	Points  int                     `json:"points"` // Отрицательное значение - штраф за пропуск
+	Player  GamePlayerResponse      `json:"player"`
+	Penalty *ActionResponseWithType `json:"penalty,omitempty"`
+}
+
+// GameResultResponse представляет результат завершенной игры пользователя
+type GameResultResponse struct {
+	SessionID    string    `json:"sessionId"`
+	CollectionID uint      `json:"collectionId"`
+	PlayerName   string    `json:"playerName"`
+	Score        int       `json:"score"`
+	Completed    int       `json:"completed"`
+	Skipped      int       `json:"skipped"`
+	Rank         int       `json:"rank"`
+	PlayerCount  int       `json:"playerCount"`
+	FinishedAt   time.Time `json:"finishedAt"`
+}
+
+// GameResultsResponse представляет страницу результатов игр
+type GameResultsResponse struct {
+	Total int64                `json:"total"`
+	Page  int                  `json:"page"`
+	Size  int                  `json:"size"`
+	Items []GameResultResponse `json:"items"`
+}
+
+// CreateRoomRequest представляет запрос на создание игровой комнаты
+type CreateRoomRequest struct {
+	CollectionID uint   `json:"collectionId" binding:"required"`
+	Name         string `json:"name"` // Имя ведущего; для авторизованного пользователя по умолчанию имя из профиля
+}
+
+// JoinRoomRequest представляет запрос на вход в комнату
+type JoinRoomRequest struct {
+	Name string `json:"name"`
+}
+
+// RoomInviteRequest представляет запрос на приглашение пользователя в комнату
+type RoomInviteRequest struct {
+	UserID uint `json:"userId" binding:"required"`
+}
+
+// RoomMemberResponse представляет участника комнаты
+type RoomMemberResponse struct {
+	ID       uint      `json:"id"`
+	Name     string    `json:"name"`
+	IsHost   bool      `json:"isHost"`
+	IsGuest  bool      `json:"isGuest"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+// RoomJoinResponse представляет результат создания комнаты или входа в нее
+type RoomJoinResponse struct {
+	Code        string              `json:"code"`
+	Member      RoomMemberResponse  `json:"member"`
+	MemberToken string              `json:"memberToken"` // Передается при подключении к /ws и для переподключения
+	State       *services.RoomState `json:"state"`
+}
+
+// HistoryCollectionResponse представляет коллекцию в истории пользователя
+type HistoryCollectionResponse struct {
+	CollectionID uint      `json:"collectionId"`
+	Name         string    `json:"name,omitempty"` // Пусто, если коллекция удалена
+	ImageURL     string    `json:"imageUrl,omitempty"`
+	SeenCount    int       `json:"seenCount"`
+	TotalCards   int       `json:"totalCards"`
+	LastSeenAt   time.Time `json:"lastSeenAt"`
+}
+
+// HistoryResponse представляет страницу истории пользователя
+type HistoryResponse struct {
+	Total int64                       `json:"total"`
+	Page  int                         `json:"page"`
+	Size  int                         `json:"size"`
+	Items []HistoryCollectionResponse `json:"items"`
+}
+
+// SeenCardResponse представляет карточку, которую видел пользователь
+type SeenCardResponse struct {
+	ActionID    uint      `json:"actionId"`
+	Type        string    `json:"type,omitempty"`
+	Text        string    `json:"text,omitempty"`
+	Deleted     bool      `json:"deleted"` // Карточка удалена из коллекции
+	TimesSeen   int       `json:"timesSeen"`
+	FirstSeenAt time.Time `json:"firstSeenAt"`
+	LastSeenAt  time.Time `json:"lastSeenAt"`
+}
+
+// ResetHistoryResponse представляет результат очистки истории
+type ResetHistoryResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+// DailyPlayStatsResponse представляет статистику партий за день
+type DailyPlayStatsResponse struct {
+	Date                   string  `json:"date"` // YYYY-MM-DD
+	Plays                  int     `json:"plays"`
+	CompletedPlays         int     `json:"completedPlays"`
+	AnonymousPlays         int     `json:"anonymousPlays"`
+	UniqueUsers            int     `json:"uniqueUsers"`
+	CardsDrawn             int     `json:"cardsDrawn"`
+	AverageDurationSeconds float64 `json:"averageDurationSeconds"`
+}
+
+// CollectionAnalyticsResponse представляет статистику партий коллекции за период
+type CollectionAnalyticsResponse struct {
+	CollectionID           uint                     `json:"collectionId"`
+	From                   string                   `json:"from"`
+	To                     string                   `json:"to"`
+	TotalPlays             int                      `json:"totalPlays"`
+	CompletedPlays         int                      `json:"completedPlays"`
+	AnonymousPlays         int                      `json:"anonymousPlays"`
+	UniqueUsers            int64                    `json:"uniqueUsers"`
+	CardsDrawn             int                      `json:"cardsDrawn"`
+	AverageDurationSeconds float64                  `json:"averageDurationSeconds"`
+	CompletionRate         float64                  `json:"completionRate"`
+	AllTimePlayCount       int                      `json:"allTimePlayCount"`
+	Days                   []DailyPlayStatsResponse `json:"days"`
+}
+
+// ReactionResponse представляет отметки пользователя на коллекции после изменения
+type ReactionResponse struct {
+	CollectionID  uint `json:"collectionId"`
+	Liked         bool `json:"liked"`
+	Favorited     bool `json:"favorited"`
+	LikeCount     int  `json:"likeCount"`
+	FavoriteCount int  `json:"favoriteCount"`
+}
+
+// ReviewRequest представляет оценку и отзыв о коллекции
+type ReviewRequest struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Text   string `json:"text"`
+}
+
+// ReviewReplyRequest представляет ответ владельца коллекции на отзыв
+type ReviewReplyRequest struct {
+	Text string `json:"text"`
+}
+
+// ReviewResponse представляет отзыв о коллекции
+type ReviewResponse struct {
+	ID             uint       `json:"id"`
+	CollectionID   uint       `json:"collectionId"`
+	UserID         uint       `json:"userId"`
+	UserName       string     `json:"userName"`
+	UserImageURL   string     `json:"userImageUrl,omitempty"`
+	Rating         int        `json:"rating"`
+	Text           string     `json:"text,omitempty"`
+	OwnerReply     string     `json:"ownerReply,omitempty"`
+	OwnerRepliedAt *time.Time `json:"ownerRepliedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+// ReviewListResponse представляет страницу отзывов о коллекции
+type ReviewListResponse struct {
+	Total int64            `json:"total"`
+	Page  int              `json:"page"`
+	Size  int              `json:"size"`
+	Items []ReviewResponse `json:"items"`
+}
+
+// CommentRequest представляет запрос на создание или изменение комментария
+type CommentRequest struct {
+	Text     string `json:"text" binding:"required"`
+	ParentID *uint  `json:"parentId"` // Только при создании: комментарий, на который отвечают
+	Mentions []uint `json:"mentions"` // ID упомянутых пользователей
+}
+
+// PinCommentRequest представляет запрос на закрепление комментария
+type PinCommentRequest struct {
+	Pinned bool `json:"pinned"`
+}
+
+// CommentMentionResponse представляет упомянутого пользователя
+type CommentMentionResponse struct {
+	UserID uint   `json:"userId"`
+	Name   string `json:"name"`
+}
+
+// CommentResponse представляет комментарий к коллекции
+type CommentResponse struct {
+	ID           uint                     `json:"id"`
+	CollectionID uint                     `json:"collectionId"`
+	ParentID     *uint                    `json:"parentId,omitempty"`
+	RootID       *uint                    `json:"rootId,omitempty"`
+	UserID       uint                     `json:"userId,omitempty"` // Пусто у удаленного комментария
+	UserName     string                   `json:"userName,omitempty"`
+	UserImageURL string                   `json:"userImageUrl,omitempty"`
+	Text         string                   `json:"text"`
+	Mentions     []CommentMentionResponse `json:"mentions,omitempty"`
+	ReplyCount   int                      `json:"replyCount"`
+	Pinned       bool                     `json:"pinned"`
+	Removed      bool                     `json:"removed"`
+	EditedAt     *time.Time               `json:"editedAt,omitempty"`
+	CreatedAt    time.Time                `json:"createdAt"`
+}
+
+// CommentListResponse представляет страницу комментариев
+type CommentListResponse struct {
+	Total int64             `json:"total"`
+	Page  int               `json:"page"`
+	Size  int               `json:"size"`
+	Items []CommentResponse `json:"items"`
+}
+
+// RejectSuggestionRequest представляет отказ по предложенной карточке
+type RejectSuggestionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// SuggestionResponse представляет предложенную карточку
+type SuggestionResponse struct {
+	ID             uint              `json:"id"`
+	CollectionID   uint              `json:"collectionId"`
+	CollectionName string            `json:"collectionName,omitempty"`
+	UserID         uint              `json:"userId"`
+	UserName       string            `json:"userName"`
+	Text           string            `json:"text"`
+	Type           string            `json:"type"`
+	Extra          map[string]string `json:"extra,omitempty"`
+	Difficulty     int               `json:"difficulty"`
+	Spiciness      int               `json:"spiciness"`
+	TimerSeconds   int               `json:"timerSeconds"`
+	Points         int               `json:"points"`
+	Status         string            `json:"status"` // pending, accepted или rejected
+	RejectReason   string            `json:"rejectReason,omitempty"`
+	ActionID       *uint             `json:"actionId,omitempty"` // Карточка, добавленная при принятии
+	ReviewedAt     *time.Time        `json:"reviewedAt,omitempty"`
+	CreatedAt      time.Time         `json:"createdAt"`
+}
+
+// SuggestionListResponse представляет страницу предложенных карточек
+type SuggestionListResponse struct {
+	Total int64                `json:"total"`
+	Page  int                  `json:"page"`
+	Size  int                  `json:"size"`
+	Items []SuggestionResponse `json:"items"`
+}
+
+// ReportRequest представляет жалобу на контент или пользователя
+type ReportRequest struct {
+	Reason  string `json:"reason" binding:"required"` // spam, harassment, hate, sexual, violence, illegal или other
+	Details string `json:"details"`
+}
+
+// ResolveReportRequest представляет решение модератора по жалобе
+type ResolveReportRequest struct {
+	Action      string `json:"action" binding:"required"` // dismiss, hide, delete, warn или suspend
+	Note        string `json:"note"`
+	SuspendDays int    `json:"suspendDays"` // Для suspend, по умолчанию 7
+}
+
+// UnhideCollectionRequest представляет запрос модератора на возврат скрытой коллекции
+type UnhideCollectionRequest struct {
+	Note string `json:"note"`
+}
+
+// ReportResponse представляет жалобу
+type ReportResponse struct {
+	ID             uint       `json:"id"`
+	ReporterID     *uint      `json:"reporterId,omitempty"` // Пусто у жалобы фильтра контента
+	ReporterName   string     `json:"reporterName,omitempty"`
+	TargetType     string     `json:"targetType"`
+	TargetID       uint       `json:"targetId"`
+	TargetUserID   *uint      `json:"targetUserId,omitempty"`
+	Reason         string     `json:"reason"`
+	Details        string     `json:"details,omitempty"`
+	Status         string     `json:"status"` // open, claimed или resolved
+	AssigneeID     *uint      `json:"assigneeId,omitempty"`
+	ClaimedAt      *time.Time `json:"claimedAt,omitempty"`
+	Resolution     string     `json:"resolution,omitempty"`
+	ResolutionNote string     `json:"resolutionNote,omitempty"`
+	ResolvedByID   *uint      `json:"resolvedById,omitempty"`
+	ResolvedAt     *time.Time `json:"resolvedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// ReportListResponse представляет страницу очереди жалоб
+type ReportListResponse struct {
+	Total int64            `json:"total"`
+	Page  int              `json:"page"`
+	Size  int              `json:"size"`
+	Items []ReportResponse `json:"items"`
+}
+
+// ModerationLogResponse представляет запись журнала модерации
+type ModerationLogResponse struct {
+	ID            uint       `json:"id"`
+	ModeratorID   uint       `json:"moderatorId"`
+	ModeratorName string     `json:"moderatorName"`
+	ReportID      *uint      `json:"reportId,omitempty"`
+	Action        string     `json:"action"`
+	TargetType    string     `json:"targetType"`
+	TargetID      uint       `json:"targetId"`
+	TargetUserID  *uint      `json:"targetUserId,omitempty"`
+	Note          string     `json:"note,omitempty"`
+	SuspendUntil  *time.Time `json:"suspendUntil,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// ModerationLogListResponse представляет страницу журнала модерации
+type ModerationLogListResponse struct {
+	Total int64                   `json:"total"`
+	Page  int                     `json:"page"`
+	Size  int                     `json:"size"`
+	Items []ModerationLogResponse `json:"items"`
+}
+
+// FollowResponse представляет подписку на автора после изменения и счетчики автора
+type FollowResponse struct {
+	UserID         uint `json:"userId"`
+	Following      bool `json:"following"`
+	FollowerCount  *int `json:"followerCount,omitempty"` // Нет, если автор скрыл подписки
+	FollowingCount *int `json:"followingCount,omitempty"`
+}
+
+// BlockResponse представляет блокировку пользователя после изменения
+type BlockResponse struct {
+	UserID  uint `json:"userId"`
+	Blocked bool `json:"blocked"`
+}
+
+// UserListResponse представляет страницу списка пользователей
+type UserListResponse struct {
+	Total int64                `json:"total"`
+	Page  int                  `json:"page"`
+	Size  int                  `json:"size"`
+	Items []PublicUserResponse `json:"items"`
+}
+
+// FeedItemResponse представляет коллекцию в ленте подписок
+type FeedItemResponse struct {
+	Event      string             `json:"event"` // created или updated
+	At         time.Time          `json:"at"`    // Время создания или последнего изменения
+	Author     PublicUserResponse `json:"author"`
+	Collection CollectionResponse `json:"collection"`
+}
+
+// FeedResponse представляет страницу ленты подписок
+type FeedResponse struct {
+	Items      []FeedItemResponse `json:"items"`
+	NextCursor string             `json:"nextCursor,omitempty"` // Пустой на последней странице
+}
+
+// NotificationResponse представляет уведомление; однотипные события объединены,
+// Actor - последний участник, ActorCount - число разных участников
+type NotificationResponse struct {
+	ID           uint               `json:"id"`
+	Type         string             `json:"type"`
+	Text         string             `json:"text"` // Например, "Anna and 4 others liked your collection"
+	CollectionID *uint              `json:"collectionId,omitempty"`
+	CommentID    *uint              `json:"commentId,omitempty"` // Ветка комментариев для ответов
+	Actor        PublicUserResponse `json:"actor"`
+	ActorCount   int                `json:"actorCount"`
+	Read         bool               `json:"read"`
+	CreatedAt    time.Time          `json:"createdAt"`
+	UpdatedAt    time.Time          `json:"updatedAt"` // Время последнего события
+}
+
+// NotificationListResponse представляет страницу уведомлений со счетчиком непрочитанных
+type NotificationListResponse struct {
+	Total       int64                  `json:"total"`
+	UnreadCount int64                  `json:"unreadCount"`
+	Page        int                    `json:"page"`
+	Size        int                    `json:"size"`
+	Items       []NotificationResponse `json:"items"`
+}
+
+// UnreadCountResponse представляет количество непрочитанных уведомлений
+type UnreadCountResponse struct {
+	UnreadCount int64 `json:"unreadCount"`
+}
+
+// RegisterDeviceRequest представляет запрос на регистрацию push-токена устройства
+type RegisterDeviceRequest struct {
+	Platform string `json:"platform" binding:"required"` // android или ios
+	Token    string `json:"token" binding:"required"`
+}
+
+// UnregisterDeviceRequest представляет запрос на удаление push-токена устройства
+type UnregisterDeviceRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// DeviceResponse представляет зарегистрированное устройство
+type DeviceResponse struct {
+	ID         uint      `json:"id"`
+	Platform   string    `json:"platform"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// FollowHandler обрабатывает запросы, связанные с подписками на авторов и лентой подписок
+type FollowHandler struct {
+	followService services.FollowService
+	likeService   services.LikeService
+}
+
+// NewFollowHandler создает новый обработчик подписок
+func NewFollowHandler(followService services.FollowService, likeService services.LikeService) *FollowHandler {
+	return &FollowHandler{
+		followService: followService,
+		likeService:   likeService,
+	}
+}
+
+// Follow обрабатывает запрос на подписку на автора (повторный запрос ничего не меняет)
+func (h *FollowHandler) Follow(c *gin.Context) {
+	h.change(c, h.followService.Follow)
+}
+
+// Unfollow обрабатывает запрос на отписку от автора (повторный запрос ничего не меняет)
+func (h *FollowHandler) Unfollow(c *gin.Context) {
+	h.change(c, h.followService.Unfollow)
+}
+
+// GetFollowers обрабатывает запрос на получение подписчиков пользователя
+func (h *FollowHandler) GetFollowers(c *gin.Context) {
+	h.list(c, h.followService.GetFollowers)
+}
+
+// GetFollowing обрабатывает запрос на получение авторов, на которых подписан пользователь
+func (h *FollowHandler) GetFollowing(c *gin.Context) {
+	h.list(c, h.followService.GetFollowing)
+}
+
+// GetFeed обрабатывает запрос на получение ленты подписок: новые и измененные коллекции
+// авторов, на которых подписан пользователь. Параметры: cursor (nextCursor предыдущей страницы), size.
+func (h *FollowHandler) GetFeed(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	_, size := parsePagination(c)
+
+	page, err := h.followService.GetFeed(userID, c.Query("cursor"), size, middleware.GetMaxAgeRating(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	collections := make([]CollectionResponse, 0, len(page.Collections))
+	for _, collection := range page.Collections {
+		collections = append(collections, newCollectionResponse(collection))
+	}
+	applyReactions(c, h.likeService, collections)
+
+	items := make([]FeedItemResponse, 0, len(page.Collections))
+	for i, collection := range page.Collections {
+		items = append(items, newFeedItemResponse(collection, collections[i]))
+	}
+
+	c.JSON(http.StatusOK, FeedResponse{
+		Items:      items,
+		NextCursor: page.NextCursor,
+	})
+}
+
+// change выполняет операцию над подпиской текущего пользователя и возвращает состояние автора
+func (h *FollowHandler) change(c *gin.Context, operation func(followerID, followeeID uint) (*services.FollowState, error)) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	state, err := operation(userID, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := FollowResponse{
+		UserID:    state.UserID,
+		Following: state.Following,
+	}
+	if state.ShowCounts {
+		response.FollowerCount = &state.FollowerCount
+		response.FollowingCount = &state.FollowingCount
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// list возвращает страницу подписчиков или подписок пользователя из пути запроса
+func (h *FollowHandler) list(c *gin.Context, query func(userID uint, viewerID *uint, page, pageSize int) ([]*models.User, int64, error)) {
+	id, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	page, size := parsePagination(c)
+	users, total, err := query(id, middleware.GetOptionalUserID(c), page, size)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	items := make([]PublicUserResponse, 0, len(users))
+	for _, user := range users {
+		items = append(items, newPublicUserResponse(user))
+	}
+
+	c.JSON(http.StatusOK, UserListResponse{
+		Total: total,
+		Page:  page,
+		Size:  size,
+		Items: items,
+	})
+}
+
+// handleError преобразует ошибки сервиса подписок в HTTP-ответы
+func (h *FollowHandler) handleError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrUserNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+	case services.ErrProfileSectionHidden:
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "User hides their follows"})
+	case services.ErrUserBlocked:
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "You cannot follow this user"})
+	case services.ErrCannotFollowSelf:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "You cannot follow yourself"})
+	case services.ErrInvalidFeedCursor:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid feed cursor"})
+	case services.ErrInvalidUserID:
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process follow request"})
+	}
+}
+
+// newFeedItemResponse преобразует коллекцию ленты в ответ. Коллекция считается измененной,
+// если после создания менялись ее описание или карточки.
+func newFeedItemResponse(collection *models.Collection, response CollectionResponse) FeedItemResponse {
+	item := FeedItemResponse{
+		Event:      "created",
+		At:         collection.CreatedAt,
+		Author:     newPublicUserResponse(&collection.User),
+		Collection: response,
+	}
+	if collection.UpdatedAt.After(collection.CreatedAt) {
+		item.Event = "updated"
+		item.At = collection.UpdatedAt
+	}
+	return item
+}
@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// GameHandler обрабатывает запросы, связанные с игровыми сессиями
+type GameHandler struct {
+	gameService services.GameService
+}
+
+// NewGameHandler создает новый обработчик игровых сессий
+func NewGameHandler(gameService services.GameService) *GameHandler {
+	return &GameHandler{
+		gameService: gameService,
+	}
+}
+
+// Start обрабатывает запрос на начало игры по коллекции
+func (h *GameHandler) Start(c *gin.Context) {
+	var req StartGameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	userID := middleware.GetOptionalUserID(c)
+	options := services.GameOptions{
+		Seed:            req.Seed,
+		Rules:           req.Rules,
+		PreferUnseen:    req.PreferUnseen,
+		QualityWeighted: req.QualityWeighted,
+		MaxAgeRating:    middleware.GetMaxAgeRating(c),
+	}
+	selfLinked := false
+	for _, name := range req.Players {
+		player := services.GamePlayerSpec{Name: name}
+		if req.Self != "" && strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(req.Self)) {
+			if userID == nil {
+				c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Authorization is required to save your results"})
+				return
+			}
+			player.UserID = userID
+			selfLinked = true
+		}
+		options.Players = append(options.Players, player)
+	}
+	if req.Self != "" && !selfLinked {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Self must be one of the players"})
+		return
+	}
+
+	session, err := h.gameService.Start(req.CollectionID, userID, options)
+	if err != nil {
+		h.handleError(c, err, "Failed to start game")
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.newGameSessionResponse(session))
+}
+
+// GetByID обрабатывает запрос на получение состояния игры
+func (h *GameHandler) GetByID(c *gin.Context) {
+	session, err := h.gameService.GetByID(c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get game")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.newGameSessionResponse(session))
+}
+
+// AddPlayer обрабатывает запрос на добавление игрока
+func (h *GameHandler) AddPlayer(c *gin.Context) {
+	var req AddGamePlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	userID := middleware.GetOptionalUserID(c)
+	spec := services.GamePlayerSpec{Name: req.Name}
+	if req.Self {
+		if userID == nil {
+			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Authorization is required to save your results"})
+			return
+		}
+		spec.UserID = userID
+	}
+
+	player, err := h.gameService.AddPlayer(c.Param("id"), userID, spec)
+	if err != nil {
+		h.handleError(c, err, "Failed to add player")
+		return
+	}
+
+	c.JSON(http.StatusCreated, newGamePlayerResponse(player))
+}
+
+// Draw обрабатывает запрос на вытягивание следующей карточки
+func (h *GameHandler) Draw(c *gin.Context) {
+	var req DrawCardRequest
+	// Тело запроса необязательно: без типа тянется следующая карточка колоды
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	result, err := h.gameService.Draw(c.Param("id"), middleware.GetOptionalUserID(c), models.ActionType(req.Type))
+	if err != nil {
+		h.handleError(c, err, "Failed to draw card")
+		return
+	}
+
+	card := newActionResponse(result.Action)
+	card.Text = result.Draw.Text
+	card.Extra = result.Extra
+
+	c.JSON(http.StatusOK, DrawCardResponse{
+		Turn:     result.Draw.Turn,
+		Round:    result.Draw.Round,
+		NewRound: result.NewRound,
+		Player:   newGamePlayerResponse(result.Player),
+		Card:     card,
+	})
+}
+
+// Complete обрабатывает запрос на выполнение последней вытянутой карточки
+func (h *GameHandler) Complete(c *gin.Context) {
+	result, err := h.gameService.Complete(c.Param("id"), middleware.GetOptionalUserID(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to complete card")
+		return
+	}
+
+	c.JSON(http.StatusOK, newTurnResultResponse(result))
+}
+
+// Skip обрабатывает запрос на пропуск последней вытянутой карточки
+func (h *GameHandler) Skip(c *gin.Context) {
+	result, err := h.gameService.Skip(c.Param("id"), middleware.GetOptionalUserID(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to skip card")
+		return
+	}
+
+	c.JSON(http.StatusOK, newTurnResultResponse(result))
+}
+
+// GetMyResults обрабатывает запрос на получение результатов игр текущего пользователя
+func (h *GameHandler) GetMyResults(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	page, size := parsePagination(c)
+	results, total, err := h.gameService.GetUserResults(userID, page, size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get game results"})
+		return
+	}
+
+	items := make([]GameResultResponse, 0, len(results))
+	for _, result := range results {
+		items = append(items, GameResultResponse{
+			SessionID:    result.SessionID,
+			CollectionID: result.CollectionID,
+			PlayerName:   result.PlayerName,
+			Score:        result.Score,
+			Completed:    result.Completed,
+			Skipped:      result.Skipped,
+			Rank:         result.Rank,
+			PlayerCount:  result.PlayerCount,
+			FinishedAt:   result.FinishedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, GameResultsResponse{
+		Total: total,
+		Page:  page,
+		Size:  size,
+		Items: items,
+	})
+}
+
+// End обрабатывает запрос на завершение игры
+func (h *GameHandler) End(c *gin.Context) {
+	session, err := h.gameService.End(c.Param("id"), middleware.GetOptionalUserID(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to end game")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.newGameSessionResponse(session))
+}
+
+// handleError преобразует ошибки сервиса игровых сессий в HTTP-ответ
+func (h *GameHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case err == services.ErrGameNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Game not found"})
+	case err == services.ErrCollectionNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
+	case err == services.ErrNotGameParticipant:
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "You are not allowed to manage this game"})
+	case err == services.ErrGameFinished, err == services.ErrNoCardsAvailable,
+		err == services.ErrPlayerNameTaken, err == services.ErrTooManyPlayers,
+		err == services.ErrNoPendingDraw, err == services.ErrSkipLimitReached,
+		err == services.ErrDrawPending:
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case err == services.ErrNoPlayers,
+		errors.Is(err, services.ErrInvalidPlayers),
+		errors.Is(err, services.ErrInvalidActionType),
+		errors.Is(err, services.ErrInvalidActionTemplate),
+		errors.Is(err, services.ErrInvalidGameRules):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: fallback})
+	}
+}
+
+// newGameSessionResponse преобразует игровую сессию в ответ
+func (h *GameHandler) newGameSessionResponse(session *models.GameSession) GameSessionResponse {
+	players := make([]GamePlayerResponse, 0, len(session.Players))
+	for _, player := range session.Players {
+		players = append(players, newGamePlayerResponse(player))
+	}
+
+	response := GameSessionResponse{
+		ID:             session.ID,
+		CollectionID:   session.CollectionID,
+		Status:         string(session.Status),
+		Seed:           session.Seed,
+		Round:          session.Round,
+		Turn:           session.Turn,
+		Players:        players,
+		TotalCards:     len(session.DeckOrder),
+		RemainingCards: h.gameService.RemainingCards(session),
+		Rules:          session.Rules,
+		Scoreboard:     make([]ScoreEntryResponse, 0, len(session.Players)),
+		StartedAt:      session.StartedAt,
+		EndedAt:        session.EndedAt,
+	}
+	for _, entry := range h.gameService.Scoreboard(session) {
+		response.Scoreboard = append(response.Scoreboard, ScoreEntryResponse{
+			Rank:   entry.Rank,
+			Player: newGamePlayerResponse(entry.Player),
+		})
+	}
+	if current := h.gameService.CurrentPlayer(session); current != nil && session.Status == models.GameSessionActive {
+		player := newGamePlayerResponse(current)
+		response.CurrentPlayer = &player
+	}
+	return response
+}
+
+// newGamePlayerResponse преобразует игрока в ответ
+func newGamePlayerResponse(player *models.GamePlayer) GamePlayerResponse {
+	return GamePlayerResponse{
+		ID:         player.ID,
+		Name:       player.Name,
+		Position:   player.Position,
+		Registered: player.UserID != nil,
+		Score:      player.Score,
+		Completed:  player.Completed,
+		Skipped:    player.Skipped,
+	}
+}
+
+// newTurnResultResponse преобразует исход хода в ответ
+func newTurnResultResponse(result *services.TurnResult) TurnResultResponse {
+	response := TurnResultResponse{
+		Turn:    result.Draw.Turn,
+		Outcome: string(result.Draw.Outcome),
+		Points:  result.Draw.Points,
+		Player:  newGamePlayerResponse(result.Player),
+	}
+	if result.Penalty != nil {
+		penalty := newActionResponse(result.Penalty)
+		penalty.Text = result.Draw.PenaltyText
+		response.Penalty = &penalty
+	}
+	return response
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// HistoryHandler обрабатывает запросы, связанные с историей просмотренных карточек
+type HistoryHandler struct {
+	historyService services.HistoryService
+}
+
+// NewHistoryHandler создает новый обработчик истории
+func NewHistoryHandler(historyService services.HistoryService) *HistoryHandler {
+	return &HistoryHandler{
+		historyService: historyService,
+	}
+}
+
+// GetMyHistory обрабатывает запрос на получение истории текущего пользователя.
+// Без collectionId возвращает сводку по коллекциям, с ним - просмотренные карточки коллекции.
+func (h *HistoryHandler) GetMyHistory(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	collectionID, ok := parseCollectionIDQuery(c)
+	if !ok {
+		return
+	}
+	if collectionID != nil {
+		h.getCollectionHistory(c, userID, *collectionID)
+		return
+	}
+
+	page, size := parsePagination(c)
+	summary, total, err := h.historyService.GetSummary(userID, page, size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get history"})
+		return
+	}
+
+	items := make([]HistoryCollectionResponse, 0, len(summary))
+	for _, item := range summary {
+		response := HistoryCollectionResponse{
+			CollectionID: item.CollectionID,
+			SeenCount:    item.SeenCount,
+			TotalCards:   item.TotalCards,
+			LastSeenAt:   item.LastSeenAt,
+		}
+		if item.Collection != nil {
+			response.Name = item.Collection.Name
+			response.ImageURL = item.Collection.ImageURL
+		}
+		items = append(items, response)
+	}
+
+	c.JSON(http.StatusOK, HistoryResponse{
+		Total: total,
+		Page:  page,
+		Size:  size,
+		Items: items,
+	})
+}
+
+// ResetMyHistory обрабатывает запрос на очистку истории текущего пользователя
+// (целиком или по коллекции из параметра collectionId)
+func (h *HistoryHandler) ResetMyHistory(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	collectionID, ok := parseCollectionIDQuery(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.historyService.Reset(userID, collectionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to reset history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResetHistoryResponse{Deleted: deleted})
+}
+
+// getCollectionHistory отвечает списком просмотренных карточек коллекции
+func (h *HistoryHandler) getCollectionHistory(c *gin.Context, userID, collectionID uint) {
+	cards, err := h.historyService.GetCollectionHistory(userID, collectionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get history"})
+		return
+	}
+
+	items := make([]SeenCardResponse, 0, len(cards))
+	for _, card := range cards {
+		response := SeenCardResponse{
+			ActionID:    card.View.ActionID,
+			Deleted:     card.Action == nil,
+			TimesSeen:   card.View.TimesSeen,
+			FirstSeenAt: card.View.FirstSeenAt,
+			LastSeenAt:  card.View.LastSeenAt,
+		}
+		if card.Action != nil {
+			response.Type = string(card.Action.Type)
+			response.Text = card.Action.Text
+		}
+		items = append(items, response)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collectionId": collectionID,
+		"items":        items,
+	})
+}
+
+// parseCollectionIDQuery разбирает необязательный параметр collectionId
+func parseCollectionIDQuery(c *gin.Context) (*uint, bool) {
+	return parseIDQuery(c, "collectionId", "Invalid collection ID")
+}
+
+// parseIDQuery разбирает необязательный ID из query-параметра name.
+// При ошибке отправляет 400 с сообщением message и возвращает false.
+func parseIDQuery(c *gin.Context, name, message string) (*uint, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: message})
+		return nil, false
+	}
+	result := uint(id)
+	return &result, true
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// LikeHandler обрабатывает запросы, связанные с лайками и избранным
+type LikeHandler struct {
+	likeService services.LikeService
+}
+
+// NewLikeHandler создает новый обработчик лайков и избранного
+func NewLikeHandler(likeService services.LikeService) *LikeHandler {
+	return &LikeHandler{
+		likeService: likeService,
+	}
+}
+
+// Like обрабатывает запрос на лайк коллекции (повторный запрос ничего не меняет)
+func (h *LikeHandler) Like(c *gin.Context) {
+	h.react(c, h.likeService.Like)
+}
+
+// Unlike обрабатывает запрос на снятие лайка (повторный запрос ничего не меняет)
+func (h *LikeHandler) Unlike(c *gin.Context) {
+	h.react(c, h.likeService.Unlike)
+}
+
+// Favorite обрабатывает запрос на добавление коллекции в избранное
+func (h *LikeHandler) Favorite(c *gin.Context) {
+	h.react(c, h.likeService.Favorite)
+}
+
+// Unfavorite обрабатывает запрос на удаление коллекции из избранного
+func (h *LikeHandler) Unfavorite(c *gin.Context) {
+	h.react(c, h.likeService.Unfavorite)
+}
+
+// GetMyFavorites обрабатывает запрос на получение избранных коллекций текущего пользователя
+func (h *LikeHandler) GetMyFavorites(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	page, size := parsePagination(c)
+	collections, total, err := h.likeService.GetFavorites(userID, page, size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get favorites"})
+		return
+	}
+
+	items := make([]CollectionResponse, 0, len(collections))
+	for _, collection := range collections {
+		items = append(items, newCollectionResponse(collection))
+	}
+	applyReactions(c, h.likeService, items)
+
+	c.JSON(http.StatusOK, PaginationResponse{
+		Total: total,
+		Page:  page,
+		Size:  size,
+		Items: items,
+	})
+}
+
+// react выполняет операцию над отметкой текущего пользователя и возвращает состояние коллекции
+func (h *LikeHandler) react(c *gin.Context, operation func(userID, collectionID uint) (*services.CollectionReaction, error)) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid collection ID"})
+		return
+	}
+
+	reaction, err := operation(userID, uint(id))
+	if err != nil {
+		switch err {
+		case services.ErrCollectionNotFound:
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
+		case services.ErrInvalidUserID:
+			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update collection reaction"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, ReactionResponse{
+		CollectionID:  reaction.CollectionID,
+		Liked:         reaction.Liked,
+		Favorited:     reaction.Favorited,
+		LikeCount:     reaction.LikeCount,
+		FavoriteCount: reaction.FavoriteCount,
+	})
+}
+
+// applyReactions заполняет likedByMe и favoritedByMe для авторизованного запроса.
+// Ошибка получения отметок не ломает ответ: флаги просто не выводятся.
+func applyReactions(c *gin.Context, likeService services.LikeService, items []CollectionResponse) {
+	userID := middleware.GetOptionalUserID(c)
+	if userID == nil || len(items) == 0 {
+		return
+	}
+
+	collectionIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		collectionIDs = append(collectionIDs, item.ID)
+	}
+
+	reactions, err := likeService.GetReactions(*userID, collectionIDs)
+	if err != nil {
+		return
+	}
+	for i := range items {
+		if reaction, ok := reactions[items[i].ID]; ok {
+			liked, favorited := reaction.Liked, reaction.Favorited
+			items[i].LikedByMe = &liked
+			items[i].FavoritedByMe = &favorited
+		}
+	}
+}
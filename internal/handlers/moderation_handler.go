@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ModerationHandler обрабатывает жалобы пользователей и очередь модерации
+type ModerationHandler struct {
+	moderationService services.ModerationService
+}
+
+// NewModerationHandler создает новый обработчик модерации
+func NewModerationHandler(moderationService services.ModerationService) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: moderationService,
+	}
+}
+
+// ReportCollection обрабатывает жалобу на коллекцию
+func (h *ModerationHandler) ReportCollection(c *gin.Context) {
+	h.report(c, models.ReportTargetCollection, "Invalid collection ID")
+}
+
+// ReportAction обрабатывает жалобу на карточку
+func (h *ModerationHandler) ReportAction(c *gin.Context) {
+	h.report(c, models.ReportTargetAction, "Invalid action ID")
+}
+
+// ReportComment обрабатывает жалобу на комментарий
+func (h *ModerationHandler) ReportComment(c *gin.Context) {
+	h.report(c, models.ReportTargetComment, "Invalid comment ID")
+}
+
+// ReportUser обрабатывает жалобу на пользователя
+func (h *ModerationHandler) ReportUser(c *gin.Context) {
+	h.report(c, models.ReportTargetUser, "Invalid user ID")
+}
+
+// GetQueue обрабатывает запрос модератора на получение очереди жалоб
+// (?status=open|claimed|resolved, ?targetType=, ?mine=true)
+func (h *ModerationHandler) GetQueue(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	filter := repository.ReportFilter{
+		Status:     models.ReportStatus(c.DefaultQuery("status", string(models.ReportOpen))),
+		TargetType: models.ReportTargetType(c.Query("targetType")),
+	}
+	if c.Query("mine") == "true" {
+		filter.AssigneeID = &userID
+	}
+
+	page, size := parsePagination(c)
+	reports, total, err := h.moderationService.ListQueue(userID, filter, page, size)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	items := make([]ReportResponse, 0, len(reports))
+	for _, report := range reports {
+		items = append(items, newReportResponse(report))
+	}
+	c.JSON(http.StatusOK, ReportListResponse{
+		Total: total,
+		Page:  page,
+		Size:  size,
+		Items: items,
+	})
+}
+
+// Claim обрабатывает запрос модератора на взятие жалобы в работу
+func (h *ModerationHandler) Claim(c *gin.Context) {
+	h.transition(c, h.moderationService.Claim)
+}
+
+// Release обрабатывает запрос модератора на возврат жалобы в очередь
+func (h *ModerationHandler) Release(c *gin.Context) {
+	h.transition(c, h.moderationService.Release)
+}
+
+// Resolve обрабатывает решение модератора по взятой в работу жалобе
+func (h *ModerationHandler) Resolve(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	reportID, ok := parseIDParam(c, "id", "Invalid report ID")
+	if !ok {
+		return
+	}
+
+	var req ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	report, err := h.moderationService.Resolve(userID, reportID, services.ResolveReportInput{
+		Action:      models.ModerationAction(req.Action),
+		Note:        req.Note,
+		SuspendDays: req.SuspendDays,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newReportResponse(report))
+}
+
+// UnhideCollection обрабатывает запрос модератора на возврат скрытой коллекции в публичный доступ
+func (h *ModerationHandler) UnhideCollection(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	collectionID, ok := parseIDParam(c, "id", "Invalid collection ID")
+	if !ok {
+		return
+	}
+
+	var req UnhideCollectionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	if err := h.moderationService.UnhideCollection(userID, collectionID, req.Note); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{Message: "Collection is visible again"})
+}
+
+// GetAuditLog обрабатывает запрос модератора на получение журнала решений
+// (?targetType=, ?targetId=, ?targetUserId=, ?moderatorId=)
+func (h *ModerationHandler) GetAuditLog(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	filter := repository.ModerationLogFilter{
+		TargetType: models.ReportTargetType(c.Query("targetType")),
+	}
+	var ok bool
+	if filter.TargetID, ok = parseIDQuery(c, "targetId", "Invalid target ID"); !ok {
+		return
+	}
+	if filter.TargetUserID, ok = parseIDQuery(c, "targetUserId", "Invalid target user ID"); !ok {
+		return
+	}
+	if filter.ModeratorID, ok = parseIDQuery(c, "moderatorId", "Invalid moderator ID"); !ok {
+		return
+	}
+
+	page, size := parsePagination(c)
+	logs, total, err := h.moderationService.ListAuditLog(userID, filter, page, size)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	items := make([]ModerationLogResponse, 0, len(logs))
+	for _, entry := range logs {
+		items = append(items, ModerationLogResponse{
+			ID:            entry.ID,
+			ModeratorID:   entry.ModeratorID,
+			ModeratorName: entry.Moderator.Name,
+			ReportID:      entry.ReportID,
+			Action:        string(entry.Action),
+			TargetType:    string(entry.TargetType),
+			TargetID:      entry.TargetID,
+			TargetUserID:  entry.TargetUserID,
+			Note:          entry.Note,
+			SuspendUntil:  entry.SuspendUntil,
+			CreatedAt:     entry.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, ModerationLogListResponse{
+		Total: total,
+		Page:  page,
+		Size:  size,
+		Items: items,
+	})
+}
+
+// report создает жалобу на объект targetType с ID из параметра пути
+func (h *ModerationHandler) report(c *gin.Context, targetType models.ReportTargetType, invalidIDMessage string) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	targetID, ok := parseIDParam(c, "id", invalidIDMessage)
+	if !ok {
+		return
+	}
+
+	var req ReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	report, err := h.moderationService.Report(userID, targetType, targetID, models.ReportReason(req.Reason), req.Details)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newReportResponse(report))
+}
+
+// transition выполняет claim или release жалобы из параметра пути
+func (h *ModerationHandler) transition(c *gin.Context, apply func(moderatorID, reportID uint) (*models.Report, error)) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	reportID, ok := parseIDParam(c, "id", "Invalid report ID")
+	if !ok {
+		return
+	}
+
+	report, err := apply(userID, reportID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newReportResponse(report))
+}
+
+// handleError преобразует ошибки сервиса модерации в HTTP-ответ
+func (h *ModerationHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case err == services.ErrReportNotFound, err == services.ErrReportTargetNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case err == services.ErrInvalidReport, err == services.ErrInvalidModeration, err == services.ErrInvalidModerationList:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case err == services.ErrNotModerator:
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case err == services.ErrAlreadyReported, err == services.ErrReportClaimed, err == services.ErrReportNotClaimed,
+		err == services.ErrCollectionNotHidden:
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case err == services.ErrReportRateLimited:
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{Error: err.Error()})
+	case err == services.ErrInvalidUserID:
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process moderation request"})
+	}
+}
+
+// newReportResponse преобразует жалобу в ответ
+func newReportResponse(report *models.Report) ReportResponse {
+	response := ReportResponse{
+		ID:             report.ID,
+		ReporterID:     report.ReporterID,
+		TargetType:     string(report.TargetType),
+		TargetID:       report.TargetID,
+		TargetUserID:   report.TargetUserID,
+		Reason:         string(report.Reason),
+		Details:        report.Details,
+		Status:         string(report.Status),
+		AssigneeID:     report.AssigneeID,
+		ClaimedAt:      report.ClaimedAt,
+		Resolution:     string(report.Resolution),
+		ResolutionNote: report.ResolutionNote,
+		ResolvedByID:   report.ResolvedByID,
+		ResolvedAt:     report.ResolvedAt,
+		CreatedAt:      report.CreatedAt,
+	}
+	if report.Reporter != nil {
+		response.ReporterName = report.Reporter.Name
+	}
+	return response
+}
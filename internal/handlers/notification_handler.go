@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler обрабатывает запросы, связанные с уведомлениями пользователя
+type NotificationHandler struct {
+	notificationService services.NotificationService
+}
+
+// NewNotificationHandler создает новый обработчик уведомлений
+func NewNotificationHandler(notificationService services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// List обрабатывает запрос на получение уведомлений текущего пользователя.
+// Параметры: page, size, unreadOnly=true - только непрочитанные.
+func (h *NotificationHandler) List(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	page, size := parsePagination(c)
+	notifications, total, err := h.notificationService.List(userID, c.Query("unreadOnly") == "true", page, size)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	unread, err := h.notificationService.UnreadCount(userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	items := make([]NotificationResponse, 0, len(notifications))
+	for _, notification := range notifications {
+		items = append(items, newNotificationResponse(notification))
+	}
+
+	c.JSON(http.StatusOK, NotificationListResponse{
+		Total:       total,
+		UnreadCount: unread,
+		Page:        page,
+		Size:        size,
+		Items:       items,
+	})
+}
+
+// UnreadCount обрабатывает запрос на получение количества непрочитанных уведомлений
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	unread, err := h.notificationService.UnreadCount(userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, UnreadCountResponse{UnreadCount: unread})
+}
+
+// MarkRead обрабатывает запрос на отметку уведомления прочитанным
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "Invalid notification ID")
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.MarkRead(userID, id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	h.UnreadCount(c)
+}
+
+// MarkAllRead обрабатывает запрос на отметку всех уведомлений прочитанными
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	if _, err := h.notificationService.MarkAllRead(userID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, UnreadCountResponse{UnreadCount: 0})
+}
+
+// UpdatePreferences сохраняет отключенные типы уведомлений текущего пользователя
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var req NotificationPreferencesSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	user, err := h.notificationService.UpdatePreferences(userID, models.NotificationPreferences{
+		MuteLikes:       req.MuteLikes,
+		MuteForks:       req.MuteForks,
+		MuteComments:    req.MuteComments,
+		MuteReplies:     req.MuteReplies,
+		MuteSuggestions: req.MuteSuggestions,
+		MuteFollows:     req.MuteFollows,
+		MutePush:        req.MutePush,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newNotificationPreferencesSettings(user.Notifications))
+}
+
+// handleError преобразует ошибки сервиса уведомлений в HTTP-ответы
+func (h *NotificationHandler) handleError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrNotificationNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Notification not found"})
+	case services.ErrUserNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+	case services.ErrInvalidUserID:
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process notification request"})
+	}
+}
+
+// newNotificationResponse преобразует уведомление в ответ
+func newNotificationResponse(notification *models.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:           notification.ID,
+		Type:         string(notification.Type),
+		Text:         services.NotificationText(notification),
+		CollectionID: notification.CollectionID,
+		CommentID:    notification.CommentID,
+		Actor:        newPublicUserResponse(&notification.Actor),
+		ActorCount:   notification.ActorCount,
+		Read:         notification.IsRead(),
+		CreatedAt:    notification.CreatedAt,
+		UpdatedAt:    notification.UpdatedAt,
+	}
+}
+
+// newNotificationPreferencesSettings преобразует настройки уведомлений в ответ
+func newNotificationPreferencesSettings(preferences models.NotificationPreferences) NotificationPreferencesSettings {
+	return NotificationPreferencesSettings{
+		MuteLikes:       preferences.MuteLikes,
+		MuteForks:       preferences.MuteForks,
+		MuteComments:    preferences.MuteComments,
+		MuteReplies:     preferences.MuteReplies,
+		MuteSuggestions: preferences.MuteSuggestions,
+		MuteFollows:     preferences.MuteFollows,
+		MutePush:        preferences.MutePush,
+	}
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewHandler обрабатывает запросы, связанные с оценками и отзывами
+type ReviewHandler struct {
+	reviewService services.ReviewService
+}
+
+// NewReviewHandler создает новый обработчик отзывов
+func NewReviewHandler(reviewService services.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// List обрабатывает запрос на получение отзывов о коллекции
+func (h *ReviewHandler) List(c *gin.Context) {
+	collectionID, ok := parseIDParam(c, "id", "Invalid collection ID")
+	if !ok {
+		return
+	}
+
+	page, size := parsePagination(c)
+	reviews, total, err := h.reviewService.List(collectionID, middleware.GetOptionalUserID(c), page, size)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	items := make([]ReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		items = append(items, newReviewResponse(review))
+	}
+
+	c.JSON(http.StatusOK, ReviewListResponse{
+		Total: total,
+		Page:  page,
+		Size:  size,
+		Items: items,
+	})
+}
+
+// GetMine обрабатывает запрос на получение своего отзыва о коллекции
+func (h *ReviewHandler) GetMine(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	collectionID, ok := parseIDParam(c, "id", "Invalid collection ID")
+	if !ok {
+		return
+	}
+
+	review, err := h.reviewService.GetMine(userID, collectionID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newReviewResponse(review))
+}
+
+// Save обрабатывает запрос на создание или изменение своего отзыва о коллекции
+func (h *ReviewHandler) Save(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	collectionID, ok := parseIDParam(c, "id", "Invalid collection ID")
+	if !ok {
+		return
+	}
+
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	review, created, err := h.reviewService.Save(userID, collectionID, req.Rating, req.Text)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, newReviewResponse(review))
+}
+
+// Delete обрабатывает запрос на удаление своего отзыва о коллекции
+func (h *ReviewHandler) Delete(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	collectionID, ok := parseIDParam(c, "id", "Invalid collection ID")
+	if !ok {
+		return
+	}
+
+	if err := h.reviewService.Delete(userID, collectionID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review deleted successfully"})
+}
+
+// Reply обрабатывает запрос владельца коллекции на ответ к отзыву (пустой текст удаляет ответ)
+func (h *ReviewHandler) Reply(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	reviewID, ok := parseIDParam(c, "id", "Invalid review ID")
+	if !ok {
+		return
+	}
+
+	var req ReviewReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	review, err := h.reviewService.Reply(userID, reviewID, req.Text)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newReviewResponse(review))
+}
+
+// handleError преобразует ошибки сервиса отзывов в HTTP-ответ
+func (h *ReviewHandler) handleError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrCollectionNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
+	case services.ErrReviewNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Review not found"})
+	case services.ErrInvalidRating, services.ErrReviewTooLong:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case services.ErrOwnCollectionReview, services.ErrNotCollectionOwner:
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case services.ErrInvalidUserID:
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process review"})
+	}
+}
+
+// parseIDParam разбирает числовой параметр пути и отвечает 400, если он некорректен
+func parseIDParam(c *gin.Context, name, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: message})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// newReviewResponse преобразует отзыв в ответ
+func newReviewResponse(review *models.CollectionReview) ReviewResponse {
+	return ReviewResponse{
+		ID:             review.ID,
+		CollectionID:   review.CollectionID,
+		UserID:         review.UserID,
+		UserName:       review.User.Name,
+		UserImageURL:   publicImageURL(&review.User),
+		Rating:         review.Rating,
+		Text:           review.Text,
+		OwnerReply:     review.OwnerReply,
+		OwnerRepliedAt: review.OwnerRepliedAt,
+		CreatedAt:      review.CreatedAt,
+		UpdatedAt:      review.UpdatedAt,
+	}
+}
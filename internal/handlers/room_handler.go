@@ -0,0 +1,407 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/realtime"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// roomEventError отправляется только клиенту, команда которого не выполнилась
+	roomEventError = "error"
+	// sseHeartbeatInterval определяет, как часто в простаивающий поток SSE пишется комментарий
+	sseHeartbeatInterval = 15 * time.Second
+)
+
+// RoomHandler обрабатывает запросы, связанные с игровыми комнатами
+type RoomHandler struct {
+	roomService services.RoomService
+}
+
+// NewRoomHandler создает новый обработчик игровых комнат
+func NewRoomHandler(roomService services.RoomService) *RoomHandler {
+	return &RoomHandler{
+		roomService: roomService,
+	}
+}
+
+// Create обрабатывает запрос на создание комнаты
+func (h *RoomHandler) Create(c *gin.Context) {
+	var req CreateRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	room, member, err := h.roomService.Create(req.CollectionID, middleware.GetOptionalUserID(c), req.Name, middleware.GetMaxAgeRating(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to create room")
+		return
+	}
+
+	h.respondWithMember(c, http.StatusCreated, room.Code, member)
+}
+
+// Join обрабатывает запрос на вход в комнату по коду
+func (h *RoomHandler) Join(c *gin.Context) {
+	var req JoinRoomRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	member, err := h.roomService.Join(c.Param("code"), middleware.GetOptionalUserID(c), req.Name, middleware.GetMaxAgeRating(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to join room")
+		return
+	}
+
+	h.respondWithMember(c, http.StatusOK, c.Param("code"), member)
+}
+
+// GetByCode обрабатывает запрос на получение состояния комнаты
+func (h *RoomHandler) GetByCode(c *gin.Context) {
+	state, err := h.roomService.PublicState(c.Param("code"), middleware.GetMaxAgeRating(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to get room")
+		return
+	}
+
+	c.JSON(http.StatusOK, state)
+}
+
+// WebSocket подключает участника к комнате. Участник передает memberToken,
+// полученный при создании комнаты или входе в нее, в параметре token.
+// Сразу после подключения клиент получает событие state с полным состоянием
+// либо, если передан lastEventId, пропущенные события.
+func (h *RoomHandler) WebSocket(c *gin.Context) {
+	code := c.Param("code")
+	member, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	lastEventID := lastEventID(c)
+
+	server := websocket.Server{
+		// Мобильные клиенты не передают Origin, поэтому стандартная проверка отключена;
+		// доступ к комнате определяется токеном участника
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			h.serveConnection(conn, code, member, lastEventID)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// serveConnection обслуживает WebSocket-соединение участника до его закрытия
+func (h *RoomHandler) serveConnection(conn *websocket.Conn, code string, member *models.RoomMember, lastEventID uint64) {
+	defer conn.Close()
+
+	room, err := h.roomService.Connect(code, member.ID, lastEventID)
+	if err != nil {
+		websocket.JSON.Send(conn, h.errorEvent(code, err))
+		return
+	}
+	defer room.Close()
+
+	// Все записи в соединение выполняет один цикл ниже; ответы на команды
+	// конкретного клиента передаются ему через replies
+	replies := make(chan realtime.Event, 8)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	defer close(stopped)
+	reply := func(event realtime.Event) {
+		select {
+		case replies <- event:
+		case <-stopped:
+		}
+	}
+
+	go func() {
+		defer close(done)
+		for {
+			var cmd services.RoomCommand
+			if err := websocket.JSON.Receive(conn, &cmd); err != nil {
+				return
+			}
+
+			if err := h.roomService.Execute(code, member.ID, cmd); err != nil {
+				reply(h.errorEvent(code, err))
+				continue
+			}
+			if cmd.Type == services.RoomCommandSync {
+				reply(h.stateEvent(code, 0))
+			}
+			if cmd.Type == services.RoomCommandLeave {
+				return
+			}
+		}
+	}()
+
+	for _, event := range h.initialEvents(code, room) {
+		if err := websocket.JSON.Send(conn, event); err != nil {
+			return
+		}
+	}
+
+	for {
+		var event realtime.Event
+		select {
+		case <-done:
+			return
+		case event = <-replies:
+		case e, ok := <-room.Events:
+			if !ok {
+				// Комната закрыта или клиент не успевал читать события
+				return
+			}
+			event = e
+		}
+
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		if err := websocket.JSON.Send(conn, event); err != nil {
+			log.Printf("Error sending event to room %s member %d: %v", code, member.ID, err)
+			return
+		}
+	}
+}
+
+// Events отдает события комнаты как поток Server-Sent Events для сетей, где
+// заблокирован WebSocket. Поток возобновляется с заголовка Last-Event-ID;
+// команды в этом режиме отправляются через POST /rooms/:code/commands.
+func (h *RoomHandler) Events(c *gin.Context) {
+	code := c.Param("code")
+	member, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+
+	room, err := h.roomService.Connect(code, member.ID, lastEventID(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to connect to room")
+		return
+	}
+	defer room.Close()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Отключает буферизацию в nginx
+
+	for _, event := range h.initialEvents(code, room) {
+		c.Render(-1, newSSEvent(event))
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-room.Events:
+			if !ok {
+				// Комната закрыта или клиент не успевал читать события; он переподключится с Last-Event-ID
+				return false
+			}
+			c.Render(-1, newSSEvent(event))
+			return true
+		case <-heartbeat.C:
+			// Комментарий не виден клиенту, но не дает прокси закрыть неактивное соединение
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		}
+	})
+}
+
+// Command выполняет команду участника, переданную по HTTP (для клиентов на SSE).
+// Результат команды приходит всем участникам в потоке событий; в ответе
+// возвращается актуальное состояние комнаты.
+func (h *RoomHandler) Command(c *gin.Context) {
+	member, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+
+	var cmd services.RoomCommand
+	if err := c.ShouldBindJSON(&cmd); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.roomService.Execute(c.Param("code"), member.ID, cmd); err != nil {
+		h.handleError(c, err, "Failed to execute command")
+		return
+	}
+
+	state, err := h.roomService.State(c.Param("code"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get room")
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
+
+// Invite отправляет пользователю push с приглашением в комнату от участника с X-Member-Token
+func (h *RoomHandler) Invite(c *gin.Context) {
+	member, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+
+	var req RoomInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.roomService.Invite(c.Param("code"), member.ID, req.UserID); err != nil {
+		h.handleError(c, err, "Failed to invite user")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation sent"})
+}
+
+// authenticate находит участника комнаты по токену из заголовка X-Member-Token
+// или параметра token (браузерные WebSocket и EventSource не умеют передавать заголовки)
+func (h *RoomHandler) authenticate(c *gin.Context) (*models.RoomMember, bool) {
+	token := c.GetHeader("X-Member-Token")
+	if token == "" {
+		token = c.Query("token")
+	}
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Member token is required"})
+		return nil, false
+	}
+
+	member, err := h.roomService.Authenticate(c.Param("code"), token)
+	if err != nil {
+		h.handleError(c, err, "Failed to authenticate room member")
+		return nil, false
+	}
+	return member, true
+}
+
+// initialEvents возвращает события, которые клиент получает сразу после подключения:
+// пропущенные события при возобновлении или полное состояние комнаты
+func (h *RoomHandler) initialEvents(code string, room *services.RoomConnection) []realtime.Event {
+	if room.Resumed {
+		return room.Missed
+	}
+	return []realtime.Event{h.stateEvent(code, room.LastEventID)}
+}
+
+// stateEvent формирует событие с полным состоянием комнаты для одного клиента.
+// ID события равен ID последнего учтенного в состоянии события комнаты.
+func (h *RoomHandler) stateEvent(code string, id uint64) realtime.Event {
+	state, err := h.roomService.State(code)
+	if err != nil {
+		return h.errorEvent(code, err)
+	}
+	return realtime.Event{ID: id, Room: code, Type: services.RoomEventState, Data: state, Time: time.Now()}
+}
+
+// lastEventID возвращает ID последнего полученного клиентом события из заголовка
+// Last-Event-ID (его отправляет EventSource при переподключении) или параметра lastEventId
+func lastEventID(c *gin.Context) uint64 {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("lastEventId")
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// newSSEvent преобразует событие комнаты в событие SSE; в data передается то же
+// JSON-представление, что и по WebSocket
+func newSSEvent(event realtime.Event) sse.Event {
+	sseEvent := sse.Event{
+		Event: event.Type,
+		Data:  event,
+	}
+	if event.ID > 0 {
+		sseEvent.Id = strconv.FormatUint(event.ID, 10)
+	}
+	return sseEvent
+}
+
+// errorEvent формирует событие об ошибке команды
+func (h *RoomHandler) errorEvent(code string, err error) realtime.Event {
+	return realtime.Event{
+		Room: code,
+		Type: roomEventError,
+		Data: ErrorResponse{Error: err.Error()},
+		Time: time.Now(),
+	}
+}
+
+// respondWithMember отвечает данными участника и текущим состоянием комнаты
+func (h *RoomHandler) respondWithMember(c *gin.Context, status int, code string, member *models.RoomMember) {
+	state, err := h.roomService.State(code)
+	if err != nil {
+		h.handleError(c, err, "Failed to get room")
+		return
+	}
+
+	c.JSON(status, RoomJoinResponse{
+		Code: state.Code,
+		Member: RoomMemberResponse{
+			ID:       member.ID,
+			Name:     member.Name,
+			IsHost:   member.IsHost,
+			IsGuest:  member.UserID == nil,
+			JoinedAt: member.JoinedAt,
+		},
+		MemberToken: member.Token,
+		State:       state,
+	})
+}
+
+// handleError преобразует ошибки сервиса комнат в HTTP-ответ
+func (h *RoomHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case err == services.ErrRoomNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Room not found"})
+	case err == services.ErrCollectionNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
+	case err == services.ErrNotRoomMember:
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid member token"})
+	case err == services.ErrUserNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+	case err == services.ErrNotRoomHost, err == services.ErrNotYourTurn, err == services.ErrUserBlocked,
+		err == services.ErrGuestCannotInvite, err == services.ErrInviteNotAllowed:
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case err == services.ErrInviteRateLimited:
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{Error: err.Error()})
+	case err == services.ErrRoomFinished, err == services.ErrRoomFull, err == services.ErrMemberNameTaken,
+		err == services.ErrRoomNotPlaying, err == services.ErrRoomAlreadyStarted,
+		err == services.ErrGameFinished, err == services.ErrNoCardsAvailable, err == services.ErrPlayerNameTaken,
+		err == services.ErrNoPendingDraw, err == services.ErrSkipLimitReached, err == services.ErrAlreadyRoomMember,
+		err == services.ErrDrawPending:
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case err == services.ErrInvalidUserID, err == services.ErrNoPlayers,
+		errors.Is(err, services.ErrInvalidPlayers),
+		errors.Is(err, services.ErrInvalidActionType),
+		errors.Is(err, services.ErrInvalidGameRules),
+		errors.Is(err, services.ErrUnknownRoomCommand):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: fallback})
+	}
+}
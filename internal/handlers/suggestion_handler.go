@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// SuggestionHandler обрабатывает запросы, связанные с предложенными карточками
+type SuggestionHandler struct {
+	suggestionService services.SuggestionService
+}
+
+// NewSuggestionHandler создает новый обработчик предложенных карточек
+func NewSuggestionHandler(suggestionService services.SuggestionService) *SuggestionHandler {
+	return &SuggestionHandler{
+		suggestionService: suggestionService,
+	}
+}
+
+// Suggest обрабатывает запрос на предложение карточки в чужую коллекцию
+func (h *SuggestionHandler) Suggest(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	collectionID, ok := parseIDParam(c, "id", "Invalid collection ID")
+	if !ok {
+		return
+	}
+
+	var req CreateActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	suggestion, err := h.suggestionService.Suggest(userID, collectionID, &models.Action{
+		Text:         req.Text,
+		Type:         models.ActionType(req.Type),
+		Extra:        req.Extra,
+		Difficulty:   req.Difficulty,
+		Spiciness:    req.Spiciness,
+		TimerSeconds: req.TimerSeconds,
+		Points:       req.Points,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newSuggestionResponse(suggestion))
+}
+
+// GetMySuggestions обрабатывает запрос на получение своих предложений и их статусов (?status=)
+func (h *SuggestionHandler) GetMySuggestions(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	page, size := parsePagination(c)
+	status := models.SuggestionStatus(c.Query("status"))
+	suggestions, total, err := h.suggestionService.GetMine(userID, status, page, size)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSuggestionListResponse(suggestions, total, page, size))
+}
+
+// GetInbox обрабатывает запрос владельца на получение предложений к его коллекциям
+// (?status=pending|accepted|rejected, ?collectionId=)
+func (h *SuggestionHandler) GetInbox(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	collectionID, ok := parseCollectionIDQuery(c)
+	if !ok {
+		return
+	}
+
+	page, size := parsePagination(c)
+	status := models.SuggestionStatus(c.DefaultQuery("status", string(models.SuggestionPending)))
+	suggestions, total, err := h.suggestionService.GetInbox(userID, collectionID, status, page, size)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSuggestionListResponse(suggestions, total, page, size))
+}
+
+// Accept обрабатывает запрос владельца на принятие предложения
+func (h *SuggestionHandler) Accept(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	suggestionID, ok := parseIDParam(c, "id", "Invalid suggestion ID")
+	if !ok {
+		return
+	}
+
+	suggestion, err := h.suggestionService.Accept(userID, suggestionID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSuggestionResponse(suggestion))
+}
+
+// Reject обрабатывает запрос владельца на отклонение предложения с причиной
+func (h *SuggestionHandler) Reject(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	suggestionID, ok := parseIDParam(c, "id", "Invalid suggestion ID")
+	if !ok {
+		return
+	}
+
+	// Причина необязательна, поэтому пустое тело запроса допустимо
+	var req RejectSuggestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	suggestion, err := h.suggestionService.Reject(userID, suggestionID, req.Reason)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSuggestionResponse(suggestion))
+}
+
+// handleError преобразует ошибки сервиса предложений в HTTP-ответ
+func (h *SuggestionHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case err == services.ErrCollectionNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Collection not found"})
+	case err == services.ErrSuggestionNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Suggestion not found"})
+	case errors.Is(err, services.ErrInvalidActionType):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid action type"})
+	case errors.Is(err, services.ErrInvalidActionData), errors.Is(err, services.ErrInvalidActionTemplate),
+		errors.Is(err, services.ErrContentRejected), err == services.ErrInvalidSuggestionStatus,
+		err == services.ErrRejectReasonTooLong:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case err == services.ErrNotCollectionOwner, err == services.ErrOwnCollectionSuggest, err == services.ErrUserBlocked:
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case err == services.ErrSuggestionReviewed, err == services.ErrVirtualCollection:
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case err == services.ErrSuggestionRateLimited:
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{Error: err.Error()})
+	case err == services.ErrInvalidUserID:
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process suggestion"})
+	}
+}
+
+// newSuggestionListResponse преобразует страницу предложений в ответ
+func newSuggestionListResponse(suggestions []*models.CardSuggestion, total int64, page, size int) SuggestionListResponse {
+	items := make([]SuggestionResponse, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		items = append(items, newSuggestionResponse(suggestion))
+	}
+	return SuggestionListResponse{
+		Total: total,
+		Page:  page,
+		Size:  size,
+		Items: items,
+	}
+}
+
+// newSuggestionResponse преобразует предложение в ответ
+func newSuggestionResponse(suggestion *models.CardSuggestion) SuggestionResponse {
+	return SuggestionResponse{
+		ID:             suggestion.ID,
+		CollectionID:   suggestion.CollectionID,
+		CollectionName: suggestion.Collection.Name,
+		UserID:         suggestion.UserID,
+		UserName:       suggestion.User.Name,
+		Text:           suggestion.Text,
+		Type:           string(suggestion.Type),
+		Extra:          suggestion.Extra,
+		Difficulty:     suggestion.Difficulty,
+		Spiciness:      suggestion.Spiciness,
+		TimerSeconds:   suggestion.TimerSeconds,
+		Points:         suggestion.Points,
+		Status:         string(suggestion.Status),
+		RejectReason:   suggestion.RejectReason,
+		ActionID:       suggestion.ActionID,
+		ReviewedAt:     suggestion.ReviewedAt,
+		CreatedAt:      suggestion.CreatedAt,
+	}
+}
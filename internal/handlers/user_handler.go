@@ -1,27 +1,35 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/KoLili12/bulb-server/internal/middleware"
+	"github.com/KoLili12/bulb-server/internal/models"
 	"github.com/KoLili12/bulb-server/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
 // UserHandler обрабатывает запросы, связанные с пользователями
 type UserHandler struct {
-	userService services.UserService
+	userService    services.UserService
+	profileService services.ProfileService
+	likeService    services.LikeService
 }
 
 // NewUserHandler создает новый обработчик пользователей
-func NewUserHandler(userService services.UserService) *UserHandler {
+func NewUserHandler(userService services.UserService, profileService services.ProfileService, likeService services.LikeService) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:    userService,
+		profileService: profileService,
+		likeService:    likeService,
 	}
 }
 
-// GetUserByID возвращает информацию о пользователе по ID (публичный endpoint)
+// GetUserByID возвращает публичный профиль автора по ID (публичный endpoint).
+// Данные, которые автор скрыл настройками приватности, видит только он сам.
 func (h *UserHandler) GetUserByID(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -29,7 +37,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.GetByID(uint(id))
+	profile, err := h.profileService.GetProfile(uint(id), middleware.GetOptionalUserID(c), middleware.GetMaxAgeRating(c))
 	if err != nil {
 		if err == services.ErrUserNotFound {
 			c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
@@ -39,16 +47,51 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 		return
 	}
 
-	// Возвращаем только публичную информацию о пользователе
-	response := PublicUserResponse{
-		ID:      user.ID,
-		Name:    user.Name,
-		Surname: user.Surname,
-	}
+	response := newPublicProfileResponse(profile)
+	applyReactions(c, h.likeService, response.Collections)
 
 	c.JSON(http.StatusOK, response)
 }
 
+// GetCollections возвращает публичные коллекции автора постранично (?sort=newest|popular|rating)
+func (h *UserHandler) GetCollections(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+
+	page, size := parsePagination(c)
+	sort := models.CollectionSort(c.Query("sort"))
+	collections, total, err := h.profileService.GetCollections(uint(id), middleware.GetOptionalUserID(c), page, size, sort, middleware.GetMaxAgeRating(c))
+	if err != nil {
+		switch err {
+		case services.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		case services.ErrProfileSectionHidden:
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "User hides their collections"})
+		case services.ErrInvalidCollectionSort:
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid sort, expected newest, popular or rating"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get user collections"})
+		}
+		return
+	}
+
+	items := make([]CollectionResponse, 0, len(collections))
+	for _, collection := range collections {
+		items = append(items, newCollectionResponse(collection))
+	}
+	applyReactions(c, h.likeService, items)
+
+	c.JSON(http.StatusOK, PaginationResponse{
+		Total: total,
+		Page:  page,
+		Size:  size,
+		Items: items,
+	})
+}
+
 // GetProfile возвращает профиль текущего пользователя
 func (h *UserHandler) GetProfile(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -68,14 +111,19 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 	}
 
 	response := UserResponse{
-		ID:          user.ID,
-		Name:        user.Name,
-		Surname:     user.Surname,
-		Email:       user.Email,
-		Phone:       user.Phone,
-		ImageURL:    user.ImageURL,
-		Description: user.Description,
-		CreatedAt:   user.CreatedAt,
+		ID:             user.ID,
+		Name:           user.Name,
+		Surname:        user.Surname,
+		Email:          user.Email,
+		Phone:          user.Phone,
+		ImageURL:       user.ImageURL,
+		Description:    user.Description,
+		BirthDate:      formatBirthDate(user.BirthDate),
+		FollowerCount:  user.FollowerCount,
+		FollowingCount: user.FollowingCount,
+		Privacy:        newProfilePrivacySettings(user.Privacy),
+		Notifications:  newNotificationPreferencesSettings(user.Notifications),
+		CreatedAt:      user.CreatedAt,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -95,6 +143,12 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
+	birthDate, err := parseBirthDate(req.BirthDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
 	// Получаем текущего пользователя
 	user, err := h.userService.GetByID(userID)
 	if err != nil {
@@ -108,6 +162,10 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	user.Email = req.Email
 	user.Phone = req.Phone
 	user.Description = req.Description
+	user.ImageURL = req.ImageURL
+	if birthDate != nil {
+		user.BirthDate = birthDate
+	}
 
 	// Сохраняем изменения
 	if err := h.userService.Update(user); err != nil {
@@ -115,9 +173,140 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 			c.JSON(http.StatusConflict, ErrorResponse{Error: "Email already exists"})
 			return
 		}
+		if err == services.ErrInvalidBirthDate {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid birth date"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update user"})
 		return
 	}
 
 	c.JSON(http.StatusOK, SuccessResponse{Message: "Profile updated successfully"})
-}
\ No newline at end of file
+}
+
+// UpdatePrivacy сохраняет настройки приватности публичного профиля текущего пользователя
+func (h *UserHandler) UpdatePrivacy(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var req ProfilePrivacySettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	user, err := h.profileService.UpdatePrivacy(userID, models.ProfilePrivacy{
+		HideAvatar:      req.HideAvatar,
+		HideBio:         req.HideBio,
+		HideCollections: req.HideCollections,
+		HideStats:       req.HideStats,
+		HideFollows:     req.HideFollows,
+		HideJoinDate:    req.HideJoinDate,
+	})
+	if err != nil {
+		if err == services.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update privacy settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newProfilePrivacySettings(user.Privacy))
+}
+
+// birthDateLayout - формат даты рождения в запросах и ответах
+const birthDateLayout = "2006-01-02"
+
+// parseBirthDate разбирает дату рождения в формате YYYY-MM-DD; пустая строка дает nil
+func parseBirthDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(birthDateLayout, value)
+	if err != nil {
+		return nil, errors.New("invalid birth date, expected YYYY-MM-DD")
+	}
+	return &date, nil
+}
+
+// formatBirthDate форматирует дату рождения для ответа
+func formatBirthDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(birthDateLayout)
+}
+
+// newPublicUserResponse преобразует пользователя в краткий публичный ответ для списков
+func newPublicUserResponse(user *models.User) PublicUserResponse {
+	return PublicUserResponse{
+		ID:       user.ID,
+		Name:     user.Name,
+		Surname:  user.Surname,
+		ImageURL: publicImageURL(user),
+	}
+}
+
+// publicImageURL возвращает аватар пользователя для показа другим или пустую строку,
+// если пользователь скрыл аватар в настройках приватности
+func publicImageURL(user *models.User) string {
+	if user.Privacy.HideAvatar {
+		return ""
+	}
+	return user.ImageURL
+}
+
+// newPublicProfileResponse преобразует профиль автора в ответ; скрытые данные уже убраны сервисом
+func newPublicProfileResponse(profile *services.CreatorProfile) PublicProfileResponse {
+	user := profile.User
+	response := PublicProfileResponse{
+		ID:           user.ID,
+		Name:         user.Name,
+		Surname:      user.Surname,
+		ImageURL:     user.ImageURL,
+		Description:  user.Description,
+		FollowedByMe: profile.FollowedByMe,
+	}
+	if profile.ShowJoinDate {
+		response.JoinedAt = &user.CreatedAt
+	}
+	if profile.ShowFollows {
+		response.FollowerCount = &user.FollowerCount
+		response.FollowingCount = &user.FollowingCount
+	}
+	if profile.Stats != nil {
+		response.Stats = &CreatorStatsResponse{
+			CollectionCount: profile.Stats.CollectionCount,
+			TotalPlays:      profile.Stats.TotalPlays,
+			AverageRating:   profile.Stats.AverageRating(),
+			RatingCount:     profile.Stats.RatingCount,
+		}
+	}
+	if profile.Collections != nil {
+		response.Collections = make([]CollectionResponse, 0, len(profile.Collections))
+		for _, collection := range profile.Collections {
+			response.Collections = append(response.Collections, newCollectionResponse(collection))
+		}
+	}
+	if profile.IsOwner {
+		privacy := newProfilePrivacySettings(user.Privacy)
+		response.Privacy = &privacy
+	}
+	return response
+}
+
+// newProfilePrivacySettings преобразует настройки приватности в ответ
+func newProfilePrivacySettings(privacy models.ProfilePrivacy) ProfilePrivacySettings {
+	return ProfilePrivacySettings{
+		HideAvatar:      privacy.HideAvatar,
+		HideBio:         privacy.HideBio,
+		HideCollections: privacy.HideCollections,
+		HideStats:       privacy.HideStats,
+		HideFollows:     privacy.HideFollows,
+		HideJoinDate:    privacy.HideJoinDate,
+	}
+}
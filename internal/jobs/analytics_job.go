@@ -0,0 +1,66 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/services"
+)
+
+const (
+	// analyticsInterval определяет, как часто пересчитываются дневные сводки
+	analyticsInterval = 15 * time.Minute
+	// staleSessionTimeout определяет, через сколько часов бездействия сессия считается брошенной
+	staleSessionTimeout = 6 * time.Hour
+)
+
+// AnalyticsJob периодически закрывает брошенные игровые сессии и пересчитывает
+// дневные сводки партий
+type AnalyticsJob struct {
+	gameService      services.GameService
+	analyticsService services.AnalyticsService
+}
+
+// NewAnalyticsJob создает новую фоновую задачу статистики
+func NewAnalyticsJob(gameService services.GameService, analyticsService services.AnalyticsService) *AnalyticsJob {
+	return &AnalyticsJob{
+		gameService:      gameService,
+		analyticsService: analyticsService,
+	}
+}
+
+// Start запускает задачу: первый проход выполняется сразу, затем по расписанию
+// до отмены контекста
+func (j *AnalyticsJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(analyticsInterval)
+	defer ticker.Stop()
+
+	for {
+		j.RunOnce()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce выполняет один проход задачи; ошибки только логируются, чтобы
+// следующий проход мог повторить попытку
+func (j *AnalyticsJob) RunOnce() {
+	closed, err := j.gameService.CloseStale(staleSessionTimeout)
+	if err != nil {
+		log.Printf("Error closing stale game sessions: %v", err)
+	} else if closed > 0 {
+		log.Printf("Closed %d stale game sessions", closed)
+	}
+
+	rows, err := j.analyticsService.Rollup()
+	if err != nil {
+		log.Printf("Error rolling up play analytics: %v", err)
+		return
+	}
+	log.Printf("Play analytics rolled up: %d daily stats updated", rows)
+}
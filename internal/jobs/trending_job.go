@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/services"
+)
+
+// TrendingJob периодически пересчитывает рейтинг популярных коллекций
+type TrendingJob struct {
+	trendingService services.TrendingService
+}
+
+// NewTrendingJob создает новую фоновую задачу пересчета популярности
+func NewTrendingJob(trendingService services.TrendingService) *TrendingJob {
+	return &TrendingJob{
+		trendingService: trendingService,
+	}
+}
+
+// Start запускает задачу: первый пересчет выполняется сразу, затем с интервалом
+// из конфигурации до отмены контекста
+func (j *TrendingJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.trendingService.RefreshInterval())
+	defer ticker.Stop()
+
+	for {
+		j.RunOnce()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce пересчитывает рейтинг; ошибка только логируется, прежний рейтинг остается в силе
+func (j *TrendingJob) RunOnce() {
+	startedAt := time.Now()
+	if err := j.trendingService.Refresh(); err != nil {
+		log.Printf("Error refreshing trending collections: %v", err)
+		return
+	}
+	log.Printf("Trending collections refreshed in %s", time.Since(startedAt).Round(time.Millisecond))
+}
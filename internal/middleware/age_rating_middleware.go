@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// SafeModeHeader включает семейный режим для запроса: показываются только коллекции 0+
+const SafeModeHeader = "X-Safe-Mode"
+
+// AgeRatingMiddleware определяет, коллекции какого возрастного рейтинга можно показывать в запросе
+type AgeRatingMiddleware struct {
+	userService services.UserService
+}
+
+// NewAgeRatingMiddleware создает новый экземпляр AgeRatingMiddleware
+func NewAgeRatingMiddleware(userService services.UserService) *AgeRatingMiddleware {
+	return &AgeRatingMiddleware{
+		userService: userService,
+	}
+}
+
+// Limit устанавливает в контекст наибольший допустимый рейтинг. Семейный режим включается
+// заголовком X-Safe-Mode или параметром ?safeMode=true; для пользователя с указанной датой
+// рождения рейтинг дополнительно ограничивается его возрастом.
+// Должен стоять после OptionalAuth или RequireAuth.
+func (m *AgeRatingMiddleware) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := models.AgeRating18
+
+		safeMode := c.GetHeader(SafeModeHeader)
+		if safeMode == "" {
+			safeMode = c.Query("safeMode")
+		}
+		if safeMode != "" {
+			enabled, err := strconv.ParseBool(safeMode)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid safe mode value"})
+				c.Abort()
+				return
+			}
+			if enabled {
+				limit = models.AgeRatingAll
+			}
+		}
+
+		if userID := GetUserID(c); userID != 0 && limit > models.AgeRatingAll {
+			if user, err := m.userService.GetByID(userID); err == nil {
+				if age, ok := user.Age(time.Now()); ok && models.MaxAgeRatingForAge(age) < limit {
+					limit = models.MaxAgeRatingForAge(age)
+				}
+			}
+		}
+
+		c.Set("maxAgeRating", limit)
+		c.Next()
+	}
+}
+
+// GetMaxAgeRating возвращает наибольший допустимый в запросе рейтинг.
+// Без AgeRatingMiddleware ограничений нет.
+func GetMaxAgeRating(c *gin.Context) int {
+	limit, exists := c.Get("maxAgeRating")
+	if !exists {
+		return models.AgeRating18
+	}
+	return limit.(int)
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/KoLili12/bulb-server/internal/services"
 	"github.com/gin-gonic/gin"
@@ -11,12 +12,14 @@ import (
 // AuthMiddleware представляет middleware для проверки аутентификации
 type AuthMiddleware struct {
 	authService services.AuthService
+	userService services.UserService
 }
 
 // NewAuthMiddleware создает новый экземпляр AuthMiddleware
-func NewAuthMiddleware(authService services.AuthService) *AuthMiddleware {
+func NewAuthMiddleware(authService services.AuthService, userService services.UserService) *AuthMiddleware {
 	return &AuthMiddleware{
 		authService: authService,
+		userService: userService,
 	}
 }
 
@@ -44,6 +47,9 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		if !m.checkActive(c, claims.UserID) {
+			return
+		}
 
 		// Устанавливаем ID пользователя в контекст
 		c.Set("userID", claims.UserID)
@@ -53,6 +59,59 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	}
 }
 
+// OptionalAuth устанавливает ID пользователя в контекст, если передан токен,
+// и пропускает запросы без заголовка Authorization как анонимные
+func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.Next()
+			return
+		}
+
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header format must be Bearer {token}"})
+			c.Abort()
+			return
+		}
+
+		// Неверный токен не превращаем в анонимный запрос, чтобы клиент узнал о проблеме
+		claims, err := m.authService.ValidateAccessToken(parts[1])
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			c.Abort()
+			return
+		}
+		if !m.checkActive(c, claims.UserID) {
+			return
+		}
+
+		c.Set("userID", claims.UserID)
+		c.Set("email", claims.Email)
+
+		c.Next()
+	}
+}
+
+// checkActive проверяет, что владелец токена существует и не заблокирован модератором.
+// Блокировка действует сразу, не дожидаясь истечения выданного до нее токена.
+// Если доступ запрещен, запрос прерывается и возвращается false.
+func (m *AuthMiddleware) checkActive(c *gin.Context, userID uint) bool {
+	user, err := m.userService.GetByID(userID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.Abort()
+		return false
+	}
+	if user.IsSuspended(time.Now()) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "User is suspended"})
+		c.Abort()
+		return false
+	}
+	return true
+}
+
 // GetUserID возвращает ID пользователя из контекста
 func GetUserID(c *gin.Context) uint {
 	userID, exists := c.Get("userID")
@@ -61,3 +120,12 @@ func GetUserID(c *gin.Context) uint {
 	}
 	return userID.(uint)
 }
+
+// GetOptionalUserID возвращает ID пользователя из контекста или nil для анонимного запроса
+func GetOptionalUserID(c *gin.Context) *uint {
+	userID := GetUserID(c)
+	if userID == 0 {
+		return nil
+	}
+	return &userID
+}
@@ -6,7 +6,8 @@ import (
     "gorm.io/gorm"
 )
 
-// ActionType представляет тип действия (Правда или Действие)
+// ActionType представляет тип действия. Допустимые типы и правила их валидации
+// описаны в реестре типов карточек (см. action_type.go)
 type ActionType string
 
 const (
@@ -14,15 +15,69 @@ const (
     ActionTypeDare  ActionType = "dare"
 )
 
+// Допустимые значения метаданных карточки
+const (
+    MinDifficulty = 1 // Легко
+    MaxDifficulty = 3 // Сложно
+
+    MinSpiciness = 0 // Для всех
+    MaxSpiciness = 3 // 18+
+
+    MaxTimerSeconds = 3600
+    MaxPoints       = 1000
+)
+
 // Action представляет действие в подборке
 type Action struct {
     ID           uint           `gorm:"primaryKey" json:"id"`
     Text         string         `json:"text" gorm:"not null"`
     Type         ActionType     `json:"type" gorm:"type:varchar(20);not null;default:'truth'"` // Новое поле для типа
+    Extra        ActionExtra    `json:"extra,omitempty" gorm:"type:jsonb"`                     // Дополнительные поля, зависящие от типа
     CollectionID uint           `json:"collectionId"`
     Collection   Collection     `json:"-" gorm:"foreignKey:CollectionID"`
     Order        int            `json:"order" gorm:"default:0"` // Порядок действий в подборке
+    Difficulty   int            `json:"difficulty" gorm:"not null;default:1;index"`  // Сложность от MinDifficulty до MaxDifficulty
+    Spiciness    int            `json:"spiciness" gorm:"not null;default:0;index"`   // Уровень откровенности, MaxSpiciness - только 18+
+    TimerSeconds int            `json:"timerSeconds" gorm:"not null;default:0"`      // Время на выполнение, 0 - без таймера
+    Points       int            `json:"points" gorm:"not null;default:0"`            // Очки за выполнение
     CreatedAt    time.Time      `json:"createdAt"`
     UpdatedAt    time.Time      `json:"updatedAt"`
     DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// ActionFilter задает условия отбора карточек коллекции. Нулевые значения не ограничивают выборку.
+type ActionFilter struct {
+    Type          ActionType
+    MinDifficulty int
+    MaxDifficulty int
+    MinSpiciness  *int
+    MaxSpiciness  *int
+    TimedOnly     bool
+    MinPoints     int
+}
+
+// Matches проверяет, удовлетворяет ли карточка фильтру
+func (f ActionFilter) Matches(action *Action) bool {
+    if f.Type != "" && action.Type != f.Type {
+        return false
+    }
+    if f.MinDifficulty > 0 && action.Difficulty < f.MinDifficulty {
+        return false
+    }
+    if f.MaxDifficulty > 0 && action.Difficulty > f.MaxDifficulty {
+        return false
+    }
+    if f.MinSpiciness != nil && action.Spiciness < *f.MinSpiciness {
+        return false
+    }
+    if f.MaxSpiciness != nil && action.Spiciness > *f.MaxSpiciness {
+        return false
+    }
+    if f.TimedOnly && action.TimerSeconds == 0 {
+        return false
+    }
+    if f.MinPoints > 0 && action.Points < f.MinPoints {
+        return false
+    }
+    return true
 }
\ No newline at end of file
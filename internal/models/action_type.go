@@ -0,0 +1,256 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrUnknownActionType = errors.New("unknown action type")
+	ErrInvalidActionData = errors.New("invalid action data")
+)
+
+const (
+	ActionTypeNeverHaveIEver ActionType = "never_have_i_ever"
+	ActionTypeWouldYouRather ActionType = "would_you_rather"
+	ActionTypeChallenge      ActionType = "challenge"
+	ActionTypePenalty        ActionType = "penalty"
+)
+
+// ActionFieldKind определяет тип значения дополнительного поля карточки
+type ActionFieldKind string
+
+const (
+	ActionFieldString ActionFieldKind = "string"
+	ActionFieldInt    ActionFieldKind = "int"
+)
+
+// ActionTypeField описывает дополнительное поле, которое поддерживает тип карточки
+type ActionTypeField struct {
+	Name        string
+	Kind        ActionFieldKind
+	Required    bool
+	Description string
+}
+
+// ActionTypeDefinition описывает тип карточки и правила его валидации
+type ActionTypeDefinition struct {
+	Type        ActionType
+	Name        string
+	Description string
+	Fields      []ActionTypeField
+	// Validate выполняет дополнительную проверку карточки (может быть nil)
+	Validate func(action *Action) error
+}
+
+// ActionExtra содержит значения дополнительных полей карточки
+type ActionExtra map[string]string
+
+// Keys возвращает имена дополнительных полей в алфавитном порядке. Обход по Keys дает
+// одинаковый порядок при каждом вызове, в отличие от обхода самого map.
+func (e ActionExtra) Keys() []string {
+	keys := make([]string, 0, len(e))
+	for name := range e {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Value сериализует дополнительные поля в JSON для хранения в базе данных
+func (e ActionExtra) Value() (driver.Value, error) {
+	if len(e) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan восстанавливает дополнительные поля из JSON, хранящегося в базе данных
+func (e *ActionExtra) Scan(value interface{}) error {
+	if value == nil {
+		*e = nil
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for ActionExtra: %T", value)
+	}
+
+	return json.Unmarshal(data, e)
+}
+
+// actionTypeRegistry хранит все зарегистрированные типы карточек
+var actionTypeRegistry = struct {
+	sync.RWMutex
+	types map[ActionType]*ActionTypeDefinition
+	order []ActionType
+}{
+	types: make(map[ActionType]*ActionTypeDefinition),
+}
+
+func init() {
+	RegisterActionType(ActionTypeDefinition{
+		Type:        ActionTypeTruth,
+		Name:        "Правда",
+		Description: "Вопрос, на который нужно честно ответить",
+	})
+	RegisterActionType(ActionTypeDefinition{
+		Type:        ActionTypeDare,
+		Name:        "Действие",
+		Description: "Задание, которое нужно выполнить",
+	})
+	RegisterActionType(ActionTypeDefinition{
+		Type:        ActionTypeNeverHaveIEver,
+		Name:        "Я никогда не",
+		Description: "Утверждение, в ответ на которое признаются те, кто это делал",
+	})
+	RegisterActionType(ActionTypeDefinition{
+		Type:        ActionTypeWouldYouRather,
+		Name:        "Что бы ты выбрал",
+		Description: "Выбор между двумя вариантами",
+		Fields: []ActionTypeField{
+			{Name: "optionA", Kind: ActionFieldString, Required: true, Description: "Первый вариант"},
+			{Name: "optionB", Kind: ActionFieldString, Required: true, Description: "Второй вариант"},
+		},
+		Validate: func(action *Action) error {
+			if strings.EqualFold(action.Extra["optionA"], action.Extra["optionB"]) {
+				return fmt.Errorf("%w: options must differ", ErrInvalidActionData)
+			}
+			return nil
+		},
+	})
+	RegisterActionType(ActionTypeDefinition{
+		Type:        ActionTypeChallenge,
+		Name:        "Испытание",
+		Description: "Задание с измеримым результатом",
+		Fields: []ActionTypeField{
+			{Name: "goal", Kind: ActionFieldString, Description: "Условие успешного выполнения"},
+			{Name: "attempts", Kind: ActionFieldInt, Description: "Количество попыток"},
+		},
+	})
+	RegisterActionType(ActionTypeDefinition{
+		Type:        ActionTypePenalty,
+		Name:        "Штраф",
+		Description: "Наказание за отказ или проигрыш",
+	})
+}
+
+// RegisterActionType добавляет новый тип карточки в реестр.
+// Повторная регистрация одного и того же типа приводит к панике.
+func RegisterActionType(def ActionTypeDefinition) {
+	actionTypeRegistry.Lock()
+	defer actionTypeRegistry.Unlock()
+
+	if _, exists := actionTypeRegistry.types[def.Type]; exists {
+		panic(fmt.Sprintf("action type %q is already registered", def.Type))
+	}
+
+	actionTypeRegistry.types[def.Type] = &def
+	actionTypeRegistry.order = append(actionTypeRegistry.order, def.Type)
+}
+
+// LookupActionType возвращает описание типа карточки
+func LookupActionType(actionType ActionType) (*ActionTypeDefinition, bool) {
+	actionTypeRegistry.RLock()
+	defer actionTypeRegistry.RUnlock()
+
+	def, ok := actionTypeRegistry.types[actionType]
+	return def, ok
+}
+
+// ActionTypes возвращает все зарегистрированные типы карточек в порядке регистрации
+func ActionTypes() []*ActionTypeDefinition {
+	actionTypeRegistry.RLock()
+	defer actionTypeRegistry.RUnlock()
+
+	defs := make([]*ActionTypeDefinition, 0, len(actionTypeRegistry.order))
+	for _, t := range actionTypeRegistry.order {
+		defs = append(defs, actionTypeRegistry.types[t])
+	}
+	return defs
+}
+
+// IsValidActionType проверяет, зарегистрирован ли тип карточки
+func IsValidActionType(actionType ActionType) bool {
+	_, ok := LookupActionType(actionType)
+	return ok
+}
+
+// ValidateAction проверяет тип карточки и ее дополнительные поля по реестру
+func ValidateAction(action *Action) error {
+	def, ok := LookupActionType(action.Type)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownActionType, action.Type)
+	}
+	return def.ValidateAction(action)
+}
+
+// ValidateAction проверяет карточку по правилам данного типа
+func (d *ActionTypeDefinition) ValidateAction(action *Action) error {
+	if strings.TrimSpace(action.Text) == "" {
+		return fmt.Errorf("%w: text is required", ErrInvalidActionData)
+	}
+	if err := validateActionMetadata(action); err != nil {
+		return err
+	}
+
+	known := make(map[string]ActionTypeField, len(d.Fields))
+	for _, field := range d.Fields {
+		known[field.Name] = field
+
+		value, present := action.Extra[field.Name]
+		if field.Required && strings.TrimSpace(value) == "" {
+			return fmt.Errorf("%w: field %s is required for type %s", ErrInvalidActionData, field.Name, d.Type)
+		}
+		if present && field.Kind == ActionFieldInt {
+			if _, err := strconv.Atoi(value); err != nil {
+				return fmt.Errorf("%w: field %s must be an integer", ErrInvalidActionData, field.Name)
+			}
+		}
+	}
+
+	// Поля, не объявленные для типа, не допускаются
+	for name := range action.Extra {
+		if _, ok := known[name]; !ok {
+			return fmt.Errorf("%w: field %s is not supported for type %s", ErrInvalidActionData, name, d.Type)
+		}
+	}
+
+	if d.Validate != nil {
+		return d.Validate(action)
+	}
+	return nil
+}
+
+// validateActionMetadata проверяет общие для всех типов метаданные карточки
+func validateActionMetadata(action *Action) error {
+	if action.Difficulty < MinDifficulty || action.Difficulty > MaxDifficulty {
+		return fmt.Errorf("%w: difficulty must be between %d and %d", ErrInvalidActionData, MinDifficulty, MaxDifficulty)
+	}
+	if action.Spiciness < MinSpiciness || action.Spiciness > MaxSpiciness {
+		return fmt.Errorf("%w: spiciness must be between %d and %d", ErrInvalidActionData, MinSpiciness, MaxSpiciness)
+	}
+	if action.TimerSeconds < 0 || action.TimerSeconds > MaxTimerSeconds {
+		return fmt.Errorf("%w: timerSeconds must be between 0 and %d", ErrInvalidActionData, MaxTimerSeconds)
+	}
+	if action.Points < 0 || action.Points > MaxPoints {
+		return fmt.Errorf("%w: points must be between 0 and %d", ErrInvalidActionData, MaxPoints)
+	}
+	return nil
+}
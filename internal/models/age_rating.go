@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// Возрастные рейтинги коллекций - минимальный возраст игроков
+const (
+	AgeRatingAll = 0
+	AgeRating12  = 12
+	AgeRating16  = 16
+	AgeRating18  = 18
+)
+
+// ageRatings перечисляет допустимые рейтинги по возрастанию
+var ageRatings = []int{AgeRatingAll, AgeRating12, AgeRating16, AgeRating18}
+
+// IsValidAgeRating проверяет, что рейтинг входит в шкалу
+func IsValidAgeRating(rating int) bool {
+	for _, r := range ageRatings {
+		if r == rating {
+			return true
+		}
+	}
+	return false
+}
+
+// AgeRatingForSpiciness возвращает рейтинг, которого требует уровень откровенности карточки
+func AgeRatingForSpiciness(spiciness int) int {
+	switch {
+	case spiciness >= MaxSpiciness:
+		return AgeRating18
+	case spiciness <= MinSpiciness:
+		return AgeRatingAll
+	}
+	return ageRatings[spiciness]
+}
+
+// MaxAgeRatingForAge возвращает наибольший рейтинг, доступный в этом возрасте
+func MaxAgeRatingForAge(age int) int {
+	allowed := AgeRatingAll
+	for _, r := range ageRatings {
+		if r <= age {
+			allowed = r
+		}
+	}
+	return allowed
+}
+
+// ResolveAgeRating пересчитывает рейтинг коллекции: наибольший из рейтинга самой
+// откровенной карточки, рейтинга, заявленного автором, и пометки фильтра контента
+func (c *Collection) ResolveAgeRating(maxSpiciness int) {
+	rating := AgeRatingForSpiciness(maxSpiciness)
+	if c.DeclaredAgeRating > rating {
+		rating = c.DeclaredAgeRating
+	}
+	if c.AutoAdult {
+		rating = AgeRating18
+	}
+	c.AgeRating = rating
+}
+
+// Age возвращает полное число лет пользователя на момент now; false, если дата рождения не указана
+func (u *User) Age(now time.Time) (int, bool) {
+	if u.BirthDate == nil {
+		return 0, false
+	}
+	birth := *u.BirthDate
+	age := now.Year() - birth.Year()
+	if now.Month() < birth.Month() || (now.Month() == birth.Month() && now.Day() < birth.Day()) {
+		age--
+	}
+	return age, true
+}
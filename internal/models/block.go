@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// UserBlock представляет блокировку пользователя: заблокированный не может комментировать
+// коллекции блокирующего, предлагать к ним карточки, смешивать их в свои колоды, подписываться
+// на него и входить в его комнаты, а его контент не показывается блокирующему в списках
+type UserBlock struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	BlockerID uint      `json:"blockerId" gorm:"not null;uniqueIndex:idx_user_blocks_blocker_blocked"`
+	BlockedID uint      `json:"blockedId" gorm:"not null;index;uniqueIndex:idx_user_blocks_blocker_blocked"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+// Допустимые значения голоса за карточку
+const (
+	VoteUp   = 1
+	VoteDown = -1
+)
+
+// ActionVote представляет голос пользователя за карточку (один на пользователя)
+type ActionVote struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ActionID  uint      `json:"actionId" gorm:"not null;index;uniqueIndex:idx_action_votes_user_action"`
+	UserID    uint      `json:"userId" gorm:"not null;uniqueIndex:idx_action_votes_user_action"`
+	Value     int       `json:"value" gorm:"not null"` // VoteUp или VoteDown
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CardQuality содержит обратную связь по карточке: голоса и исходы в игровых сессиях
+type CardQuality struct {
+	ActionID  uint
+	Upvotes   int
+	Downvotes int
+	Draws     int // Сколько раз карточка выпадала
+	Completed int
+	Skipped   int
+}
+
+// SkipRate возвращает долю пропусков среди выполненных и пропущенных розыгрышей карточки
+func (q *CardQuality) SkipRate() float64 {
+	resolved := q.Completed + q.Skipped
+	if resolved == 0 {
+		return 0
+	}
+	return float64(q.Skipped) / float64(resolved)
+}
+
+// Score возвращает оценку качества карточки от 0 до 1. Доли одобрения и выполнения
+// сглажены по Лапласу, поэтому карточка без обратной связи получает нейтральные 0.5.
+func (q *CardQuality) Score() float64 {
+	approval := float64(q.Upvotes+1) / float64(q.Upvotes+q.Downvotes+2)
+	completion := float64(q.Completed+1) / float64(q.Completed+q.Skipped+2)
+	return (approval + completion) / 2
+}
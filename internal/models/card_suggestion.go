@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+// SuggestionStatus представляет состояние предложенной карточки
+type SuggestionStatus string
+
+const (
+	SuggestionPending  SuggestionStatus = "pending"  // Ждет решения владельца коллекции
+	SuggestionAccepted SuggestionStatus = "accepted" // Карточка добавлена в коллекцию
+	SuggestionRejected SuggestionStatus = "rejected" // Владелец отклонил предложение
+)
+
+// IsValid проверяет, что статус предложения известен
+func (s SuggestionStatus) IsValid() bool {
+	switch s {
+	case SuggestionPending, SuggestionAccepted, SuggestionRejected:
+		return true
+	}
+	return false
+}
+
+// CardSuggestion представляет карточку, предложенную пользователем в чужую коллекцию
+type CardSuggestion struct {
+	ID           uint             `gorm:"primaryKey" json:"id"`
+	CollectionID uint             `json:"collectionId" gorm:"not null;index"`
+	Collection   Collection       `json:"-" gorm:"foreignKey:CollectionID"`
+	UserID       uint             `json:"userId" gorm:"not null;index"` // Автор предложения
+	User         User             `json:"user" gorm:"foreignKey:UserID"`
+	Text         string           `json:"text" gorm:"not null"`
+	Type         ActionType       `json:"type" gorm:"type:varchar(20);not null"`
+	Extra        ActionExtra      `json:"extra,omitempty" gorm:"type:jsonb"`
+	Difficulty   int              `json:"difficulty" gorm:"not null;default:1"`
+	Spiciness    int              `json:"spiciness" gorm:"not null;default:0"`
+	TimerSeconds int              `json:"timerSeconds" gorm:"not null;default:0"`
+	Points       int              `json:"points" gorm:"not null;default:0"`
+	Status       SuggestionStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	RejectReason string           `json:"rejectReason,omitempty"`
+	ActionID     *uint            `json:"actionId,omitempty"` // Карточка, созданная при принятии
+	ReviewedAt   *time.Time       `json:"reviewedAt,omitempty"`
+	CreatedAt    time.Time        `json:"createdAt"`
+	UpdatedAt    time.Time        `json:"updatedAt"`
+}
+
+// ToAction создает карточку из предложения
+func (s *CardSuggestion) ToAction() *Action {
+	return &Action{
+		Text:         s.Text,
+		Type:         s.Type,
+		Extra:        s.Extra,
+		Difficulty:   s.Difficulty,
+		Spiciness:    s.Spiciness,
+		TimerSeconds: s.TimerSeconds,
+		Points:       s.Points,
+	}
+}
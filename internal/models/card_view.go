@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// CardView хранит, сколько раз пользователь или комната видели карточку.
+// Заполняется при каждом ходе игровой сессии и переживает отдельные игры.
+type CardView struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       *uint     `json:"userId,omitempty" gorm:"uniqueIndex:idx_card_views_user_action"` // Заполнен для просмотров пользователя
+	RoomID       *uint     `json:"roomId,omitempty" gorm:"uniqueIndex:idx_card_views_room_action"` // Заполнен для просмотров комнаты
+	CollectionID uint      `json:"collectionId" gorm:"index;not null"`
+	ActionID     uint      `json:"actionId" gorm:"not null;uniqueIndex:idx_card_views_user_action;uniqueIndex:idx_card_views_room_action"`
+	TimesSeen    int       `json:"timesSeen" gorm:"not null;default:1"`
+	FirstSeenAt  time.Time `json:"firstSeenAt"`
+	LastSeenAt   time.Time `json:"lastSeenAt"`
+}
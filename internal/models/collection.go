@@ -6,17 +6,66 @@ import (
 	"gorm.io/gorm"
 )
 
+// CollectionSort задает порядок списка коллекций
+type CollectionSort string
+
+const (
+	CollectionSortDefault CollectionSort = ""
+	CollectionSortNewest  CollectionSort = "newest"
+	CollectionSortPopular CollectionSort = "popular"
+	CollectionSortRating  CollectionSort = "rating"
+)
+
+// IsValid проверяет, что порядок сортировки поддерживается
+func (s CollectionSort) IsValid() bool {
+	switch s {
+	case CollectionSortDefault, CollectionSortNewest, CollectionSortPopular, CollectionSortRating:
+		return true
+	}
+	return false
+}
+
+// CollectionVisibility ограничивает публичные списки коллекций и доступ к их карточкам
+// для конкретного запроса
+type CollectionVisibility struct {
+	MaxAgeRating int   // Наибольший допустимый возрастной рейтинг
+	ViewerID     *uint // Коллекции заблокированных им авторов не показываются; nil - без авторизации
+}
+
+// Allows проверяет, можно ли показать коллекцию в запросе: скрытую модератором коллекцию
+// и коллекцию с рейтингом выше MaxAgeRating видит только ее владелец
+func (v CollectionVisibility) Allows(collection *Collection) bool {
+	if v.ViewerID != nil && *v.ViewerID == collection.UserID {
+		return true
+	}
+	return collection.HiddenAt == nil && collection.AgeRating <= v.MaxAgeRating
+}
+
 // Collection представляет подборку в системе
 type Collection struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	Name        string         `json:"name"`
-	Description string         `json:"description"`
-	ImageURL    string         `json:"imageUrl"`
-	UserID      uint           `json:"userId"`
-	User        User           `json:"user" gorm:"foreignKey:UserID"`
-	Actions     []*Action      `json:"actions,omitempty" gorm:"foreignKey:CollectionID"`
-	PlayCount   int            `json:"playCount" gorm:"default:0"`
-	CreatedAt   time.Time      `json:"createdAt"`
-	UpdatedAt   time.Time      `json:"updatedAt"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID            uint             `gorm:"primaryKey" json:"id"`
+	Name          string           `json:"name"`
+	Description   string           `json:"description"`
+	ImageURL      string           `json:"imageUrl"`
+	UserID        uint             `json:"userId" gorm:"index:idx_collections_user_updated,priority:1"`
+	User          User             `json:"user" gorm:"foreignKey:UserID"`
+	Actions       []*Action        `json:"actions,omitempty" gorm:"foreignKey:CollectionID"`
+	PlayCount     int              `json:"playCount" gorm:"default:0"`
+	LikeCount     int              `json:"likeCount" gorm:"not null;default:0"`
+	FavoriteCount int              `json:"favoriteCount" gorm:"not null;default:0"`
+	RatingCount   int              `json:"ratingCount" gorm:"not null;default:0"`
+	RatingSum     int              `json:"ratingSum" gorm:"not null;default:0"`
+	IsVirtual     bool             `json:"isVirtual" gorm:"default:false"` // Карточки собираются из MixSources
+	MixSources    []*DeckMixSource `json:"mixSources,omitempty" gorm:"foreignKey:MixCollectionID"`
+	MixCount      int              `json:"mixCount,omitempty"`
+	MixTruthRatio *float64         `json:"mixTruthRatio,omitempty"`
+	MixSeed       int64            `json:"mixSeed,omitempty"`
+	HiddenAt      *time.Time       `json:"hiddenAt,omitempty" gorm:"index"`         // Скрыта модератором из публичного доступа
+	AutoAdult     bool             `json:"autoAdult" gorm:"not null;default:false"` // Фильтр контента нашел 18+ в названии или описании
+	// Заявленный автором возрастной рейтинг и итоговый рейтинг с учетом карточек (см. ResolveAgeRating)
+	DeclaredAgeRating int            `json:"declaredAgeRating" gorm:"not null;default:0"`
+	AgeRating         int            `json:"ageRating" gorm:"not null;default:0;index"`
+	CreatedAt         time.Time      `json:"createdAt"`
+	UpdatedAt         time.Time      `json:"updatedAt" gorm:"index:idx_collections_user_updated,priority:2"` // Лента подписок
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
 }
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// CollectionLike представляет отметку "нравится" пользователя на коллекции
+type CollectionLike struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `json:"userId" gorm:"not null;uniqueIndex:idx_collection_likes_user_collection"`
+	CollectionID uint      `json:"collectionId" gorm:"not null;index;uniqueIndex:idx_collection_likes_user_collection"`
+	CreatedAt    time.Time `json:"createdAt" gorm:"index"`
+}
+
+// CollectionFavorite представляет коллекцию, добавленную пользователем в избранное
+type CollectionFavorite struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `json:"userId" gorm:"not null;uniqueIndex:idx_collection_favorites_user_collection"`
+	CollectionID uint      `json:"collectionId" gorm:"not null;index;uniqueIndex:idx_collection_favorites_user_collection"`
+	CreatedAt    time.Time `json:"createdAt" gorm:"index"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CommentMention хранит упомянутого пользователя с именем на момент написания комментария
+type CommentMention struct {
+	UserID uint   `json:"userId"`
+	Name   string `json:"name"`
+}
+
+// CommentMentions хранит упоминания в виде JSON-массива
+type CommentMentions []CommentMention
+
+// Value сериализует упоминания в JSON для хранения в базе данных
+func (m CommentMentions) Value() (driver.Value, error) {
+	if m == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan восстанавливает упоминания из JSON, хранящегося в базе данных
+func (m *CommentMentions) Scan(value interface{}) error {
+	if value == nil {
+		*m = nil
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for CommentMentions: %T", value)
+	}
+
+	return json.Unmarshal(data, m)
+}
+
+// Comment представляет комментарий к коллекции. Ответы образуют дерево: ParentID
+// указывает на комментарий, на который ответили, RootID - на начало ветки.
+// Удаленный комментарий остается в базе (RemovedAt), чтобы ветка не распадалась.
+type Comment struct {
+	ID           uint            `gorm:"primaryKey" json:"id"`
+	CollectionID uint            `json:"collectionId" gorm:"not null;index"`
+	UserID       uint            `json:"userId" gorm:"not null;index"`
+	User         User            `json:"user" gorm:"foreignKey:UserID"`
+	ParentID     *uint           `json:"parentId,omitempty" gorm:"index"`
+	RootID       *uint           `json:"rootId,omitempty" gorm:"index"` // Пусто у комментария верхнего уровня
+	Text         string          `json:"text" gorm:"type:text;not null"`
+	Mentions     CommentMentions `json:"mentions,omitempty" gorm:"type:jsonb"`
+	ReplyCount   int             `json:"replyCount" gorm:"not null;default:0"` // Ответов во всей ветке (только у корня)
+	IsPinned     bool            `json:"isPinned" gorm:"not null;default:false"`
+	PinnedAt     *time.Time      `json:"pinnedAt,omitempty"`
+	EditedAt     *time.Time      `json:"editedAt,omitempty"`
+	RemovedAt    *time.Time      `json:"removedAt,omitempty"`
+	CreatedAt    time.Time       `json:"createdAt"`
+	UpdatedAt    time.Time       `json:"updatedAt"`
+}
+
+// IsRemoved проверяет, удален ли комментарий
+func (c *Comment) IsRemoved() bool {
+	return c.RemovedAt != nil
+}
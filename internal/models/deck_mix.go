@@ -0,0 +1,9 @@
+package models
+
+// DeckMixSource представляет источник карточек виртуальной коллекции-микса
+type DeckMixSource struct {
+	ID                 uint    `gorm:"primaryKey" json:"id"`
+	MixCollectionID    uint    `json:"mixCollectionId" gorm:"index;not null"`
+	SourceCollectionID uint    `json:"sourceCollectionId" gorm:"not null"`
+	Weight             float64 `json:"weight" gorm:"not null;default:1"`
+}
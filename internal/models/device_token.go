@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// DevicePlatform определяет платформу устройства, от нее зависит сервис доставки push
+type DevicePlatform string
+
+const (
+	DevicePlatformAndroid DevicePlatform = "android" // Firebase Cloud Messaging
+	DevicePlatformIOS     DevicePlatform = "ios"     // Apple Push Notification service
+)
+
+// IsValid проверяет, что платформа устройства известна
+func (p DevicePlatform) IsValid() bool {
+	switch p {
+	case DevicePlatformAndroid, DevicePlatformIOS:
+		return true
+	}
+	return false
+}
+
+// DeviceToken представляет push-токен устройства, на котором пользователь вошел в приложение.
+// Токен принадлежит одному пользователю: вход под другим аккаунтом на том же устройстве
+// переносит токен. Токены, отклоненные сервисом доставки, удаляются.
+type DeviceToken struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	UserID     uint           `json:"userId" gorm:"not null;index"`
+	Platform   DevicePlatform `json:"platform" gorm:"type:varchar(10);not null"`
+	Token      string         `json:"token" gorm:"type:varchar(512);not null;uniqueIndex"`
+	LastSeenAt time.Time      `json:"lastSeenAt"` // Последняя регистрация токена приложением
+	CreatedAt  time.Time      `json:"createdAt"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// UserFollow представляет подписку пользователя на автора коллекций
+type UserFollow struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	FollowerID uint      `json:"followerId" gorm:"not null;uniqueIndex:idx_user_follows_follower_followee"`
+	FolloweeID uint      `json:"followeeId" gorm:"not null;index;uniqueIndex:idx_user_follows_follower_followee"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"index"`
+}
+
+// FeedCursor указывает позицию в ленте подписок: следующая страница начинается
+// с коллекций, измененных раньше UpdatedAt (при равном времени - с меньшим ID)
+type FeedCursor struct {
+	UpdatedAt    time.Time
+	CollectionID uint
+}
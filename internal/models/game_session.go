@@ -0,0 +1,170 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// GameSessionStatus представляет состояние игровой сессии
+type GameSessionStatus string
+
+const (
+	GameSessionActive   GameSessionStatus = "active"
+	GameSessionFinished GameSessionStatus = "finished"
+	// GameSessionAbandoned - сессия закрыта автоматически после долгого бездействия
+	GameSessionAbandoned GameSessionStatus = "abandoned"
+)
+
+// DrawOutcome представляет исход хода
+type DrawOutcome string
+
+const (
+	DrawPending   DrawOutcome = "pending"   // Игрок еще не выполнил задание
+	DrawCompleted DrawOutcome = "completed" // Задание выполнено, очки начислены
+	DrawSkipped   DrawOutcome = "skipped"   // Задание пропущено, применен штраф
+)
+
+// GameRules описывает правила подсчета очков в сессии
+type GameRules struct {
+	PointsByType        map[ActionType]int `json:"pointsByType,omitempty"`        // Очки за выполнение карточки типа без собственных очков
+	DefaultPoints       int                `json:"defaultPoints"`                 // Очки за карточку, если тип не указан в PointsByType
+	SkipPenalty         int                `json:"skipPenalty"`                   // Сколько очков списывается за пропуск
+	SkipLimit           int                `json:"skipLimit"`                     // Сколько пропусков доступно игроку; 0 - без ограничений
+	PenaltyCollectionID *uint              `json:"penaltyCollectionId,omitempty"` // Коллекция штрафных карточек за пропуск
+}
+
+// Value сериализует правила в JSON для хранения в базе данных
+func (r GameRules) Value() (driver.Value, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan восстанавливает правила из JSON, хранящегося в базе данных
+func (r *GameRules) Scan(value interface{}) error {
+	if value == nil {
+		*r = GameRules{}
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for GameRules: %T", value)
+	}
+
+	return json.Unmarshal(data, r)
+}
+
+// IDList хранит список идентификаторов в виде JSON-массива
+type IDList []uint
+
+// Value сериализует список в JSON для хранения в базе данных
+func (l IDList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan восстанавливает список из JSON, хранящегося в базе данных
+func (l *IDList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for IDList: %T", value)
+	}
+
+	return json.Unmarshal(data, l)
+}
+
+// GameSession представляет партию, сыгранную по коллекции
+type GameSession struct {
+	ID              string            `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	CollectionID    uint              `json:"collectionId" gorm:"index;not null"`
+	UserID          *uint             `json:"userId,omitempty" gorm:"index"` // Пустой для анонимной игры
+	RoomID          *uint             `json:"roomId,omitempty" gorm:"index"` // Комната, в которой идет игра
+	Status          GameSessionStatus `json:"status" gorm:"type:varchar(20);not null;default:'active'"`
+	Seed            int64             `json:"seed"`
+	Round           int               `json:"round" gorm:"not null;default:1"` // Круг колоды, после исчерпания колода перемешивается заново
+	Turn            int               `json:"turn" gorm:"not null;default:0"`  // Количество сделанных ходов
+	DeckOrder       IDList            `json:"-" gorm:"type:jsonb"`             // Порядок карточек в текущем круге
+	Rules           GameRules         `json:"rules" gorm:"type:jsonb"`
+	PreferUnseen    bool              `json:"preferUnseen"`    // Сначала выдавать карточки, которых участники еще не видели
+	QualityWeighted bool              `json:"qualityWeighted"` // Колода перемешивается с учетом качества карточек
+	Players         []*GamePlayer     `json:"players" gorm:"foreignKey:SessionID"`
+	Draws           []*GameDraw       `json:"draws,omitempty" gorm:"foreignKey:SessionID"`
+	StartedAt       time.Time         `json:"startedAt"`
+	EndedAt         *time.Time        `json:"endedAt,omitempty"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	UpdatedAt       time.Time         `json:"updatedAt"`
+}
+
+// GamePlayer представляет участника игровой сессии
+type GamePlayer struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	SessionID string     `json:"sessionId" gorm:"type:varchar(36);index;not null"`
+	UserID    *uint      `json:"userId,omitempty" gorm:"index"`   // Зарегистрированный пользователь, за которым сохраняется результат
+	MemberID  *uint      `json:"memberId,omitempty" gorm:"index"` // Участник комнаты, который ходит за этого игрока
+	Name      string     `json:"name" gorm:"not null"`
+	Position  int        `json:"position"` // Порядок хода
+	Score     int        `json:"score" gorm:"not null;default:0"`
+	Completed int        `json:"completed" gorm:"not null;default:0"`
+	Skipped   int        `json:"skipped" gorm:"not null;default:0"`
+	LeftAt    *time.Time `json:"leftAt,omitempty"` // Игрок вышел: очки сохраняются, но он больше не ходит
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// GameDraw представляет карточку, вытянутую в ходе игры
+type GameDraw struct {
+	ID        uint        `gorm:"primaryKey" json:"id"`
+	SessionID string      `json:"sessionId" gorm:"type:varchar(36);index;not null"`
+	ActionID  uint        `json:"actionId" gorm:"not null;index"`
+	PlayerID  uint        `json:"playerId"`
+	Round     int         `json:"round"`
+	Turn      int         `json:"turn"`
+	Type      ActionType  `json:"type" gorm:"type:varchar(20)"`
+	Text      string      `json:"text"` // Текст с подставленными именами игроков
+	Outcome   DrawOutcome `json:"outcome" gorm:"type:varchar(20);not null;default:'pending'"`
+	Points    int         `json:"points"` // Начисленные (или списанные за пропуск) очки
+	// Штрафная карточка, выданная за пропуск
+	PenaltyActionID *uint     `json:"penaltyActionId,omitempty"`
+	PenaltyText     string    `json:"penaltyText,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// GameResult хранит итог завершенной сессии для зарегистрированного игрока
+type GameResult struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	SessionID    string    `json:"sessionId" gorm:"type:varchar(36);index;not null"`
+	UserID       uint      `json:"userId" gorm:"index;not null"`
+	CollectionID uint      `json:"collectionId" gorm:"index;not null"`
+	PlayerName   string    `json:"playerName"`
+	Score        int       `json:"score"`
+	Completed    int       `json:"completed"`
+	Skipped      int       `json:"skipped"`
+	Rank         int       `json:"rank"` // Место в итоговой таблице, 1 - победитель
+	PlayerCount  int       `json:"playerCount"`
+	FinishedAt   time.Time `json:"finishedAt"`
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+)
+
+// ReportTargetType определяет, на что подана жалоба
+type ReportTargetType string
+
+const (
+	ReportTargetCollection ReportTargetType = "collection"
+	ReportTargetAction     ReportTargetType = "action"
+	ReportTargetComment    ReportTargetType = "comment"
+	ReportTargetUser       ReportTargetType = "user"
+)
+
+// ReportReason представляет код причины жалобы
+type ReportReason string
+
+const (
+	ReportReasonSpam       ReportReason = "spam"
+	ReportReasonHarassment ReportReason = "harassment"
+	ReportReasonHate       ReportReason = "hate"
+	ReportReasonSexual     ReportReason = "sexual"   // Откровенный контент без пометки 18+ или с участием несовершеннолетних
+	ReportReasonViolence   ReportReason = "violence" // Насилие или опасные задания
+	ReportReasonIllegal    ReportReason = "illegal"
+	ReportReasonOther      ReportReason = "other"
+
+	// ReportReasonContentFilter - автоматическая жалоба фильтра контента, пользователи ее не выбирают
+	ReportReasonContentFilter ReportReason = "content_filter"
+)
+
+// IsValid проверяет, что пользователь может указать эту причину жалобы
+func (r ReportReason) IsValid() bool {
+	switch r {
+	case ReportReasonSpam, ReportReasonHarassment, ReportReasonHate, ReportReasonSexual,
+		ReportReasonViolence, ReportReasonIllegal, ReportReasonOther:
+		return true
+	}
+	return false
+}
+
+// ReportStatus представляет состояние жалобы в очереди модерации
+type ReportStatus string
+
+const (
+	ReportOpen     ReportStatus = "open"     // Ждет модератора
+	ReportClaimed  ReportStatus = "claimed"  // Модератор взял жалобу в работу
+	ReportResolved ReportStatus = "resolved" // Решение принято
+)
+
+// IsValid проверяет, что статус жалобы известен
+func (s ReportStatus) IsValid() bool {
+	switch s {
+	case ReportOpen, ReportClaimed, ReportResolved:
+		return true
+	}
+	return false
+}
+
+// ModerationAction представляет решение или действие модератора
+type ModerationAction string
+
+const (
+	ModerationClaim   ModerationAction = "claim"
+	ModerationRelease ModerationAction = "release"
+	ModerationDismiss ModerationAction = "dismiss" // Нарушения нет
+	ModerationHide    ModerationAction = "hide"    // Скрыть контент из публичного доступа
+	ModerationDelete  ModerationAction = "delete"  // Удалить контент
+	ModerationWarn    ModerationAction = "warn"    // Предупредить автора
+	ModerationSuspend ModerationAction = "suspend" // Заблокировать автора на срок
+	ModerationUnhide  ModerationAction = "unhide"  // Вернуть скрытую коллекцию в публичный доступ
+)
+
+// IsResolution проверяет, что действие завершает разбор жалобы
+func (a ModerationAction) IsResolution() bool {
+	switch a {
+	case ModerationDismiss, ModerationHide, ModerationDelete, ModerationWarn, ModerationSuspend:
+		return true
+	}
+	return false
+}
+
+// Report представляет жалобу пользователя на контент или другого пользователя
+type Report struct {
+	ID             uint             `gorm:"primaryKey" json:"id"`
+	ReporterID     *uint            `json:"reporterId,omitempty" gorm:"index"` // nil - жалоба фильтра контента
+	Reporter       *User            `json:"reporter,omitempty" gorm:"foreignKey:ReporterID"`
+	TargetType     ReportTargetType `json:"targetType" gorm:"type:varchar(20);not null;index:idx_reports_target"`
+	TargetID       uint             `json:"targetId" gorm:"not null;index:idx_reports_target"`
+	TargetUserID   *uint            `json:"targetUserId,omitempty" gorm:"index"` // Автор контента
+	Reason         ReportReason     `json:"reason" gorm:"type:varchar(20);not null"`
+	Details        string           `json:"details" gorm:"type:text"`
+	Status         ReportStatus     `json:"status" gorm:"type:varchar(20);not null;default:'open';index"`
+	AssigneeID     *uint            `json:"assigneeId,omitempty" gorm:"index"`
+	ClaimedAt      *time.Time       `json:"claimedAt,omitempty"`
+	Resolution     ModerationAction `json:"resolution,omitempty" gorm:"type:varchar(20)"`
+	ResolutionNote string           `json:"resolutionNote,omitempty" gorm:"type:text"`
+	ResolvedByID   *uint            `json:"resolvedById,omitempty"`
+	ResolvedAt     *time.Time       `json:"resolvedAt,omitempty"`
+	CreatedAt      time.Time        `json:"createdAt"`
+	UpdatedAt      time.Time        `json:"updatedAt"`
+}
+
+// ModerationLog представляет запись журнала решений модераторов. Записи только добавляются.
+type ModerationLog struct {
+	ID           uint             `gorm:"primaryKey" json:"id"`
+	ModeratorID  uint             `json:"moderatorId" gorm:"not null;index"`
+	Moderator    User             `json:"moderator" gorm:"foreignKey:ModeratorID"`
+	ReportID     *uint            `json:"reportId,omitempty" gorm:"index"`
+	Action       ModerationAction `json:"action" gorm:"type:varchar(20);not null"`
+	TargetType   ReportTargetType `json:"targetType" gorm:"type:varchar(20);not null;index:idx_moderation_logs_target"`
+	TargetID     uint             `json:"targetId" gorm:"not null;index:idx_moderation_logs_target"`
+	TargetUserID *uint            `json:"targetUserId,omitempty" gorm:"index"`
+	Note         string           `json:"note,omitempty" gorm:"type:text"`
+	SuspendUntil *time.Time       `json:"suspendUntil,omitempty"` // Для suspend
+	CreatedAt    time.Time        `json:"createdAt" gorm:"index"`
+}
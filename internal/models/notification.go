@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+)
+
+// NotificationType определяет событие, о котором уведомляется пользователь
+type NotificationType string
+
+const (
+	NotificationLike       NotificationType = "like"       // Лайк коллекции
+	NotificationFork       NotificationType = "fork"       // Коллекция смешана в чужую колоду
+	NotificationComment    NotificationType = "comment"    // Комментарий к коллекции
+	NotificationReply      NotificationType = "reply"      // Ответ на комментарий
+	NotificationSuggestion NotificationType = "suggestion" // Предложена карточка в коллекцию
+	NotificationFollow     NotificationType = "follow"     // Новый подписчик
+)
+
+// IsValid проверяет, что тип уведомления известен
+func (t NotificationType) IsValid() bool {
+	switch t {
+	case NotificationLike, NotificationFork, NotificationComment, NotificationReply, NotificationSuggestion, NotificationFollow:
+		return true
+	}
+	return false
+}
+
+// NotificationPreferences определяет, какие уведомления пользователь отключил.
+// По умолчанию включены все; отключенный тип не приходит ни в приложение, ни push.
+type NotificationPreferences struct {
+	MuteLikes       bool `json:"muteLikes" gorm:"not null;default:false"`
+	MuteForks       bool `json:"muteForks" gorm:"not null;default:false"`
+	MuteComments    bool `json:"muteComments" gorm:"not null;default:false"`
+	MuteReplies     bool `json:"muteReplies" gorm:"not null;default:false"`
+	MuteSuggestions bool `json:"muteSuggestions" gorm:"not null;default:false"`
+	MuteFollows     bool `json:"muteFollows" gorm:"not null;default:false"`
+	MutePush        bool `json:"mutePush" gorm:"not null;default:false"` // Только в приложении, без push
+}
+
+// IsMuted проверяет, отключены ли уведомления типа t
+func (p NotificationPreferences) IsMuted(t NotificationType) bool {
+	switch t {
+	case NotificationLike:
+		return p.MuteLikes
+	case NotificationFork:
+		return p.MuteForks
+	case NotificationComment:
+		return p.MuteComments
+	case NotificationReply:
+		return p.MuteReplies
+	case NotificationSuggestion:
+		return p.MuteSuggestions
+	case NotificationFollow:
+		return p.MuteFollows
+	}
+	return false
+}
+
+// Notification представляет уведомление пользователя. Однотипные события об одном объекте
+// объединяются в одно непрочитанное уведомление по GroupKey ("5 человек лайкнули коллекцию"):
+// ActorID указывает на последнего участника, ActorCount - сколько разных пользователей
+// участвовало. После прочтения следующее событие начинает новое уведомление.
+type Notification struct {
+	ID           uint             `gorm:"primaryKey" json:"id"`
+	UserID       uint             `json:"userId" gorm:"not null;index:idx_notifications_user_updated,priority:1;uniqueIndex:idx_notifications_unread_group,where:read_at IS NULL"` // Получатель
+	Type         NotificationType `json:"type" gorm:"type:varchar(20);not null"`
+	GroupKey     string           `json:"-" gorm:"type:varchar(100);not null;uniqueIndex:idx_notifications_unread_group,where:read_at IS NULL"`
+	CollectionID *uint            `json:"collectionId,omitempty"`
+	CommentID    *uint            `json:"commentId,omitempty"` // Ветка комментариев для ответов
+	ActorID      uint             `json:"actorId" gorm:"not null"`
+	Actor        User             `json:"actor" gorm:"foreignKey:ActorID"`
+	ActorCount   int              `json:"actorCount" gorm:"not null;default:1"`
+	ReadAt       *time.Time       `json:"readAt,omitempty"`
+	CreatedAt    time.Time        `json:"createdAt"`
+	UpdatedAt    time.Time        `json:"updatedAt" gorm:"index:idx_notifications_user_updated,priority:2"` // Время последнего события
+}
+
+// IsRead проверяет, прочитано ли уведомление
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
+
+// NotificationActor связывает уведомление с участником события, чтобы
+// повторное действие того же пользователя не увеличивало ActorCount
+type NotificationActor struct {
+	NotificationID uint `gorm:"primaryKey;autoIncrement:false"`
+	ActorID        uint `gorm:"primaryKey;autoIncrement:false"`
+}
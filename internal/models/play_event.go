@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+)
+
+// PlayEvent представляет одну сыгранную партию. Таблица только дополняется:
+// событие записывается один раз при завершении или закрытии брошенной сессии.
+type PlayEvent struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	CollectionID    uint      `json:"collectionId" gorm:"index;not null"`
+	UserID          *uint     `json:"userId,omitempty" gorm:"index"` // Пустой для анонимной игры
+	SessionID       string    `json:"sessionId" gorm:"type:varchar(36);uniqueIndex;not null"`
+	StartedAt       time.Time `json:"startedAt" gorm:"index"`
+	EndedAt         time.Time `json:"endedAt"`
+	DurationSeconds int       `json:"durationSeconds"`
+	CardsDrawn      int       `json:"cardsDrawn"`
+	CardsCompleted  int       `json:"cardsCompleted"`
+	Completed       bool      `json:"completed"` // false - сессия брошена без завершения
+	CreatedAt       time.Time `json:"createdAt" gorm:"index"`
+}
+
+// PlayDailyStat хранит дневную сводку партий по коллекции, рассчитанную из PlayEvent
+type PlayDailyStat struct {
+	ID                   uint      `gorm:"primaryKey" json:"id"`
+	CollectionID         uint      `json:"collectionId" gorm:"not null;uniqueIndex:idx_play_daily_stats_collection_day"`
+	Day                  time.Time `json:"day" gorm:"type:date;not null;uniqueIndex:idx_play_daily_stats_collection_day"`
+	Plays                int       `json:"plays"`
+	CompletedPlays       int       `json:"completedPlays"`
+	AnonymousPlays       int       `json:"anonymousPlays"`
+	UniqueUsers          int       `json:"uniqueUsers"`
+	CardsDrawn           int       `json:"cardsDrawn"`
+	TotalDurationSeconds int64     `json:"totalDurationSeconds"`
+	UpdatedAt            time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// ProfilePrivacy определяет, какие данные публичного профиля пользователь скрывает от других.
+// Владелец профиля всегда видит свой профиль целиком.
+type ProfilePrivacy struct {
+	HideAvatar      bool `json:"hideAvatar" gorm:"not null;default:false"`
+	HideBio         bool `json:"hideBio" gorm:"not null;default:false"`
+	HideCollections bool `json:"hideCollections" gorm:"not null;default:false"` // Список коллекций
+	HideStats       bool `json:"hideStats" gorm:"not null;default:false"`       // Запуски и средняя оценка
+	HideFollows     bool `json:"hideFollows" gorm:"not null;default:false"`     // Счетчики и списки подписок
+	HideJoinDate    bool `json:"hideJoinDate" gorm:"not null;default:false"`
+}
+
+// CreatorStats содержит суммарную статистику публичных коллекций автора
+type CreatorStats struct {
+	CollectionCount int64
+	TotalPlays      int64
+	RatingCount     int64
+	RatingSum       int64
+}
+
+// AverageRating возвращает среднее всех оценок коллекций автора (0, если оценок нет)
+func (s *CreatorStats) AverageRating() float64 {
+	if s.RatingCount == 0 {
+		return 0
+	}
+	return float64(s.RatingSum) / float64(s.RatingCount)
+}
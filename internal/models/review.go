@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+)
+
+// Границы оценки и параметры байесовского среднего: к отзывам коллекции добавляется
+// RatingPriorWeight "виртуальных" оценок RatingPriorMean, чтобы коллекция с одной
+// пятеркой не обгоняла коллекцию с сотней оценок 4.8.
+const (
+	MinRating         = 1
+	MaxRating         = 5
+	RatingPriorMean   = 3.0
+	RatingPriorWeight = 5
+)
+
+// CollectionReview представляет оценку и отзыв пользователя о коллекции (один на пользователя)
+type CollectionReview struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	CollectionID   uint       `json:"collectionId" gorm:"not null;index;uniqueIndex:idx_collection_reviews_user_collection"`
+	UserID         uint       `json:"userId" gorm:"not null;uniqueIndex:idx_collection_reviews_user_collection"`
+	User           User       `json:"user" gorm:"foreignKey:UserID"`
+	Rating         int        `json:"rating" gorm:"not null"`
+	Text           string     `json:"text" gorm:"type:text"`
+	OwnerReply     string     `json:"ownerReply,omitempty" gorm:"type:text"`
+	OwnerRepliedAt *time.Time `json:"ownerRepliedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+// AverageRating возвращает простое среднее оценок коллекции (0, если оценок нет)
+func (c *Collection) AverageRating() float64 {
+	if c.RatingCount == 0 {
+		return 0
+	}
+	return float64(c.RatingSum) / float64(c.RatingCount)
+}
+
+// BayesianRating возвращает среднее оценок, сглаженное к RatingPriorMean
+func (c *Collection) BayesianRating() float64 {
+	return (float64(c.RatingSum) + RatingPriorMean*RatingPriorWeight) / float64(c.RatingCount+RatingPriorWeight)
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// RoomStatus представляет состояние игровой комнаты
+type RoomStatus string
+
+const (
+	RoomStatusLobby    RoomStatus = "lobby"
+	RoomStatusPlaying  RoomStatus = "playing"
+	RoomStatusFinished RoomStatus = "finished"
+)
+
+// Room представляет комнату для совместной игры с нескольких устройств
+type Room struct {
+	ID            uint          `gorm:"primaryKey" json:"id"`
+	Code          string        `json:"code" gorm:"type:varchar(8);uniqueIndex;not null"` // Короткий код для входа
+	CollectionID  uint          `json:"collectionId" gorm:"not null"`
+	HostMemberID  uint          `json:"hostMemberId"`
+	GameSessionID *string       `json:"-" gorm:"type:varchar(36)"` // Сессия создается при старте игры
+	Status        RoomStatus    `json:"status" gorm:"type:varchar(20);not null;default:'lobby'"`
+	Members       []*RoomMember `json:"members" gorm:"foreignKey:RoomID"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	UpdatedAt     time.Time     `json:"updatedAt"`
+}
+
+// RoomMember представляет участника комнаты: зарегистрированного пользователя или гостя
+type RoomMember struct {
+	ID       uint       `gorm:"primaryKey" json:"id"`
+	RoomID   uint       `json:"roomId" gorm:"index;not null"`
+	UserID   *uint      `json:"userId,omitempty" gorm:"index"` // Пустой для гостя
+	Name     string     `json:"name" gorm:"not null"`
+	Token    string     `json:"-" gorm:"type:varchar(36);uniqueIndex;not null"` // Токен для переподключения
+	IsHost   bool       `json:"isHost"`
+	JoinedAt time.Time  `json:"joinedAt"`
+	LeftAt   *time.Time `json:"leftAt,omitempty"`
+}
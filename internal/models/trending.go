@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+)
+
+// TrendingWindow определяет период, за который учитываются сигналы популярности
+type TrendingWindow string
+
+const (
+	TrendingWindowDay   TrendingWindow = "day"
+	TrendingWindowWeek  TrendingWindow = "week"
+	TrendingWindowMonth TrendingWindow = "month"
+	TrendingWindowAll   TrendingWindow = "all" // Без ограничения периода и без затухания
+)
+
+// TrendingWindows перечисляет все периоды в порядке пересчета
+var TrendingWindows = []TrendingWindow{
+	TrendingWindowDay,
+	TrendingWindowWeek,
+	TrendingWindowMonth,
+	TrendingWindowAll,
+}
+
+// Duration возвращает длину периода; 0 означает "за все время"
+func (w TrendingWindow) Duration() time.Duration {
+	switch w {
+	case TrendingWindowDay:
+		return 24 * time.Hour
+	case TrendingWindowWeek:
+		return 7 * 24 * time.Hour
+	case TrendingWindowMonth:
+		return 30 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// IsValid проверяет, что период поддерживается
+func (w TrendingWindow) IsValid() bool {
+	for _, window := range TrendingWindows {
+		if w == window {
+			return true
+		}
+	}
+	return false
+}
+
+// TrendingScore хранит предрасчитанную популярность коллекции за период.
+// Колонка периода называется trending_window, так как WINDOW - ключевое слово SQL.
+type TrendingScore struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	Window       TrendingWindow `json:"window" gorm:"column:trending_window;type:varchar(10);not null;uniqueIndex:idx_trending_scores_window_collection;index:idx_trending_scores_window_rank,priority:1"`
+	CollectionID uint           `json:"collectionId" gorm:"not null;uniqueIndex:idx_trending_scores_window_collection"`
+	Rank         int            `json:"rank" gorm:"not null;index:idx_trending_scores_window_rank,priority:2"`
+	Score        float64        `json:"score"`
+	Plays        int            `json:"plays"`
+	Likes        int            `json:"likes"`
+	Forks        int            `json:"forks"`
+	ComputedAt   time.Time      `json:"computedAt"`
+}
@@ -6,17 +6,44 @@ import (
 	"gorm.io/gorm"
 )
 
+// UserRole определяет права пользователя
+type UserRole string
+
+const (
+	UserRoleUser      UserRole = "user"
+	UserRoleModerator UserRole = "moderator"
+	UserRoleAdmin     UserRole = "admin"
+)
+
 // User представляет модель пользователя в системе
 type User struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	Name        string         `json:"name"`
-	Surname     string         `json:"surname"`
-	Email       string         `json:"email" gorm:"uniqueIndex"`
-	Password    string         `json:"-"` // Не отдаем пароль в JSON-ответах
-	Phone       string         `json:"phone"`
-	ImageURL    string         `json:"imageUrl"`
-	Description string         `json:"description"`
-	CreatedAt   time.Time      `json:"createdAt"`
-	UpdatedAt   time.Time      `json:"updatedAt"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID             uint                    `gorm:"primaryKey" json:"id"`
+	Name           string                  `json:"name"`
+	Surname        string                  `json:"surname"`
+	Email          string                  `json:"email" gorm:"uniqueIndex"`
+	Password       string                  `json:"-"` // Не отдаем пароль в JSON-ответах
+	Phone          string                  `json:"phone"`
+	ImageURL       string                  `json:"imageUrl"`
+	Description    string                  `json:"description"`
+	BirthDate      *time.Time              `json:"birthDate,omitempty" gorm:"type:date"` // Для возрастных ограничений контента
+	Role           UserRole                `json:"role" gorm:"type:varchar(20);not null;default:'user'"`
+	SuspendedUntil *time.Time              `json:"suspendedUntil,omitempty"` // Блокировка модератором
+	WarningCount   int                     `json:"warningCount" gorm:"not null;default:0"`
+	FollowerCount  int                     `json:"followerCount" gorm:"not null;default:0"`
+	FollowingCount int                     `json:"followingCount" gorm:"not null;default:0"`
+	Privacy        ProfilePrivacy          `json:"privacy" gorm:"embedded;embeddedPrefix:privacy_"`
+	Notifications  NotificationPreferences `json:"notifications" gorm:"embedded;embeddedPrefix:notify_"`
+	CreatedAt      time.Time               `json:"createdAt"`
+	UpdatedAt      time.Time               `json:"updatedAt"`
+	DeletedAt      gorm.DeletedAt          `gorm:"index" json:"-"`
+}
+
+// IsModerator проверяет, может ли пользователь разбирать жалобы
+func (u *User) IsModerator() bool {
+	return u.Role == UserRoleModerator || u.Role == UserRoleAdmin
+}
+
+// IsSuspended проверяет, заблокирован ли пользователь на момент now
+func (u *User) IsSuspended(now time.Time) bool {
+	return u.SuspendedUntil != nil && u.SuspendedUntil.After(now)
 }
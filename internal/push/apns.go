@@ -0,0 +1,164 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	apnsProductionURL = "https://api.push.apple.com"
+	apnsSandboxURL    = "https://api.sandbox.push.apple.com"
+	// apnsTokenLifetime - как долго используется JWT провайдера. Apple отклоняет токены
+	// старше часа и слишком частую их смену (чаще раза в 20 минут).
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+// apnsErrorResponse - тело ответа APNs с ошибкой
+type apnsErrorResponse struct {
+	Reason string `json:"reason"`
+}
+
+// APNsSender отправляет push на iOS через HTTP/2 API Apple Push Notification service
+// с авторизацией по ключу .p8 (JWT провайдера кешируется на apnsTokenLifetime)
+type APNsSender struct {
+	keyID    string
+	teamID   string
+	topic    string // Bundle ID приложения
+	endpoint string
+	key      *ecdsa.PrivateKey
+	client   *http.Client
+
+	mu       sync.Mutex
+	bearer   string
+	issuedAt time.Time
+}
+
+// NewAPNsSender создает отправителя APNs по ключу .p8 в формате PEM.
+// sandbox включает окружение разработки Apple.
+func NewAPNsSender(keyPEM []byte, keyID, teamID, topic string, sandbox bool, client *http.Client) (*APNsSender, error) {
+	if keyID == "" || teamID == "" || topic == "" {
+		return nil, fmt.Errorf("invalid APNs settings: key ID, team ID and topic are required")
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APNs private key: %w", err)
+	}
+
+	endpoint := apnsProductionURL
+	if sandbox {
+		endpoint = apnsSandboxURL
+	}
+
+	return &APNsSender{
+		keyID:    keyID,
+		teamID:   teamID,
+		topic:    topic,
+		endpoint: endpoint,
+		key:      key,
+		client:   client,
+	}, nil
+}
+
+// Send отправляет уведомление на устройство iOS
+func (s *APNsSender) Send(ctx context.Context, message Message) error {
+	bearer, err := s.token()
+	if err != nil {
+		return err
+	}
+
+	// Дополнительные данные передаются рядом с aps, как ожидает приложение
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{"title": message.Title, "body": message.Body},
+			"sound": "default",
+		},
+	}
+	for key, value := range message.Data {
+		if key != "aps" {
+			payload[key] = value
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+"/3/device/"+url.PathEscape(message.Token), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+bearer)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("apns-topic", s.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var errorResponse apnsErrorResponse
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&errorResponse)
+
+	switch errorResponse.Reason {
+	case "BadDeviceToken", "Unregistered":
+		return fmt.Errorf("%w: %s", ErrInvalidToken, errorResponse.Reason)
+	case "ExpiredProviderToken", "InvalidProviderToken":
+		s.resetToken()
+		return &SendError{StatusCode: resp.StatusCode, Reason: errorResponse.Reason, Retryable: true}
+	case "DeviceTokenNotForTopic", "TopicDisallowed", "BadTopic", "MissingTopic", "BadCertificate", "BadCertificateEnvironment":
+		// Topic не совпадает с приложением, выдавшим токены, - ошибка в настройках сервера
+		return &SendError{StatusCode: resp.StatusCode, Reason: errorResponse.Reason, Misconfigured: true}
+	}
+	if resp.StatusCode == http.StatusGone {
+		return fmt.Errorf("%w: %s", ErrInvalidToken, errorResponse.Reason)
+	}
+	return &SendError{StatusCode: resp.StatusCode, Reason: errorResponse.Reason, Retryable: isRetryableStatus(resp.StatusCode)}
+}
+
+// token возвращает действующий JWT провайдера, при необходимости подписывая новый
+func (s *APNsSender) token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.bearer != "" && now.Sub(s.issuedAt) < apnsTokenLifetime {
+		return s.bearer, nil
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Issuer:   s.teamID,
+		IssuedAt: jwt.NewNumericDate(now),
+	})
+	token.Header["kid"] = s.keyID
+	bearer, err := token.SignedString(s.key)
+	if err != nil {
+		return "", err
+	}
+
+	s.bearer = bearer
+	s.issuedAt = now
+	return bearer, nil
+}
+
+// resetToken сбрасывает JWT провайдера, отклоненный APNs
+func (s *APNsSender) resetToken() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bearer = ""
+}
@@ -0,0 +1,109 @@
+package push
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestAPNsSender создает отправителя APNs, который отправляет сообщения на тестовый сервер
+func newTestAPNsSender(t *testing.T, send http.HandlerFunc) *APNsSender {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to encode key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	server := httptest.NewServer(send)
+	t.Cleanup(server.Close)
+
+	sender, err := NewAPNsSender(keyPEM, "KEY123", "TEAM123", "com.bulb.app", true, server.Client())
+	if err != nil {
+		t.Fatalf("NewAPNsSender() unexpected error: %v", err)
+	}
+	sender.endpoint = server.URL
+	return sender
+}
+
+func TestAPNsSenderErrors(t *testing.T) {
+	tests := []struct {
+		name              string
+		status            int
+		reason            string
+		wantInvalid       bool
+		wantRetryable     bool
+		wantMisconfigured bool
+	}{
+		{name: "delivered", status: http.StatusOK},
+		{name: "bad device token", status: http.StatusBadRequest, reason: "BadDeviceToken", wantInvalid: true},
+		{name: "unregistered", status: http.StatusGone, reason: "Unregistered", wantInvalid: true},
+		{name: "gone without reason", status: http.StatusGone, wantInvalid: true},
+		{name: "token for another app", status: http.StatusBadRequest, reason: "DeviceTokenNotForTopic", wantMisconfigured: true},
+		{name: "topic disallowed", status: http.StatusBadRequest, reason: "TopicDisallowed", wantMisconfigured: true},
+		{name: "invalid payload", status: http.StatusRequestEntityTooLarge, reason: "PayloadTooLarge"},
+		{name: "expired provider token", status: http.StatusForbidden, reason: "ExpiredProviderToken", wantRetryable: true},
+		{name: "too many requests", status: http.StatusTooManyRequests, reason: "TooManyRequests", wantRetryable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := newTestAPNsSender(t, func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("apns-topic"); got != "com.bulb.app" {
+					t.Errorf("apns-topic = %q, want the bundle ID", got)
+				}
+				w.WriteHeader(tt.status)
+				if tt.reason != "" {
+					_, _ = w.Write([]byte(`{"reason":"` + tt.reason + `"}`))
+				}
+			})
+
+			err := sender.Send(context.Background(), Message{Token: "0a1b2c3d", Title: "Bulb", Body: "Привет"})
+			if tt.status == http.StatusOK {
+				if err != nil {
+					t.Fatalf("Send() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Send() error = nil, want an error")
+			}
+			if got := errors.Is(err, ErrInvalidToken); got != tt.wantInvalid {
+				t.Errorf("Send() error %v: invalid token = %v, want %v", err, got, tt.wantInvalid)
+			}
+			if got := IsRetryable(err); got != tt.wantRetryable {
+				t.Errorf("Send() error %v: retryable = %v, want %v", err, got, tt.wantRetryable)
+			}
+			if got := isMisconfigured(err); got != tt.wantMisconfigured {
+				t.Errorf("Send() error %v: misconfigured = %v, want %v", err, got, tt.wantMisconfigured)
+			}
+		})
+	}
+}
+
+func TestAPNsSenderEscapesToken(t *testing.T) {
+	var path string
+	sender := newTestAPNsSender(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+	})
+
+	// Токены проверяются при регистрации, но в базе могут остаться сохраненные раньше
+	if err := sender.Send(context.Background(), Message{Token: "0a/../1b?x=1"}); err != nil {
+		t.Fatalf("Send() unexpected error: %v", err)
+	}
+	if want := "/3/device/0a%2F..%2F1b%3Fx=1"; path != want {
+		t.Errorf("request path = %q, want %q", path, want)
+	}
+}
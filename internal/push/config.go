@@ -0,0 +1,57 @@
+package push
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/KoLili12/bulb-server/pkg/config"
+)
+
+// Senders содержит отправителей платформ; nil означает, что платформа не настроена
+type Senders struct {
+	FCM  PushSender // Android
+	APNs PushSender // iOS
+}
+
+// NewSenders создает отправителей для платформ, ключи которых заданы в конфигурации
+func NewSenders(cfg config.PushConfig) (Senders, error) {
+	// Стандартный транспорт использует HTTP/2, который требует APNs
+	client := &http.Client{Timeout: sendTimeout}
+
+	var senders Senders
+	if cfg.FCMCredentialsFile != "" {
+		credentials, err := os.ReadFile(cfg.FCMCredentialsFile)
+		if err != nil {
+			return Senders{}, fmt.Errorf("failed to read FCM credentials: %w", err)
+		}
+		fcm, err := NewFCMSender(credentials, client)
+		if err != nil {
+			return Senders{}, err
+		}
+		senders.FCM = fcm
+	}
+	if cfg.APNsKeyFile != "" {
+		key, err := os.ReadFile(cfg.APNsKeyFile)
+		if err != nil {
+			return Senders{}, fmt.Errorf("failed to read APNs key: %w", err)
+		}
+		apns, err := NewAPNsSender(key, cfg.APNsKeyID, cfg.APNsTeamID, cfg.APNsTopic, cfg.APNsSandbox, client)
+		if err != nil {
+			return Senders{}, err
+		}
+		senders.APNs = apns
+	}
+	return senders, nil
+}
+
+// NewQueueConfig возвращает параметры очереди доставки из конфигурации
+func NewQueueConfig(cfg config.PushConfig) QueueConfig {
+	return QueueConfig{
+		Workers:     cfg.Workers,
+		Size:        cfg.QueueSize,
+		MaxAttempts: cfg.MaxAttempts,
+		Backoff:     time.Duration(cfg.RetryDelaySeconds) * time.Second,
+	}
+}
@@ -0,0 +1,75 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// FakeSender реализует PushSender в памяти для тестов и локальной разработки:
+// запоминает отправленные сообщения и имитирует недействительные токены и сбои
+type FakeSender struct {
+	mu       sync.Mutex
+	sent     []Message
+	invalid  map[string]bool
+	failures int
+}
+
+// NewFakeSender создает отправителя в памяти
+func NewFakeSender() *FakeSender {
+	return &FakeSender{
+		invalid: make(map[string]bool),
+	}
+}
+
+// Send запоминает сообщение или возвращает ошибку, настроенную через MarkInvalid и FailNext
+func (f *FakeSender) Send(ctx context.Context, message Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if f.invalid[message.Token] {
+		return fmt.Errorf("%w: fake", ErrInvalidToken)
+	}
+	if f.failures > 0 {
+		f.failures--
+		return &SendError{StatusCode: 503, Reason: "fake failure", Retryable: true}
+	}
+	f.sent = append(f.sent, message)
+	return nil
+}
+
+// Sent возвращает копию успешно отправленных сообщений в порядке отправки
+func (f *FakeSender) Sent() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sent := make([]Message, len(f.sent))
+	copy(sent, f.sent)
+	return sent
+}
+
+// MarkInvalid делает токен недействительным: отправка на него вернет ErrInvalidToken
+func (f *FakeSender) MarkInvalid(token string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.invalid[token] = true
+}
+
+// FailNext заставляет n следующих отправок завершиться временной ошибкой
+func (f *FakeSender) FailNext(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = n
+}
+
+// Reset очищает отправленные сообщения и настроенные ошибки
+func (f *FakeSender) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = nil
+	f.invalid = make(map[string]bool)
+	f.failures = 0
+}
@@ -0,0 +1,243 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	fcmScope           = "https://www.googleapis.com/auth/firebase.messaging"
+	fcmSendURL         = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	fcmDefaultTokenURI = "https://oauth2.googleapis.com/token"
+	// fcmTokenMargin - за сколько до истечения OAuth-токен запрашивается заново
+	fcmTokenMargin = time.Minute
+	// fcmTokenField - поле запроса с токеном устройства в ошибках валидации FCM
+	fcmTokenField = "message.token"
+)
+
+// fcmCredentials содержит нужные поля JSON-ключа сервисного аккаунта Firebase
+type fcmCredentials struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// fcmAssertionClaims - полезная нагрузка JWT, который обменивается на OAuth-токен
+type fcmAssertionClaims struct {
+	Scope string `json:"scope"`
+	jwt.RegisteredClaims
+}
+
+// fcmRequest - тело запроса FCM HTTP v1 API
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+// fcmErrorResponse - тело ответа FCM с ошибкой
+type fcmErrorResponse struct {
+	Error struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+			// FieldViolations заполняется в деталях google.rpc.BadRequest
+			FieldViolations []struct {
+				Field string `json:"field"`
+			} `json:"fieldViolations"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// FCMSender отправляет push на Android через Firebase Cloud Messaging HTTP v1 API.
+// OAuth-токен получается по ключу сервисного аккаунта и кешируется до истечения.
+type FCMSender struct {
+	projectID   string
+	clientEmail string
+	tokenURI    string
+	sendURL     string
+	key         *rsa.PrivateKey
+	client      *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewFCMSender создает отправителя FCM по JSON-ключу сервисного аккаунта
+func NewFCMSender(credentialsJSON []byte, client *http.Client) (*FCMSender, error) {
+	var credentials fcmCredentials
+	if err := json.Unmarshal(credentialsJSON, &credentials); err != nil {
+		return nil, fmt.Errorf("invalid FCM credentials: %w", err)
+	}
+	if credentials.ProjectID == "" || credentials.ClientEmail == "" {
+		return nil, fmt.Errorf("invalid FCM credentials: project_id and client_email are required")
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(credentials.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid FCM private key: %w", err)
+	}
+	if credentials.TokenURI == "" {
+		credentials.TokenURI = fcmDefaultTokenURI
+	}
+
+	return &FCMSender{
+		projectID:   credentials.ProjectID,
+		clientEmail: credentials.ClientEmail,
+		tokenURI:    credentials.TokenURI,
+		sendURL:     fmt.Sprintf(fcmSendURL, url.PathEscape(credentials.ProjectID)),
+		key:         key,
+		client:      client,
+	}, nil
+}
+
+// Send отправляет уведомление на устройство Android
+func (s *FCMSender) Send(ctx context.Context, message Message) error {
+	accessToken, err := s.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(fcmRequest{Message: fcmMessage{
+		Token:        message.Token,
+		Notification: fcmNotification{Title: message.Title, Body: message.Body},
+		Data:         message.Data,
+	}})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.sendURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var errorResponse fcmErrorResponse
+	_ = json.Unmarshal(body, &errorResponse)
+	reason := errorResponse.Error.Status
+	tokenViolation := false
+	for _, detail := range errorResponse.Error.Details {
+		if detail.ErrorCode != "" {
+			reason = detail.ErrorCode
+		}
+		for _, violation := range detail.FieldViolations {
+			if violation.Field == fcmTokenField {
+				tokenViolation = true
+			}
+		}
+	}
+
+	switch {
+	case reason == "UNREGISTERED":
+		return fmt.Errorf("%w: %s", ErrInvalidToken, reason)
+	case resp.StatusCode == http.StatusBadRequest && reason == "INVALID_ARGUMENT" && tokenViolation:
+		// Токен недействителен только если FCM указал на поле токена: INVALID_ARGUMENT
+		// возвращается и для других ошибок сообщения (например, слишком большого payload)
+		return fmt.Errorf("%w: %s", ErrInvalidToken, errorResponse.Error.Message)
+	case reason == "SENDER_ID_MISMATCH", reason == "THIRD_PARTY_AUTH_ERROR",
+		resp.StatusCode == http.StatusForbidden, resp.StatusCode == http.StatusNotFound:
+		// 404 без UNREGISTERED означает неверный project_id или адрес отправки,
+		// а не удаленное приложение
+		return &SendError{StatusCode: resp.StatusCode, Reason: reason, Misconfigured: true}
+	case resp.StatusCode == http.StatusUnauthorized:
+		s.resetToken()
+		return &SendError{StatusCode: resp.StatusCode, Reason: reason, Retryable: true}
+	}
+	return &SendError{StatusCode: resp.StatusCode, Reason: reason, Retryable: isRetryableStatus(resp.StatusCode)}
+}
+
+// token возвращает действующий OAuth-токен, при необходимости запрашивая новый
+func (s *FCMSender) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.accessToken != "" && now.Before(s.expiresAt) {
+		return s.accessToken, nil
+	}
+
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, fcmAssertionClaims{
+		Scope: fcmScope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.clientEmail,
+			Audience:  jwt.ClaimStrings{s.tokenURI},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}).SignedString(s.key)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", &SendError{StatusCode: resp.StatusCode, Reason: "oauth token request failed", Retryable: isRetryableStatus(resp.StatusCode)}
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+
+	s.accessToken = result.AccessToken
+	s.expiresAt = now.Add(time.Duration(result.ExpiresIn)*time.Second - fcmTokenMargin)
+	return s.accessToken, nil
+}
+
+// resetToken сбрасывает OAuth-токен, отклоненный FCM
+func (s *FCMSender) resetToken() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accessToken = ""
+}
@@ -0,0 +1,154 @@
+package push
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestFCMSender создает отправителя FCM, который получает OAuth-токен и отправляет
+// сообщения на тестовый сервер; send отвечает на запросы отправки
+func newTestFCMSender(t *testing.T, send http.HandlerFunc) *FCMSender {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"test-access-token","expires_in":3600}`))
+	})
+	mux.HandleFunc("/send", send)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	credentials, err := json.Marshal(fcmCredentials{
+		ProjectID:   "bulb-test",
+		ClientEmail: "push@bulb-test.iam.gserviceaccount.com",
+		PrivateKey:  string(keyPEM),
+		TokenURI:    server.URL + "/token",
+	})
+	if err != nil {
+		t.Fatalf("failed to encode credentials: %v", err)
+	}
+	sender, err := NewFCMSender(credentials, server.Client())
+	if err != nil {
+		t.Fatalf("NewFCMSender() unexpected error: %v", err)
+	}
+	sender.sendURL = server.URL + "/send"
+	return sender
+}
+
+func TestFCMSenderErrors(t *testing.T) {
+	tests := []struct {
+		name              string
+		status            int
+		body              string
+		wantInvalid       bool
+		wantRetryable     bool
+		wantMisconfigured bool
+	}{
+		{
+			name:   "delivered",
+			status: http.StatusOK,
+			body:   `{"name":"projects/bulb-test/messages/1"}`,
+		},
+		{
+			name:        "unregistered token",
+			status:      http.StatusNotFound,
+			body:        `{"error":{"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`,
+			wantInvalid: true,
+		},
+		{
+			name:   "invalid token field",
+			status: http.StatusBadRequest,
+			body: `{"error":{"status":"INVALID_ARGUMENT","message":"The registration token is not a valid FCM registration token",
+				"details":[{"errorCode":"INVALID_ARGUMENT"},{"fieldViolations":[{"field":"message.token"}]}]}}`,
+			wantInvalid: true,
+		},
+		{
+			name:   "invalid payload",
+			status: http.StatusBadRequest,
+			body: `{"error":{"status":"INVALID_ARGUMENT","message":"Message is too big",
+				"details":[{"errorCode":"INVALID_ARGUMENT"},{"fieldViolations":[{"field":"message.data"}]}]}}`,
+		},
+		{
+			name:   "invalid argument without details",
+			status: http.StatusBadRequest,
+			body:   `{"error":{"status":"INVALID_ARGUMENT","message":"Request contains an invalid argument."}}`,
+		},
+		{
+			name:              "unknown project",
+			status:            http.StatusNotFound,
+			body:              `{"error":{"status":"NOT_FOUND","message":"Requested entity was not found."}}`,
+			wantMisconfigured: true,
+		},
+		{
+			name:              "sender id mismatch",
+			status:            http.StatusForbidden,
+			body:              `{"error":{"status":"PERMISSION_DENIED","details":[{"errorCode":"SENDER_ID_MISMATCH"}]}}`,
+			wantMisconfigured: true,
+		},
+		{
+			name:          "quota exceeded",
+			status:        http.StatusTooManyRequests,
+			body:          `{"error":{"status":"RESOURCE_EXHAUSTED","details":[{"errorCode":"QUOTA_EXCEEDED"}]}}`,
+			wantRetryable: true,
+		},
+		{
+			name:          "service unavailable",
+			status:        http.StatusServiceUnavailable,
+			body:          `{"error":{"status":"UNAVAILABLE"}}`,
+			wantRetryable: true,
+		},
+		{
+			name:          "expired access token",
+			status:        http.StatusUnauthorized,
+			body:          `{"error":{"status":"UNAUTHENTICATED"}}`,
+			wantRetryable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := newTestFCMSender(t, func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != "Bearer test-access-token" {
+					t.Errorf("Authorization = %q, want bearer access token", got)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			err := sender.Send(context.Background(), Message{Token: "device-token", Title: "Bulb", Body: "Привет"})
+			if tt.status == http.StatusOK {
+				if err != nil {
+					t.Fatalf("Send() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Send() error = nil, want an error")
+			}
+			if got := errors.Is(err, ErrInvalidToken); got != tt.wantInvalid {
+				t.Errorf("Send() error %v: invalid token = %v, want %v", err, got, tt.wantInvalid)
+			}
+			if got := IsRetryable(err); got != tt.wantRetryable {
+				t.Errorf("Send() error %v: retryable = %v, want %v", err, got, tt.wantRetryable)
+			}
+			if got := isMisconfigured(err); got != tt.wantMisconfigured {
+				t.Errorf("Send() error %v: misconfigured = %v, want %v", err, got, tt.wantMisconfigured)
+			}
+		})
+	}
+}
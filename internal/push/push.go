@@ -0,0 +1,73 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidToken означает, что сервис доставки отклонил токен устройства навсегда
+// (приложение удалено или токен устарел). Такой токен удаляется.
+var ErrInvalidToken = errors.New("push token is invalid or unregistered")
+
+// Message представляет push-уведомление для одного устройства
+type Message struct {
+	Token string
+	Title string
+	Body  string
+	Data  map[string]string // Передается приложению вместе с уведомлением
+}
+
+// PushSender определяет отправку push-уведомлений через сервис доставки платформы.
+// Send возвращает ErrInvalidToken (обернутую), если токен нужно удалить, и ошибку,
+// для которой IsRetryable == true, если отправку стоит повторить позже.
+type PushSender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// SendError описывает отказ сервиса доставки
+type SendError struct {
+	StatusCode int
+	Reason     string
+	Retryable  bool // Временная ошибка: перегрузка, сбой сервиса, устаревший ключ авторизации
+	// Misconfigured означает, что отказ вызван настройками сервера (проект, ключ, bundle ID),
+	// а не токеном: такие токены не удаляются, иначе ошибка в настройках удалила бы все
+	Misconfigured bool
+}
+
+// Error возвращает описание отказа
+func (e *SendError) Error() string {
+	return fmt.Sprintf("push delivery failed with status %d: %s", e.StatusCode, e.Reason)
+}
+
+// IsRetryable проверяет, стоит ли повторить отправку после ошибки.
+// Сетевые ошибки и таймауты считаются временными.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, ErrInvalidToken) {
+		return false
+	}
+	var sendErr *SendError
+	if errors.As(err, &sendErr) {
+		return sendErr.Retryable
+	}
+	return true
+}
+
+// isMisconfigured проверяет, вызвана ли ошибка настройками сервера, а не токеном
+func isMisconfigured(err error) bool {
+	var sendErr *SendError
+	return errors.As(err, &sendErr) && sendErr.Misconfigured
+}
+
+// isRetryableStatus проверяет, означает ли HTTP-статус временный отказ сервиса
+func isRetryableStatus(status int) bool {
+	return status == 429 || status >= 500
+}
+
+// shortToken сокращает токен устройства для логов
+func shortToken(token string) string {
+	if len(token) <= 12 {
+		return token
+	}
+	return token[:12] + "..."
+}
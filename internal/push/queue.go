@@ -0,0 +1,116 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// sendTimeout ограничивает одну попытку отправки
+const sendTimeout = 10 * time.Second
+
+// Delivery представляет сообщение, поставленное в очередь, и отправителя платформы устройства
+type Delivery struct {
+	Sender  PushSender
+	Message Message
+	attempt int
+}
+
+// QueueConfig содержит параметры очереди доставки
+type QueueConfig struct {
+	Workers     int           // Сколько сообщений отправляется одновременно
+	Size        int           // Сколько сообщений может ждать отправки
+	MaxAttempts int           // Попыток на сообщение, включая первую
+	Backoff     time.Duration // Пауза перед первым повтором; каждая следующая вдвое дольше
+}
+
+// Queue доставляет сообщения в фоне: повторяет отправку при временных ошибках
+// и сообщает о недействительных токенах через onInvalidToken.
+// Очередь живет в памяти процесса, при перезапуске неотправленные сообщения теряются.
+type Queue struct {
+	config         QueueConfig
+	deliveries     chan Delivery
+	onInvalidToken func(token string)
+}
+
+// NewQueue создает очередь доставки; onInvalidToken вызывается для токенов,
+// которые сервис доставки отклонил навсегда
+func NewQueue(config QueueConfig, onInvalidToken func(token string)) *Queue {
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+	if config.Size < 1 {
+		config.Size = 1
+	}
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+	return &Queue{
+		config:         config,
+		deliveries:     make(chan Delivery, config.Size),
+		onInvalidToken: onInvalidToken,
+	}
+}
+
+// Enqueue ставит сообщение в очередь; если очередь переполнена, сообщение
+// отбрасывается и возвращается false
+func (q *Queue) Enqueue(delivery Delivery) bool {
+	select {
+	case q.deliveries <- delivery:
+		return true
+	default:
+		log.Printf("Push queue is full, dropping push to %s", shortToken(delivery.Message.Token))
+		return false
+	}
+}
+
+// Start запускает обработчики очереди и ждет их завершения после отмены контекста
+func (q *Queue) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < q.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case delivery := <-q.deliveries:
+					q.deliver(ctx, delivery)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// deliver выполняет одну попытку отправки и решает, что делать при ошибке
+func (q *Queue) deliver(ctx context.Context, delivery Delivery) {
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	err := delivery.Sender.Send(sendCtx, delivery.Message)
+	cancel()
+
+	token := shortToken(delivery.Message.Token)
+	switch {
+	case err == nil:
+		return
+	case errors.Is(err, ErrInvalidToken):
+		log.Printf("Push token %s rejected, removing it: %v", token, err)
+		if q.onInvalidToken != nil {
+			q.onInvalidToken(delivery.Message.Token)
+		}
+	case IsRetryable(err) && delivery.attempt+1 < q.config.MaxAttempts:
+		delay := q.config.Backoff << delivery.attempt
+		delivery.attempt++
+		log.Printf("Push to %s failed (attempt %d), retrying in %s: %v", token, delivery.attempt, delay, err)
+		time.AfterFunc(delay, func() {
+			q.Enqueue(delivery)
+		})
+	case isMisconfigured(err):
+		log.Printf("Push to %s rejected because of push settings, token is kept: %v", token, err)
+	default:
+		log.Printf("Push to %s failed after %d attempts: %v", token, delivery.attempt+1, err)
+	}
+}
@@ -0,0 +1,192 @@
+package push
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingSender считает попытки отправки и передает их дальше
+type countingSender struct {
+	PushSender
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (s *countingSender) Send(ctx context.Context, message Message) error {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+	return s.PushSender.Send(ctx, message)
+}
+
+func (s *countingSender) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// permanentFailureSender отклоняет каждое сообщение ошибкой, которую не стоит повторять
+type permanentFailureSender struct{}
+
+func (permanentFailureSender) Send(ctx context.Context, message Message) error {
+	return &SendError{StatusCode: 400, Reason: "bad payload"}
+}
+
+// misconfiguredSender отклоняет каждое сообщение из-за настроек сервера
+type misconfiguredSender struct{}
+
+func (misconfiguredSender) Send(ctx context.Context, message Message) error {
+	return &SendError{StatusCode: 404, Reason: "NOT_FOUND", Misconfigured: true}
+}
+
+// waitFor ждет выполнения условия не дольше секунды
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestQueueDelivery(t *testing.T) {
+	const token = "device-token-1234567890"
+
+	tests := []struct {
+		name        string
+		maxAttempts int
+		setup       func(fake *FakeSender) PushSender
+		wantCalls   int
+		wantSent    int
+		wantInvalid bool
+	}{
+		{
+			name:        "delivered on first attempt",
+			maxAttempts: 3,
+			setup:       func(fake *FakeSender) PushSender { return fake },
+			wantCalls:   1,
+			wantSent:    1,
+		},
+		{
+			name:        "delivered after transient failures",
+			maxAttempts: 3,
+			setup: func(fake *FakeSender) PushSender {
+				fake.FailNext(2)
+				return fake
+			},
+			wantCalls: 3,
+			wantSent:  1,
+		},
+		{
+			name:        "dropped after max attempts",
+			maxAttempts: 3,
+			setup: func(fake *FakeSender) PushSender {
+				fake.FailNext(5)
+				return fake
+			},
+			wantCalls: 3,
+			wantSent:  0,
+		},
+		{
+			name:        "single attempt is not retried",
+			maxAttempts: 1,
+			setup: func(fake *FakeSender) PushSender {
+				fake.FailNext(1)
+				return fake
+			},
+			wantCalls: 1,
+			wantSent:  0,
+		},
+		{
+			name:        "invalid token is reported and not retried",
+			maxAttempts: 3,
+			setup: func(fake *FakeSender) PushSender {
+				fake.MarkInvalid(token)
+				return fake
+			},
+			wantCalls:   1,
+			wantSent:    0,
+			wantInvalid: true,
+		},
+		{
+			name:        "settings error keeps the token",
+			maxAttempts: 3,
+			setup:       func(fake *FakeSender) PushSender { return misconfiguredSender{} },
+			wantCalls:   1,
+			wantSent:    0,
+		},
+		{
+			name:        "permanent failure is not retried",
+			maxAttempts: 3,
+			setup:       func(fake *FakeSender) PushSender { return permanentFailureSender{} },
+			wantCalls:   1,
+			wantSent:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFakeSender()
+			sender := &countingSender{PushSender: tt.setup(fake)}
+
+			var mu sync.Mutex
+			var invalid []string
+			queue := NewQueue(QueueConfig{Workers: 2, Size: 10, MaxAttempts: tt.maxAttempts, Backoff: time.Millisecond}, func(token string) {
+				mu.Lock()
+				defer mu.Unlock()
+				invalid = append(invalid, token)
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				queue.Start(ctx)
+				close(done)
+			}()
+			defer func() {
+				cancel()
+				<-done
+			}()
+
+			if !queue.Enqueue(Delivery{Sender: sender, Message: Message{Token: token, Title: "Bulb", Body: "Привет"}}) {
+				t.Fatal("Enqueue() = false, want true")
+			}
+			waitFor(t, "send attempts", func() bool { return sender.Calls() >= tt.wantCalls })
+			// Даем очереди время на лишние повторы, если они запланированы по ошибке
+			time.Sleep(20 * time.Millisecond)
+
+			if calls := sender.Calls(); calls != tt.wantCalls {
+				t.Errorf("send attempts = %d, want %d", calls, tt.wantCalls)
+			}
+			if sent := fake.Sent(); len(sent) != tt.wantSent {
+				t.Errorf("delivered %d messages, want %d", len(sent), tt.wantSent)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if tt.wantInvalid {
+				if len(invalid) != 1 || invalid[0] != token {
+					t.Errorf("invalid tokens = %v, want [%s]", invalid, token)
+				}
+			} else if len(invalid) > 0 {
+				t.Errorf("invalid tokens = %v, want none", invalid)
+			}
+		})
+	}
+}
+
+func TestQueueDropsWhenFull(t *testing.T) {
+	queue := NewQueue(QueueConfig{Size: 1}, nil)
+	fake := NewFakeSender()
+	if !queue.Enqueue(Delivery{Sender: fake, Message: Message{Token: "a"}}) {
+		t.Fatal("first Enqueue() = false, want true")
+	}
+	// Очередь не запущена, поэтому второе сообщение не помещается
+	if queue.Enqueue(Delivery{Sender: fake, Message: Message{Token: "b"}}) {
+		t.Fatal("second Enqueue() = true, want false")
+	}
+}
@@ -0,0 +1,189 @@
+package realtime
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// subscriberBuffer определяет, сколько событий может накопиться у медленного подписчика
+	subscriberBuffer = 64
+	// eventLogSize определяет, сколько последних событий комнаты хранится для возобновления потока
+	eventLogSize = 256
+)
+
+// Event представляет событие игровой комнаты, рассылаемое всем подписчикам
+type Event struct {
+	ID   uint64      `json:"id"` // Монотонно возрастает в пределах комнаты
+	Room string      `json:"room"`
+	Type string      `json:"type"`
+	Data interface{} `json:"data,omitempty"`
+	Time time.Time   `json:"time"`
+}
+
+// Broker определяет механизм публикации событий комнат.
+// Реализация в памяти работает в рамках одного процесса; для нескольких
+// экземпляров сервера ее можно заменить на внешний pub/sub.
+type Broker interface {
+	// Publish присваивает событию ID и рассылает его подписчикам комнаты
+	Publish(room string, event Event) Event
+	// Subscribe возвращает канал событий комнаты и функцию отписки.
+	// Канал закрывается, если подписчик не успевает читать события.
+	Subscribe(room string) (<-chan Event, func())
+	// SubscribeSince подписывает на события комнаты и атомарно возвращает события
+	// из журнала с ID больше lastID
+	SubscribeSince(room string, lastID uint64) *Subscription
+	// Close удаляет комнату и закрывает каналы всех ее подписчиков
+	Close(room string)
+}
+
+// Subscription представляет подписку, возобновленную с определенного события
+type Subscription struct {
+	Events      <-chan Event
+	Missed      []Event // События журнала с ID больше запрошенного
+	Complete    bool    // false, если часть пропущенных событий уже вытеснена из журнала
+	LastID      uint64  // ID последнего события комнаты на момент подписки
+	Unsubscribe func()
+}
+
+// topic хранит подписчиков одной комнаты
+type topic struct {
+	lastID      uint64
+	nextSubID   int
+	subscribers map[int]chan Event
+	log         []Event // Последние события в порядке возрастания ID, не более eventLogSize
+}
+
+// MemoryBroker реализует Broker в памяти процесса
+type MemoryBroker struct {
+	mu     sync.Mutex
+	topics map[string]*topic
+}
+
+// NewMemoryBroker создает новый брокер событий в памяти
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		topics: make(map[string]*topic),
+	}
+}
+
+// Publish присваивает событию ID и рассылает его подписчикам комнаты
+func (b *MemoryBroker) Publish(room string, event Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t := b.topic(room)
+	t.lastID++
+	event.ID = t.lastID
+	event.Room = room
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	t.log = append(t.log, event)
+	if len(t.log) > eventLogSize {
+		t.log = append(t.log[:0], t.log[len(t.log)-eventLogSize:]...)
+	}
+
+	for id, ch := range t.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// Подписчик не успевает: отключаем его, клиент переподключится и получит актуальное состояние
+			close(ch)
+			delete(t.subscribers, id)
+		}
+	}
+
+	return event
+}
+
+// Subscribe возвращает канал событий комнаты и функцию отписки
+func (b *MemoryBroker) Subscribe(room string) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.subscribe(room)
+}
+
+// SubscribeSince подписывает на события комнаты и возвращает пропущенные события из журнала
+func (b *MemoryBroker) SubscribeSince(room string, lastID uint64) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t := b.topic(room)
+	var missed []Event
+	for _, event := range t.log {
+		if event.ID > lastID {
+			missed = append(missed, event)
+		}
+	}
+
+	// Возобновление полное, если первое пропущенное событие следует сразу за lastID.
+	// lastID больше последнего выданного означает, что брокер был перезапущен и события утеряны.
+	complete := lastID <= t.lastID
+	if complete && len(missed) > 0 && missed[0].ID != lastID+1 {
+		complete = false
+	}
+
+	ch, unsubscribe := b.subscribe(room)
+	return &Subscription{
+		Events:      ch,
+		Missed:      missed,
+		Complete:    complete,
+		LastID:      t.lastID,
+		Unsubscribe: unsubscribe,
+	}
+}
+
+// subscribe регистрирует подписчика комнаты; вызывается под мьютексом
+func (b *MemoryBroker) subscribe(room string) (<-chan Event, func()) {
+	t := b.topic(room)
+	id := t.nextSubID
+	t.nextSubID++
+	ch := make(chan Event, subscriberBuffer)
+	t.subscribers[id] = ch
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			if current, ok := b.topics[room]; ok && current == t {
+				if sub, ok := t.subscribers[id]; ok {
+					close(sub)
+					delete(t.subscribers, id)
+				}
+			}
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// Close удаляет комнату и закрывает каналы всех ее подписчиков
+func (b *MemoryBroker) Close(room string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t, ok := b.topics[room]
+	if !ok {
+		return
+	}
+	for id, ch := range t.subscribers {
+		close(ch)
+		delete(t.subscribers, id)
+	}
+	delete(b.topics, room)
+}
+
+// topic возвращает (и при необходимости создает) комнату; вызывается под мьютексом
+func (b *MemoryBroker) topic(room string) *topic {
+	t, ok := b.topics[room]
+	if !ok {
+		t = &topic{subscribers: make(map[int]chan Event)}
+		b.topics[room] = t
+	}
+	return t
+}
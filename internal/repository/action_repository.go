@@ -13,9 +13,11 @@ type ActionRepository interface {
 	Create(action *models.Action) error
 	GetByID(id uint) (*models.Action, error)
 	GetByCollectionID(collectionID uint) ([]*models.Action, error)
+	FindByCollectionID(collectionID uint, filter models.ActionFilter) ([]*models.Action, error)
 	Update(action *models.Action) error
 	Delete(id uint) error
 	BatchCreate(actions []*models.Action) error
+	ApplyBatch(creates []*models.Action, updates []*models.Action, deleteIDs []uint) error
 }
 
 // actionRepository реализует интерфейс ActionRepository
@@ -71,23 +73,56 @@ func (r *actionRepository) GetByCollectionID(collectionID uint) ([]*models.Actio
 
 	log.Printf("Found %d actions for collection %d", len(actions), collectionID)
 
-	// Проверяем каждое действие на корректность типа
-	for i, action := range actions {
-		if action.Type != models.ActionTypeTruth && action.Type != models.ActionTypeDare {
-			log.Printf("Warning: Action %d has invalid type: %s", action.ID, action.Type)
-			// Устанавливаем тип по умолчанию вместо возврата ошибки
-			actions[i].Type = models.ActionTypeTruth
+	// Предупреждаем о карточках с типом, отсутствующим в реестре (например, после удаления типа)
+	for _, action := range actions {
+		if !models.IsValidActionType(action.Type) {
+			log.Printf("Warning: Action %d has unregistered type: %s", action.ID, action.Type)
 		}
 	}
 
 	return actions, nil
 }
 
+// FindByCollectionID возвращает действия коллекции, удовлетворяющие фильтру
+func (r *actionRepository) FindByCollectionID(collectionID uint, filter models.ActionFilter) ([]*models.Action, error) {
+	var actions []*models.Action
+
+	query := r.db.Where("collection_id = ?", collectionID)
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.MinDifficulty > 0 {
+		query = query.Where("difficulty >= ?", filter.MinDifficulty)
+	}
+	if filter.MaxDifficulty > 0 {
+		query = query.Where("difficulty <= ?", filter.MaxDifficulty)
+	}
+	if filter.MinSpiciness != nil {
+		query = query.Where("spiciness >= ?", *filter.MinSpiciness)
+	}
+	if filter.MaxSpiciness != nil {
+		query = query.Where("spiciness <= ?", *filter.MaxSpiciness)
+	}
+	if filter.TimedOnly {
+		query = query.Where("timer_seconds > 0")
+	}
+	if filter.MinPoints > 0 {
+		query = query.Where("points >= ?", filter.MinPoints)
+	}
+
+	if err := query.Order("\"order\" ASC").Find(&actions).Error; err != nil {
+		log.Printf("Error filtering actions for collection %d: %v", collectionID, err)
+		return nil, fmt.Errorf("failed to get actions for collection %d: %w", collectionID, err)
+	}
+
+	return actions, nil
+}
+
 // Update обновляет данные действия
 func (r *actionRepository) Update(action *models.Action) error {
-	// Валидируем тип действия перед обновлением
-	if action.Type != models.ActionTypeTruth && action.Type != models.ActionTypeDare {
-		return fmt.Errorf("invalid action type: %s", action.Type)
+	// Валидируем действие перед обновлением
+	if err := models.ValidateAction(action); err != nil {
+		return err
 	}
 
 	if err := r.db.Save(action).Error; err != nil {
@@ -119,18 +154,62 @@ func (r *actionRepository) BatchCreate(actions []*models.Action) error {
 	}
 
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		for i, action := range actions {
-			// Валидируем тип действия
-			if action.Type != models.ActionTypeTruth && action.Type != models.ActionTypeDare {
-				return fmt.Errorf("invalid action type for action %d: %s", i, action.Type)
+		return createActionsInTx(tx, actions)
+	})
+}
+
+// ApplyBatch создает, обновляет и удаляет действия в одной транзакции.
+// Если хотя бы одна операция завершилась ошибкой, откатываются все изменения.
+func (r *actionRepository) ApplyBatch(creates []*models.Action, updates []*models.Action, deleteIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createActionsInTx(tx, creates); err != nil {
+			return err
+		}
+
+		for _, action := range updates {
+			// Валидируем действие
+			if err := models.ValidateAction(action); err != nil {
+				return fmt.Errorf("invalid action %d: %w", action.ID, err)
 			}
 
-			if err := tx.Create(action).Error; err != nil {
-				log.Printf("Error creating action %d in batch: %v", i, err)
-				return fmt.Errorf("failed to create action %d: %w", i, err)
+			if err := tx.Save(action).Error; err != nil {
+				log.Printf("Error updating action %d in batch: %v", action.ID, err)
+				return fmt.Errorf("failed to update action %d: %w", action.ID, err)
 			}
 		}
-		log.Printf("Successfully created %d actions in batch", len(actions))
+
+		for _, id := range deleteIDs {
+			result := tx.Delete(&models.Action{}, id)
+			if err := result.Error; err != nil {
+				log.Printf("Error deleting action %d in batch: %v", id, err)
+				return fmt.Errorf("failed to delete action %d: %w", id, err)
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("action with id %d not found", id)
+			}
+		}
+
+		log.Printf("Successfully applied batch: %d created, %d updated, %d deleted",
+			len(creates), len(updates), len(deleteIDs))
 		return nil
 	})
 }
+
+// createActionsInTx сохраняет действия в рамках переданной транзакции
+func createActionsInTx(tx *gorm.DB, actions []*models.Action) error {
+	for i, action := range actions {
+		// Валидируем действие
+		if err := models.ValidateAction(action); err != nil {
+			return fmt.Errorf("invalid action %d: %w", i, err)
+		}
+
+		if err := tx.Create(action).Error; err != nil {
+			log.Printf("Error creating action %d in batch: %v", i, err)
+			return fmt.Errorf("failed to create action %d: %w", i, err)
+		}
+	}
+	if len(actions) > 0 {
+		log.Printf("Successfully created %d actions in batch", len(actions))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// rollupDaysQuery пересчитывает дневные сводки за дни, в которые с момента since
+// были записаны новые события. Дни считаются в UTC, как и периоды статистики в сервисе,
+// независимо от часового пояса соединения с базой.
+const rollupDaysQuery = `
+INSERT INTO play_daily_stats (collection_id, day, plays, completed_plays, anonymous_plays,
+	unique_users, cards_drawn, total_duration_seconds, updated_at)
+SELECT collection_id, (started_at AT TIME ZONE 'UTC')::date AS day,
+	COUNT(*),
+	COUNT(*) FILTER (WHERE completed),
+	COUNT(*) FILTER (WHERE user_id IS NULL),
+	COUNT(DISTINCT user_id),
+	COALESCE(SUM(cards_drawn), 0),
+	COALESCE(SUM(duration_seconds), 0),
+	NOW()
+FROM play_events
+WHERE (collection_id, (started_at AT TIME ZONE 'UTC')::date) IN (
+	SELECT DISTINCT collection_id, (started_at AT TIME ZONE 'UTC')::date FROM play_events WHERE created_at >= ?
+)
+GROUP BY collection_id, (started_at AT TIME ZONE 'UTC')::date
+ON CONFLICT (collection_id, day) DO UPDATE SET
+	plays = EXCLUDED.plays,
+	completed_plays = EXCLUDED.completed_plays,
+	anonymous_plays = EXCLUDED.anonymous_plays,
+	unique_users = EXCLUDED.unique_users,
+	cards_drawn = EXCLUDED.cards_drawn,
+	total_duration_seconds = EXCLUDED.total_duration_seconds,
+	updated_at = EXCLUDED.updated_at`
+
+// AnalyticsRepository определяет методы для работы со статистикой партий
+type AnalyticsRepository interface {
+	RollupDays(since time.Time) (int64, error)
+	GetDailyStats(collectionID uint, from, to time.Time) ([]*models.PlayDailyStat, error)
+	CountUniqueUsers(collectionID uint, from, to time.Time) (int64, error)
+}
+
+// analyticsRepository реализует интерфейс AnalyticsRepository
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsRepository создает новый экземпляр репозитория статистики партий
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepository{
+		db: db,
+	}
+}
+
+// RollupDays пересчитывает дневные сводки, затронутые событиями, записанными начиная с since
+func (r *analyticsRepository) RollupDays(since time.Time) (int64, error) {
+	result := r.db.Exec(rollupDaysQuery, since)
+	if result.Error != nil {
+		log.Printf("Error rolling up play events since %s: %v", since.Format(time.RFC3339), result.Error)
+		return 0, fmt.Errorf("failed to roll up play events: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// GetDailyStats возвращает дневные сводки коллекции за период [from, to] по возрастанию даты
+func (r *analyticsRepository) GetDailyStats(collectionID uint, from, to time.Time) ([]*models.PlayDailyStat, error) {
+	var stats []*models.PlayDailyStat
+	if err := r.db.
+		Where("collection_id = ? AND day BETWEEN ? AND ?", collectionID, from, to).
+		Order("day ASC").
+		Find(&stats).Error; err != nil {
+		log.Printf("Error getting daily stats of collection %d: %v", collectionID, err)
+		return nil, fmt.Errorf("failed to get daily stats: %w", err)
+	}
+	return stats, nil
+}
+
+// CountUniqueUsers возвращает количество разных пользователей, игравших в коллекцию за период.
+// Считается по событиям, так как уникальных пользователей нельзя сложить из дневных сводок.
+func (r *analyticsRepository) CountUniqueUsers(collectionID uint, from, to time.Time) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.PlayEvent{}).
+		Where("collection_id = ? AND user_id IS NOT NULL", collectionID).
+		Where("started_at >= ? AND started_at < ?", from, to.AddDate(0, 0, 1)).
+		Distinct("user_id").
+		Count(&count).Error; err != nil {
+		log.Printf("Error counting players of collection %d: %v", collectionID, err)
+		return 0, fmt.Errorf("failed to count unique users: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BlockRepository определяет методы для работы с блокировками пользователей
+type BlockRepository interface {
+	Block(blockerID, blockedID uint) (bool, error)
+	Unblock(blockerID, blockedID uint) (bool, error)
+	HasBlocked(blockerID, blockedID uint) (bool, error)
+	IsBlockedBetween(userID, otherID uint) (bool, error)
+	IsBlockedWithAny(userID uint, otherIDs []uint) (bool, error)
+	GetBlocked(blockerID uint, offset, limit int) ([]*models.User, int64, error)
+}
+
+// blockRepository реализует интерфейс BlockRepository
+type blockRepository struct {
+	db *gorm.DB
+}
+
+// NewBlockRepository создает новый экземпляр репозитория блокировок
+func NewBlockRepository(db *gorm.DB) BlockRepository {
+	return &blockRepository{
+		db: db,
+	}
+}
+
+// Block блокирует пользователя; возвращает false, если он уже заблокирован
+func (r *blockRepository) Block(blockerID, blockedID uint) (bool, error) {
+	block := &models.UserBlock{BlockerID: blockerID, BlockedID: blockedID, CreatedAt: time.Now()}
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(block)
+	if result.Error != nil {
+		log.Printf("Error blocking user %d by user %d: %v", blockedID, blockerID, result.Error)
+		return false, fmt.Errorf("failed to block user: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Unblock снимает блокировку; возвращает false, если ее не было
+func (r *blockRepository) Unblock(blockerID, blockedID uint) (bool, error) {
+	result := r.db.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&models.UserBlock{})
+	if result.Error != nil {
+		log.Printf("Error unblocking user %d by user %d: %v", blockedID, blockerID, result.Error)
+		return false, fmt.Errorf("failed to unblock user: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// HasBlocked проверяет, заблокировал ли blockerID пользователя blockedID
+func (r *blockRepository) HasBlocked(blockerID, blockedID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.UserBlock{}).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Count(&count).Error; err != nil {
+		log.Printf("Error checking block of user %d by user %d: %v", blockedID, blockerID, err)
+		return false, fmt.Errorf("failed to check block: %w", err)
+	}
+	return count > 0, nil
+}
+
+// IsBlockedBetween проверяет, заблокировал ли кто-то из двух пользователей другого
+func (r *blockRepository) IsBlockedBetween(userID, otherID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.UserBlock{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userID, otherID, otherID, userID).
+		Count(&count).Error; err != nil {
+		log.Printf("Error checking block between users %d and %d: %v", userID, otherID, err)
+		return false, fmt.Errorf("failed to check block: %w", err)
+	}
+	return count > 0, nil
+}
+
+// IsBlockedWithAny проверяет, есть ли блокировка между userID и кем-то из otherIDs
+func (r *blockRepository) IsBlockedWithAny(userID uint, otherIDs []uint) (bool, error) {
+	if len(otherIDs) == 0 {
+		return false, nil
+	}
+
+	var count int64
+	if err := r.db.Model(&models.UserBlock{}).
+		Where("(blocker_id = ? AND blocked_id IN ?) OR (blocked_id = ? AND blocker_id IN ?)", userID, otherIDs, userID, otherIDs).
+		Count(&count).Error; err != nil {
+		log.Printf("Error checking blocks of user %d: %v", userID, err)
+		return false, fmt.Errorf("failed to check block: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetBlocked возвращает пользователей, заблокированных blockerID, начиная с последних
+func (r *blockRepository) GetBlocked(blockerID uint, offset, limit int) ([]*models.User, int64, error) {
+	query := r.db.Model(&models.User{}).
+		Joins("JOIN user_blocks ON user_blocks.blocked_id = users.id").
+		Where("user_blocks.blocker_id = ?", blockerID)
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		log.Printf("Error counting users blocked by user %d: %v", blockerID, err)
+		return nil, 0, fmt.Errorf("failed to get blocked users: %w", err)
+	}
+
+	var users []*models.User
+	if err := query.
+		Select("users.*").
+		Order("user_blocks.created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&users).Error; err != nil {
+		log.Printf("Error getting users blocked by user %d: %v", blockerID, err)
+		return nil, 0, fmt.Errorf("failed to get blocked users: %w", err)
+	}
+	return users, count, nil
+}
+
+// excludeBlocked убирает из выборки строки, автор которых (столбец column) заблокирован viewerID.
+// Для запроса без авторизации (viewerID == nil) выборка не меняется.
+func excludeBlocked(query *gorm.DB, column string, viewerID *uint) *gorm.DB {
+	if viewerID == nil {
+		return query
+	}
+	return query.Where(column+" NOT IN (SELECT blocked_id FROM user_blocks WHERE blocker_id = ?)", *viewerID)
+}
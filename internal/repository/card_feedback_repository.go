@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CardFeedbackRepository определяет методы для работы с обратной связью по карточкам
+type CardFeedbackRepository interface {
+	SetVote(vote *models.ActionVote) error
+	DeleteVote(userID, actionID uint) error
+	GetQuality(actionIDs []uint) (map[uint]*models.CardQuality, error)
+}
+
+// cardFeedbackRepository реализует интерфейс CardFeedbackRepository
+type cardFeedbackRepository struct {
+	db *gorm.DB
+}
+
+// NewCardFeedbackRepository создает новый экземпляр репозитория обратной связи по карточкам
+func NewCardFeedbackRepository(db *gorm.DB) CardFeedbackRepository {
+	return &cardFeedbackRepository{
+		db: db,
+	}
+}
+
+// SetVote сохраняет голос пользователя, заменяя предыдущий
+func (r *cardFeedbackRepository) SetVote(vote *models.ActionVote) error {
+	if err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "action_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(vote).Error; err != nil {
+		log.Printf("Error saving vote of user %d for action %d: %v", vote.UserID, vote.ActionID, err)
+		return fmt.Errorf("failed to save vote: %w", err)
+	}
+	return nil
+}
+
+// DeleteVote удаляет голос пользователя за карточку
+func (r *cardFeedbackRepository) DeleteVote(userID, actionID uint) error {
+	if err := r.db.Where("user_id = ? AND action_id = ?", userID, actionID).
+		Delete(&models.ActionVote{}).Error; err != nil {
+		log.Printf("Error deleting vote of user %d for action %d: %v", userID, actionID, err)
+		return fmt.Errorf("failed to delete vote: %w", err)
+	}
+	return nil
+}
+
+// GetQuality собирает голоса и исходы розыгрышей для карточек. Карточки без
+// обратной связи присутствуют в результате с нулевыми значениями.
+func (r *cardFeedbackRepository) GetQuality(actionIDs []uint) (map[uint]*models.CardQuality, error) {
+	quality := make(map[uint]*models.CardQuality, len(actionIDs))
+	if len(actionIDs) == 0 {
+		return quality, nil
+	}
+	for _, id := range actionIDs {
+		quality[id] = &models.CardQuality{ActionID: id}
+	}
+
+	var votes []struct {
+		ActionID  uint
+		Upvotes   int
+		Downvotes int
+	}
+	if err := r.db.Model(&models.ActionVote{}).
+		Select("action_id, SUM(CASE WHEN value > 0 THEN 1 ELSE 0 END) AS upvotes, SUM(CASE WHEN value < 0 THEN 1 ELSE 0 END) AS downvotes").
+		Where("action_id IN ?", actionIDs).
+		Group("action_id").
+		Scan(&votes).Error; err != nil {
+		log.Printf("Error aggregating card votes: %v", err)
+		return nil, fmt.Errorf("failed to get card quality: %w", err)
+	}
+	for _, row := range votes {
+		quality[row.ActionID].Upvotes = row.Upvotes
+		quality[row.ActionID].Downvotes = row.Downvotes
+	}
+
+	var draws []struct {
+		ActionID  uint
+		Draws     int
+		Completed int
+		Skipped   int
+	}
+	if err := r.db.Model(&models.GameDraw{}).
+		Select("action_id, COUNT(*) AS draws, SUM(CASE WHEN outcome = ? THEN 1 ELSE 0 END) AS completed, SUM(CASE WHEN outcome = ? THEN 1 ELSE 0 END) AS skipped",
+			models.DrawCompleted, models.DrawSkipped).
+		Where("action_id IN ?", actionIDs).
+		Group("action_id").
+		Scan(&draws).Error; err != nil {
+		log.Printf("Error aggregating card draws: %v", err)
+		return nil, fmt.Errorf("failed to get card quality: %w", err)
+	}
+	for _, row := range draws {
+		quality[row.ActionID].Draws = row.Draws
+		quality[row.ActionID].Completed = row.Completed
+		quality[row.ActionID].Skipped = row.Skipped
+	}
+
+	return quality, nil
+}
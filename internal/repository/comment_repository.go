@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CommentRepository определяет методы для работы с комментариями к коллекциям
+type CommentRepository interface {
+	Create(comment *models.Comment) error
+	GetByID(id uint) (*models.Comment, error)
+	Update(comment *models.Comment) error
+	ListRoots(collectionID uint, viewerID *uint, offset, limit int) ([]*models.Comment, int64, error)
+	ListReplies(rootID uint, viewerID *uint, offset, limit int) ([]*models.Comment, int64, error)
+	CountPinned(collectionID uint) (int64, error)
+}
+
+// commentRepository реализует интерфейс CommentRepository
+type commentRepository struct {
+	db *gorm.DB
+}
+
+// NewCommentRepository создает новый экземпляр репозитория комментариев
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{
+		db: db,
+	}
+}
+
+// Create сохраняет комментарий и увеличивает счетчик ответов ветки
+func (r *commentRepository) Create(comment *models.Comment) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(comment).Error; err != nil {
+			return err
+		}
+		if comment.RootID == nil {
+			return nil
+		}
+		return tx.Model(&models.Comment{}).Where("id = ?", *comment.RootID).
+			UpdateColumn("reply_count", gorm.Expr("reply_count + 1")).Error
+	})
+	if err != nil {
+		log.Printf("Error creating comment for collection %d: %v", comment.CollectionID, err)
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	return nil
+}
+
+// GetByID возвращает комментарий по ID
+func (r *commentRepository) GetByID(id uint) (*models.Comment, error) {
+	var comment models.Comment
+	if err := r.db.Preload("User").First(&comment, id).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Update сохраняет изменения комментария
+func (r *commentRepository) Update(comment *models.Comment) error {
+	if err := r.db.Omit(clause.Associations).Save(comment).Error; err != nil {
+		log.Printf("Error updating comment %d: %v", comment.ID, err)
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	return nil
+}
+
+// ListRoots возвращает комментарии верхнего уровня: сначала закрепленные, затем новые.
+// Комментарии пользователей, заблокированных viewerID, не возвращаются.
+func (r *commentRepository) ListRoots(collectionID uint, viewerID *uint, offset, limit int) ([]*models.Comment, int64, error) {
+	query := r.db.Model(&models.Comment{}).Where("collection_id = ? AND parent_id IS NULL", collectionID)
+	return r.list(excludeBlocked(query, "user_id", viewerID), "is_pinned DESC, pinned_at DESC, created_at DESC, id DESC", offset, limit)
+}
+
+// ListReplies возвращает все ответы ветки в порядке написания.
+// Ответы пользователей, заблокированных viewerID, не возвращаются.
+func (r *commentRepository) ListReplies(rootID uint, viewerID *uint, offset, limit int) ([]*models.Comment, int64, error) {
+	query := r.db.Model(&models.Comment{}).Where("root_id = ?", rootID)
+	return r.list(excludeBlocked(query, "user_id", viewerID), "created_at ASC, id ASC", offset, limit)
+}
+
+// CountPinned возвращает количество закрепленных комментариев коллекции
+func (r *commentRepository) CountPinned(collectionID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.Comment{}).
+		Where("collection_id = ? AND is_pinned", collectionID).
+		Count(&count).Error; err != nil {
+		log.Printf("Error counting pinned comments of collection %d: %v", collectionID, err)
+		return 0, fmt.Errorf("failed to count pinned comments: %w", err)
+	}
+	return count, nil
+}
+
+// list выполняет постраничную выборку комментариев
+func (r *commentRepository) list(query *gorm.DB, order string, offset, limit int) ([]*models.Comment, int64, error) {
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		log.Printf("Error counting comments: %v", err)
+		return nil, 0, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	var comments []*models.Comment
+	if err := query.Preload("User").Order(order).Offset(offset).Limit(limit).Find(&comments).Error; err != nil {
+		log.Printf("Error getting comments: %v", err)
+		return nil, 0, fmt.Errorf("failed to get comments: %w", err)
+	}
+	return comments, count, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeviceTokenRepository определяет методы для работы с push-токенами устройств
+type DeviceTokenRepository interface {
+	Register(device *models.DeviceToken) error
+	Unregister(userID uint, token string) (bool, error)
+	ListByUser(userID uint) ([]*models.DeviceToken, error)
+	DeleteByToken(token string) error
+}
+
+// deviceTokenRepository реализует интерфейс DeviceTokenRepository
+type deviceTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewDeviceTokenRepository создает новый экземпляр репозитория push-токенов
+func NewDeviceTokenRepository(db *gorm.DB) DeviceTokenRepository {
+	return &deviceTokenRepository{
+		db: db,
+	}
+}
+
+// Register сохраняет токен устройства. Уже известный токен переходит к пользователю
+// из device, а его платформа и время последней регистрации обновляются.
+func (r *deviceTokenRepository) Register(device *models.DeviceToken) error {
+	if err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "last_seen_at"}),
+	}).Create(device).Error; err != nil {
+		log.Printf("Error registering device token for user %d: %v", device.UserID, err)
+		return fmt.Errorf("failed to register device token: %w", err)
+	}
+	return nil
+}
+
+// Unregister удаляет токен устройства пользователя; возвращает false, если такого токена у него нет
+func (r *deviceTokenRepository) Unregister(userID uint, token string) (bool, error) {
+	result := r.db.Where("user_id = ? AND token = ?", userID, token).Delete(&models.DeviceToken{})
+	if result.Error != nil {
+		log.Printf("Error unregistering device token of user %d: %v", userID, result.Error)
+		return false, fmt.Errorf("failed to unregister device token: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListByUser возвращает токены всех устройств пользователя
+func (r *deviceTokenRepository) ListByUser(userID uint) ([]*models.DeviceToken, error) {
+	var devices []*models.DeviceToken
+	if err := r.db.Where("user_id = ?", userID).Find(&devices).Error; err != nil {
+		log.Printf("Error getting device tokens of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get device tokens: %w", err)
+	}
+	return devices, nil
+}
+
+// DeleteByToken удаляет токен, отклоненный сервисом доставки
+func (r *deviceTokenRepository) DeleteByToken(token string) error {
+	if err := r.db.Where("token = ?", token).Delete(&models.DeviceToken{}).Error; err != nil {
+		log.Printf("Error deleting device token: %v", err)
+		return fmt.Errorf("failed to delete device token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FollowRepository определяет методы для работы с подписками на авторов и лентой подписок
+type FollowRepository interface {
+	Follow(followerID, followeeID uint) (bool, error)
+	Unfollow(followerID, followeeID uint) (bool, error)
+	GetFollowedIDs(followerID uint, userIDs []uint) (map[uint]bool, error)
+	GetFollowers(userID uint, offset, limit int) ([]*models.User, int64, error)
+	GetFollowing(userID uint, offset, limit int) ([]*models.User, int64, error)
+	GetFeed(userID uint, cursor *models.FeedCursor, limit, maxAgeRating int) ([]*models.Collection, error)
+}
+
+// followRepository реализует интерфейс FollowRepository
+type followRepository struct {
+	db *gorm.DB
+}
+
+// NewFollowRepository создает новый экземпляр репозитория подписок
+func NewFollowRepository(db *gorm.DB) FollowRepository {
+	return &followRepository{
+		db: db,
+	}
+}
+
+// Follow создает подписку и увеличивает счетчики обоих пользователей; возвращает false, если подписка уже была
+func (r *followRepository) Follow(followerID, followeeID uint) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		follow := &models.UserFollow{FollowerID: followerID, FolloweeID: followeeID, CreatedAt: time.Now()}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(follow)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		created = true
+		return r.updateCounters(tx, followerID, followeeID, "+ 1")
+	})
+	if err != nil {
+		log.Printf("Error following user %d by user %d: %v", followeeID, followerID, err)
+		return false, fmt.Errorf("failed to follow user: %w", err)
+	}
+	return created, nil
+}
+
+// Unfollow удаляет подписку и уменьшает счетчики обоих пользователей; возвращает false, если подписки не было
+func (r *followRepository) Unfollow(followerID, followeeID uint) (bool, error) {
+	removed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&models.UserFollow{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		removed = true
+		return r.updateCounters(tx, followerID, followeeID, "- 1")
+	})
+	if err != nil {
+		log.Printf("Error unfollowing user %d by user %d: %v", followeeID, followerID, err)
+		return false, fmt.Errorf("failed to unfollow user: %w", err)
+	}
+	return removed, nil
+}
+
+// GetFollowedIDs возвращает пользователей из списка, на которых подписан followerID
+func (r *followRepository) GetFollowedIDs(followerID uint, userIDs []uint) (map[uint]bool, error) {
+	followed := make(map[uint]bool)
+	if len(userIDs) == 0 {
+		return followed, nil
+	}
+
+	var ids []uint
+	if err := r.db.Model(&models.UserFollow{}).
+		Where("follower_id = ? AND followee_id IN ?", followerID, userIDs).
+		Pluck("followee_id", &ids).Error; err != nil {
+		log.Printf("Error getting follows of user %d: %v", followerID, err)
+		return nil, fmt.Errorf("failed to get follows: %w", err)
+	}
+	for _, id := range ids {
+		followed[id] = true
+	}
+	return followed, nil
+}
+
+// GetFollowers возвращает подписчиков пользователя, начиная с последних подписавшихся
+func (r *followRepository) GetFollowers(userID uint, offset, limit int) ([]*models.User, int64, error) {
+	return r.listUsers("user_follows.follower_id", "user_follows.followee_id", userID, offset, limit)
+}
+
+// GetFollowing возвращает авторов, на которых подписан пользователь, начиная с последних подписок
+func (r *followRepository) GetFollowing(userID uint, offset, limit int) ([]*models.User, int64, error) {
+	return r.listUsers("user_follows.followee_id", "user_follows.follower_id", userID, offset, limit)
+}
+
+// GetFeed возвращает новые и измененные коллекции авторов, на которых подписан пользователь,
+// от последних изменений к более ранним. Лента собирается при чтении: подписки ограничивают
+// авторов, а индекс idx_collections_user_updated позволяет не сортировать все их коллекции.
+// Скрытые модератором коллекции, коллекции с рейтингом выше maxAgeRating
+// и коллекции заблокированных пользователем авторов не возвращаются.
+func (r *followRepository) GetFeed(userID uint, cursor *models.FeedCursor, limit, maxAgeRating int) ([]*models.Collection, error) {
+	followees := r.db.Model(&models.UserFollow{}).Select("followee_id").Where("follower_id = ?", userID)
+
+	query := r.db.Preload("User").
+		Where("collections.user_id IN (?)", followees).
+		Where("collections.hidden_at IS NULL AND collections.age_rating <= ?", maxAgeRating)
+	// Блокировка снимает подписки, но лента не должна зависеть от порядка этих операций
+	query = excludeBlocked(query, "collections.user_id", &userID)
+	if cursor != nil {
+		query = query.Where("(collections.updated_at, collections.id) < (?, ?)", cursor.UpdatedAt, cursor.CollectionID)
+	}
+
+	var collections []*models.Collection
+	if err := query.
+		Order("collections.updated_at DESC, collections.id DESC").
+		Limit(limit).
+		Find(&collections).Error; err != nil {
+		log.Printf("Error getting feed of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get feed: %w", err)
+	}
+	return collections, nil
+}
+
+// listUsers возвращает пользователей из столбца column подписок, где filterColumn = userID
+func (r *followRepository) listUsers(column, filterColumn string, userID uint, offset, limit int) ([]*models.User, int64, error) {
+	query := r.db.Model(&models.User{}).
+		Joins("JOIN user_follows ON "+column+" = users.id").
+		Where(filterColumn+" = ?", userID)
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		log.Printf("Error counting follows of user %d: %v", userID, err)
+		return nil, 0, fmt.Errorf("failed to get follows: %w", err)
+	}
+
+	var users []*models.User
+	if err := query.
+		Select("users.*").
+		Order("user_follows.created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&users).Error; err != nil {
+		log.Printf("Error getting follows of user %d: %v", userID, err)
+		return nil, 0, fmt.Errorf("failed to get follows: %w", err)
+	}
+	return users, count, nil
+}
+
+// updateCounters меняет счетчик подписок followerID и счетчик подписчиков followeeID на delta
+func (r *followRepository) updateCounters(tx *gorm.DB, followerID, followeeID uint, delta string) error {
+	if err := tx.Model(&models.User{}).Where("id = ?", followerID).
+		UpdateColumn("following_count", gorm.Expr("GREATEST(following_count "+delta+", 0)")).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.User{}).Where("id = ?", followeeID).
+		UpdateColumn("follower_count", gorm.Expr("GREATEST(follower_count "+delta+", 0)")).Error
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GameRepository определяет методы для работы с игровыми сессиями в базе данных
+type GameRepository interface {
+	Create(session *models.GameSession) error
+	GetByID(id string) (*models.GameSession, error)
+	Update(session *models.GameSession) error
+	AddPlayer(player *models.GamePlayer) error
+	UpdatePlayer(player *models.GamePlayer) error
+	SaveDraw(session *models.GameSession, draw *models.GameDraw, views []*models.CardView) error
+	ResolveDraw(draw *models.GameDraw, player *models.GamePlayer) error
+	Finish(session *models.GameSession, results []*models.GameResult, event *models.PlayEvent) error
+	GetStaleSessionIDs(inactiveSince time.Time, limit int) ([]string, error)
+	GetResultsByUserID(userID uint, offset, limit int) ([]*models.GameResult, int64, error)
+}
+
+// gameRepository реализует интерфейс GameRepository
+type gameRepository struct {
+	db *gorm.DB
+}
+
+// NewGameRepository создает новый экземпляр репозитория игровых сессий
+func NewGameRepository(db *gorm.DB) GameRepository {
+	return &gameRepository{
+		db: db,
+	}
+}
+
+// Create сохраняет новую игровую сессию вместе с игроками
+func (r *gameRepository) Create(session *models.GameSession) error {
+	if err := r.db.Create(session).Error; err != nil {
+		log.Printf("Error creating game session: %v", err)
+		return fmt.Errorf("failed to create game session: %w", err)
+	}
+	return nil
+}
+
+// GetByID возвращает игровую сессию с игроками и историей ходов
+func (r *gameRepository) GetByID(id string) (*models.GameSession, error) {
+	var session models.GameSession
+	err := r.db.
+		Preload("Players", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Draws", func(db *gorm.DB) *gorm.DB { return db.Order("turn ASC") }).
+		First(&session, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("game session %s not found", id)
+		}
+		log.Printf("Error getting game session %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get game session: %w", err)
+	}
+	return &session, nil
+}
+
+// Update сохраняет поля игровой сессии (без связанных игроков и ходов)
+func (r *gameRepository) Update(session *models.GameSession) error {
+	if err := r.db.Omit(clause.Associations).Save(session).Error; err != nil {
+		log.Printf("Error updating game session %s: %v", session.ID, err)
+		return fmt.Errorf("failed to update game session: %w", err)
+	}
+	return nil
+}
+
+// AddPlayer добавляет игрока в сессию
+func (r *gameRepository) AddPlayer(player *models.GamePlayer) error {
+	if err := r.db.Create(player).Error; err != nil {
+		log.Printf("Error adding player to game session %s: %v", player.SessionID, err)
+		return fmt.Errorf("failed to add player: %w", err)
+	}
+	return nil
+}
+
+// UpdatePlayer сохраняет изменения игрока
+func (r *gameRepository) UpdatePlayer(player *models.GamePlayer) error {
+	if err := r.db.Save(player).Error; err != nil {
+		log.Printf("Error updating player %d: %v", player.ID, err)
+		return fmt.Errorf("failed to update player: %w", err)
+	}
+	return nil
+}
+
+// SaveDraw сохраняет ход, обновленное состояние сессии и историю просмотров в одной транзакции
+func (r *gameRepository) SaveDraw(session *models.GameSession, draw *models.GameDraw, views []*models.CardView) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(draw).Error; err != nil {
+			log.Printf("Error saving draw for game session %s: %v", session.ID, err)
+			return fmt.Errorf("failed to save draw: %w", err)
+		}
+		if err := tx.Omit(clause.Associations).Save(session).Error; err != nil {
+			log.Printf("Error updating game session %s: %v", session.ID, err)
+			return fmt.Errorf("failed to update game session: %w", err)
+		}
+		for _, view := range views {
+			if err := upsertCardView(tx, view); err != nil {
+				log.Printf("Error recording view of action %d: %v", view.ActionID, err)
+				return fmt.Errorf("failed to record card view: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// upsertCardView добавляет просмотр карточки или увеличивает счетчик существующего
+func upsertCardView(tx *gorm.DB, view *models.CardView) error {
+	owner := "user_id"
+	if view.UserID == nil {
+		owner = "room_id"
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: owner}, {Name: "action_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"times_seen":   gorm.Expr("card_views.times_seen + 1"),
+			"last_seen_at": gorm.Expr("excluded.last_seen_at"),
+		}),
+	}).Create(view).Error
+}
+
+// ResolveDraw сохраняет исход хода и обновленный счет игрока в одной транзакции
+func (r *gameRepository) ResolveDraw(draw *models.GameDraw, player *models.GamePlayer) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(draw).Error; err != nil {
+			log.Printf("Error resolving draw %d: %v", draw.ID, err)
+			return fmt.Errorf("failed to resolve draw: %w", err)
+		}
+		if err := tx.Save(player).Error; err != nil {
+			log.Printf("Error updating score of player %d: %v", player.ID, err)
+			return fmt.Errorf("failed to update player score: %w", err)
+		}
+		return nil
+	})
+}
+
+// Finish сохраняет завершенную сессию, результаты игроков и событие партии в одной транзакции
+func (r *gameRepository) Finish(session *models.GameSession, results []*models.GameResult, event *models.PlayEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(session).Error; err != nil {
+			log.Printf("Error finishing game session %s: %v", session.ID, err)
+			return fmt.Errorf("failed to finish game session: %w", err)
+		}
+		if len(results) > 0 {
+			if err := tx.Create(&results).Error; err != nil {
+				log.Printf("Error saving results of game session %s: %v", session.ID, err)
+				return fmt.Errorf("failed to save game results: %w", err)
+			}
+		}
+		if event != nil {
+			// Событие партии записывается один раз на сессию
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(event).Error; err != nil {
+				log.Printf("Error saving play event of game session %s: %v", session.ID, err)
+				return fmt.Errorf("failed to save play event: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetStaleSessionIDs возвращает активные сессии без ходов с момента inactiveSince
+func (r *gameRepository) GetStaleSessionIDs(inactiveSince time.Time, limit int) ([]string, error) {
+	var ids []string
+	if err := r.db.Model(&models.GameSession{}).
+		Where("status = ? AND updated_at < ?", models.GameSessionActive, inactiveSince).
+		Order("updated_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		log.Printf("Error getting stale game sessions: %v", err)
+		return nil, fmt.Errorf("failed to get stale game sessions: %w", err)
+	}
+	return ids, nil
+}
+
+// GetResultsByUserID возвращает результаты игр пользователя, начиная с последних
+func (r *gameRepository) GetResultsByUserID(userID uint, offset, limit int) ([]*models.GameResult, int64, error) {
+	var count int64
+	query := r.db.Model(&models.GameResult{}).Where("user_id = ?", userID)
+	if err := query.Count(&count).Error; err != nil {
+		log.Printf("Error counting game results of user %d: %v", userID, err)
+		return nil, 0, fmt.Errorf("failed to get game results: %w", err)
+	}
+
+	var results []*models.GameResult
+	if err := query.Order("finished_at DESC, id DESC").Offset(offset).Limit(limit).Find(&results).Error; err != nil {
+		log.Printf("Error getting game results of user %d: %v", userID, err)
+		return nil, 0, fmt.Errorf("failed to get game results: %w", err)
+	}
+	return results, count, nil
+}
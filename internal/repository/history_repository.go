@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// CollectionHistory представляет сводку просмотров карточек одной коллекции
+type CollectionHistory struct {
+	CollectionID uint
+	SeenCount    int
+	LastSeenAt   time.Time
+}
+
+// HistoryRepository определяет методы для работы с историей просмотров карточек
+type HistoryRepository interface {
+	GetSeenActionIDs(actionIDs []uint, userIDs []uint, roomID *uint) (map[uint]bool, error)
+	GetUserSummary(userID uint, offset, limit int) ([]*CollectionHistory, int64, error)
+	GetUserViews(userID, collectionID uint) ([]*models.CardView, error)
+	DeleteUserViews(userID uint, collectionID *uint) (int64, error)
+	DeleteRoomViews(roomID uint) error
+}
+
+// historyRepository реализует интерфейс HistoryRepository
+type historyRepository struct {
+	db *gorm.DB
+}
+
+// NewHistoryRepository создает новый экземпляр репозитория истории просмотров
+func NewHistoryRepository(db *gorm.DB) HistoryRepository {
+	return &historyRepository{
+		db: db,
+	}
+}
+
+// GetSeenActionIDs возвращает карточки из actionIDs, которые видел кто-либо из пользователей
+// или комната. Карточки ищутся по ID, а не по коллекции: колода может состоять из карточек
+// нескольких коллекций.
+func (r *historyRepository) GetSeenActionIDs(actionIDs []uint, userIDs []uint, roomID *uint) (map[uint]bool, error) {
+	seen := make(map[uint]bool)
+	if len(actionIDs) == 0 || (len(userIDs) == 0 && roomID == nil) {
+		return seen, nil
+	}
+
+	query := r.db.Model(&models.CardView{}).Where("action_id IN ?", actionIDs)
+	switch {
+	case len(userIDs) > 0 && roomID != nil:
+		query = query.Where("user_id IN ? OR room_id = ?", userIDs, *roomID)
+	case len(userIDs) > 0:
+		query = query.Where("user_id IN ?", userIDs)
+	default:
+		query = query.Where("room_id = ?", *roomID)
+	}
+
+	var ids []uint
+	if err := query.Distinct().Pluck("action_id", &ids).Error; err != nil {
+		log.Printf("Error getting seen actions: %v", err)
+		return nil, fmt.Errorf("failed to get seen actions: %w", err)
+	}
+	for _, id := range ids {
+		seen[id] = true
+	}
+	return seen, nil
+}
+
+// GetUserSummary возвращает сводку просмотров пользователя по коллекциям, начиная с последних
+func (r *historyRepository) GetUserSummary(userID uint, offset, limit int) ([]*CollectionHistory, int64, error) {
+	var count int64
+	if err := r.db.Model(&models.CardView{}).
+		Where("user_id = ?", userID).
+		Distinct("collection_id").
+		Count(&count).Error; err != nil {
+		log.Printf("Error counting history of user %d: %v", userID, err)
+		return nil, 0, fmt.Errorf("failed to get history: %w", err)
+	}
+
+	var summary []*CollectionHistory
+	if err := r.db.Model(&models.CardView{}).
+		Select("collection_id, COUNT(*) AS seen_count, MAX(last_seen_at) AS last_seen_at").
+		Where("user_id = ?", userID).
+		Group("collection_id").
+		Order("last_seen_at DESC").
+		Offset(offset).Limit(limit).
+		Scan(&summary).Error; err != nil {
+		log.Printf("Error getting history of user %d: %v", userID, err)
+		return nil, 0, fmt.Errorf("failed to get history: %w", err)
+	}
+	return summary, count, nil
+}
+
+// GetUserViews возвращает просмотры карточек коллекции пользователем, начиная с последних
+func (r *historyRepository) GetUserViews(userID, collectionID uint) ([]*models.CardView, error) {
+	var views []*models.CardView
+	if err := r.db.
+		Where("user_id = ? AND collection_id = ?", userID, collectionID).
+		Order("last_seen_at DESC").
+		Find(&views).Error; err != nil {
+		log.Printf("Error getting views of collection %d by user %d: %v", collectionID, userID, err)
+		return nil, fmt.Errorf("failed to get history: %w", err)
+	}
+	return views, nil
+}
+
+// DeleteUserViews очищает историю пользователя целиком или по одной коллекции
+func (r *historyRepository) DeleteUserViews(userID uint, collectionID *uint) (int64, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if collectionID != nil {
+		query = query.Where("collection_id = ?", *collectionID)
+	}
+
+	result := query.Delete(&models.CardView{})
+	if result.Error != nil {
+		log.Printf("Error resetting history of user %d: %v", userID, result.Error)
+		return 0, fmt.Errorf("failed to reset history: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteRoomViews очищает историю комнаты
+func (r *historyRepository) DeleteRoomViews(roomID uint) error {
+	if err := r.db.Where("room_id = ?", roomID).Delete(&models.CardView{}).Error; err != nil {
+		log.Printf("Error resetting history of room %d: %v", roomID, err)
+		return fmt.Errorf("failed to reset history: %w", err)
+	}
+	return nil
+}
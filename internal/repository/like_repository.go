@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LikeRepository определяет методы для работы с лайками и избранным коллекций
+type LikeRepository interface {
+	Like(userID, collectionID uint) (bool, error)
+	Unlike(userID, collectionID uint) (bool, error)
+	Favorite(userID, collectionID uint) (bool, error)
+	Unfavorite(userID, collectionID uint) (bool, error)
+	GetLikedCollectionIDs(userID uint, collectionIDs []uint) (map[uint]bool, error)
+	GetFavoritedCollectionIDs(userID uint, collectionIDs []uint) (map[uint]bool, error)
+	GetFavorites(userID uint, offset, limit int) ([]*models.Collection, int64, error)
+}
+
+// likeRepository реализует интерфейс LikeRepository
+type likeRepository struct {
+	db *gorm.DB
+}
+
+// NewLikeRepository создает новый экземпляр репозитория лайков и избранного
+func NewLikeRepository(db *gorm.DB) LikeRepository {
+	return &likeRepository{
+		db: db,
+	}
+}
+
+// Like ставит лайк; возвращает false, если лайк уже был
+func (r *likeRepository) Like(userID, collectionID uint) (bool, error) {
+	like := &models.CollectionLike{UserID: userID, CollectionID: collectionID, CreatedAt: time.Now()}
+	return r.add(like, "like_count", collectionID)
+}
+
+// Unlike снимает лайк; возвращает false, если лайка не было
+func (r *likeRepository) Unlike(userID, collectionID uint) (bool, error) {
+	return r.remove(&models.CollectionLike{}, "like_count", userID, collectionID)
+}
+
+// Favorite добавляет коллекцию в избранное; возвращает false, если она уже там
+func (r *likeRepository) Favorite(userID, collectionID uint) (bool, error) {
+	favorite := &models.CollectionFavorite{UserID: userID, CollectionID: collectionID, CreatedAt: time.Now()}
+	return r.add(favorite, "favorite_count", collectionID)
+}
+
+// Unfavorite убирает коллекцию из избранного; возвращает false, если ее там не было
+func (r *likeRepository) Unfavorite(userID, collectionID uint) (bool, error) {
+	return r.remove(&models.CollectionFavorite{}, "favorite_count", userID, collectionID)
+}
+
+// GetLikedCollectionIDs возвращает коллекции из списка, которые лайкнул пользователь
+func (r *likeRepository) GetLikedCollectionIDs(userID uint, collectionIDs []uint) (map[uint]bool, error) {
+	return r.markedIDs(&models.CollectionLike{}, userID, collectionIDs)
+}
+
+// GetFavoritedCollectionIDs возвращает коллекции из списка, которые пользователь добавил в избранное
+func (r *likeRepository) GetFavoritedCollectionIDs(userID uint, collectionIDs []uint) (map[uint]bool, error) {
+	return r.markedIDs(&models.CollectionFavorite{}, userID, collectionIDs)
+}
+
+// GetFavorites возвращает избранные коллекции пользователя, начиная с последних добавленных
+func (r *likeRepository) GetFavorites(userID uint, offset, limit int) ([]*models.Collection, int64, error) {
+	query := r.db.Model(&models.Collection{}).
+		Joins("JOIN collection_favorites ON collection_favorites.collection_id = collections.id").
+		Where("collection_favorites.user_id = ?", userID)
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		log.Printf("Error counting favorites of user %d: %v", userID, err)
+		return nil, 0, fmt.Errorf("failed to get favorites: %w", err)
+	}
+
+	var collections []*models.Collection
+	if err := query.
+		Select("collections.*").
+		Order("collection_favorites.created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&collections).Error; err != nil {
+		log.Printf("Error getting favorites of user %d: %v", userID, err)
+		return nil, 0, fmt.Errorf("failed to get favorites: %w", err)
+	}
+	return collections, count, nil
+}
+
+// add создает отметку и увеличивает счетчик коллекции, если отметки еще не было
+func (r *likeRepository) add(mark interface{}, counter string, collectionID uint) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(mark)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		created = true
+		return tx.Model(&models.Collection{}).
+			Where("id = ?", collectionID).
+			UpdateColumn(counter, gorm.Expr(counter+" + 1")).Error
+	})
+	if err != nil {
+		log.Printf("Error updating %s of collection %d: %v", counter, collectionID, err)
+		return false, fmt.Errorf("failed to update %s: %w", counter, err)
+	}
+	return created, nil
+}
+
+// remove удаляет отметку и уменьшает счетчик коллекции, если отметка была
+func (r *likeRepository) remove(mark interface{}, counter string, userID, collectionID uint) (bool, error) {
+	removed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND collection_id = ?", userID, collectionID).Delete(mark)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		removed = true
+		return tx.Model(&models.Collection{}).
+			Where("id = ?", collectionID).
+			UpdateColumn(counter, gorm.Expr("GREATEST("+counter+" - 1, 0)")).Error
+	})
+	if err != nil {
+		log.Printf("Error updating %s of collection %d: %v", counter, collectionID, err)
+		return false, fmt.Errorf("failed to update %s: %w", counter, err)
+	}
+	return removed, nil
+}
+
+// markedIDs возвращает коллекции из списка, на которых у пользователя есть отметка
+func (r *likeRepository) markedIDs(mark interface{}, userID uint, collectionIDs []uint) (map[uint]bool, error) {
+	marked := make(map[uint]bool)
+	if len(collectionIDs) == 0 {
+		return marked, nil
+	}
+
+	var ids []uint
+	if err := r.db.Model(mark).
+		Where("user_id = ? AND collection_id IN ?", userID, collectionIDs).
+		Pluck("collection_id", &ids).Error; err != nil {
+		log.Printf("Error getting marks of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get marks: %w", err)
+	}
+	for _, id := range ids {
+		marked[id] = true
+	}
+	return marked, nil
+}
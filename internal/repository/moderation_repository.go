@@ -0,0 +1,312 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReportFilter задает условия выборки жалоб. Нулевые значения не ограничивают выборку.
+type ReportFilter struct {
+	Status     models.ReportStatus
+	TargetType models.ReportTargetType
+	AssigneeID *uint
+}
+
+// ModerationLogFilter задает условия выборки журнала модерации
+type ModerationLogFilter struct {
+	TargetType   models.ReportTargetType
+	TargetID     *uint
+	TargetUserID *uint
+	ModeratorID  *uint
+}
+
+// ModerationRepository определяет методы для работы с жалобами и журналом модерации
+type ModerationRepository interface {
+	CreateReport(report *models.Report) error
+	HasUnresolvedReport(reporterID *uint, targetType models.ReportTargetType, targetID uint) (bool, error)
+	GetReport(id uint) (*models.Report, error)
+	ListReports(filter ReportFilter, offset, limit int) ([]*models.Report, int64, error)
+	Claim(reportID, moderatorID uint, entry *models.ModerationLog) (bool, error)
+	Release(reportID, moderatorID uint, entry *models.ModerationLog) (bool, error)
+	Resolve(report *models.Report, entry *models.ModerationLog) (bool, error)
+	ListLogs(filter ModerationLogFilter, offset, limit int) ([]*models.ModerationLog, int64, error)
+	UnhideCollection(collectionID uint, entry *models.ModerationLog) (bool, error)
+}
+
+// moderationRepository реализует интерфейс ModerationRepository
+type moderationRepository struct {
+	db *gorm.DB
+}
+
+// NewModerationRepository создает новый экземпляр репозитория модерации
+func NewModerationRepository(db *gorm.DB) ModerationRepository {
+	return &moderationRepository{
+		db: db,
+	}
+}
+
+// CreateReport сохраняет новую жалобу
+func (r *moderationRepository) CreateReport(report *models.Report) error {
+	if err := r.db.Omit(clause.Associations).Create(report).Error; err != nil {
+		log.Printf("Error creating report on %s %d: %v", report.TargetType, report.TargetID, err)
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	return nil
+}
+
+// HasUnresolvedReport проверяет, есть ли у пользователя неразобранная жалоба на объект.
+// reporterID == nil проверяет автоматические жалобы фильтра контента.
+func (r *moderationRepository) HasUnresolvedReport(reporterID *uint, targetType models.ReportTargetType, targetID uint) (bool, error) {
+	query := r.db.Model(&models.Report{}).
+		Where("target_type = ? AND target_id = ? AND status <> ?", targetType, targetID, models.ReportResolved)
+	if reporterID != nil {
+		query = query.Where("reporter_id = ?", *reporterID)
+	} else {
+		query = query.Where("reporter_id IS NULL")
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		log.Printf("Error checking reports on %s %d: %v", targetType, targetID, err)
+		return false, fmt.Errorf("failed to check reports: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetReport возвращает жалобу по ID
+func (r *moderationRepository) GetReport(id uint) (*models.Report, error) {
+	var report models.Report
+	if err := r.db.Preload("Reporter").First(&report, id).Error; err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ListReports возвращает жалобы очереди, начиная с самых старых
+func (r *moderationRepository) ListReports(filter ReportFilter, offset, limit int) ([]*models.Report, int64, error) {
+	query := r.db.Model(&models.Report{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.AssigneeID != nil {
+		query = query.Where("assignee_id = ?", *filter.AssigneeID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		log.Printf("Error counting reports: %v", err)
+		return nil, 0, fmt.Errorf("failed to get reports: %w", err)
+	}
+
+	var reports []*models.Report
+	if err := query.Preload("Reporter").
+		Order("created_at ASC, id ASC").
+		Offset(offset).Limit(limit).
+		Find(&reports).Error; err != nil {
+		log.Printf("Error getting reports: %v", err)
+		return nil, 0, fmt.Errorf("failed to get reports: %w", err)
+	}
+	return reports, count, nil
+}
+
+// Claim назначает жалобу модератору, если она открыта или уже назначена ему.
+// Возвращает false, если жалобу разбирает другой модератор или она уже разобрана.
+func (r *moderationRepository) Claim(reportID, moderatorID uint, entry *models.ModerationLog) (bool, error) {
+	now := time.Now()
+	return r.transition(entry, func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&models.Report{}).
+			Where("id = ? AND (status = ? OR (status = ? AND assignee_id = ?))",
+				reportID, models.ReportOpen, models.ReportClaimed, moderatorID).
+			UpdateColumns(map[string]interface{}{
+				"status":      models.ReportClaimed,
+				"assignee_id": moderatorID,
+				"claimed_at":  now,
+				"updated_at":  now,
+			})
+	})
+}
+
+// Release возвращает назначенную модератору жалобу в очередь
+func (r *moderationRepository) Release(reportID, moderatorID uint, entry *models.ModerationLog) (bool, error) {
+	return r.transition(entry, func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&models.Report{}).
+			Where("id = ? AND status = ? AND assignee_id = ?", reportID, models.ReportClaimed, moderatorID).
+			UpdateColumns(map[string]interface{}{
+				"status":      models.ReportOpen,
+				"assignee_id": nil,
+				"claimed_at":  nil,
+				"updated_at":  time.Now(),
+			})
+	})
+}
+
+// Resolve закрывает назначенную модератору жалобу, применяет решение к ее объекту,
+// закрывает тем же решением остальные неразобранные жалобы на этот объект и пишет
+// решение в журнал. Все изменения выполняются в одной транзакции; возвращает false
+// и ничего не меняет, если жалоба уже не назначена модератору entry.ModeratorID.
+func (r *moderationRepository) Resolve(report *models.Report, entry *models.ModerationLog) (bool, error) {
+	resolved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		columns := map[string]interface{}{
+			"status":          models.ReportResolved,
+			"resolution":      report.Resolution,
+			"resolution_note": report.ResolutionNote,
+			"resolved_by_id":  report.ResolvedByID,
+			"resolved_at":     report.ResolvedAt,
+			"updated_at":      now,
+		}
+
+		// Сначала забираем саму жалобу: решение применяется только один раз
+		result := tx.Model(&models.Report{}).
+			Where("id = ? AND status = ? AND assignee_id = ?", report.ID, models.ReportClaimed, entry.ModeratorID).
+			UpdateColumns(columns)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		resolved = true
+
+		if err := applyModeration(tx, entry, now); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Report{}).
+			Where("target_type = ? AND target_id = ? AND status <> ?", report.TargetType, report.TargetID, models.ReportResolved).
+			UpdateColumns(columns).Error; err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(entry).Error
+	})
+	if err != nil {
+		log.Printf("Error resolving report %d: %v", report.ID, err)
+		return false, fmt.Errorf("failed to resolve report: %w", err)
+	}
+	return resolved, nil
+}
+
+// applyModeration применяет решение модератора к объекту жалобы. Карточки и комментарии
+// не имеют отдельного скрытого состояния, поэтому для них hide и delete одинаково удаляют
+// их мягко. Счетчики и поля пользователя меняются точечно, чтобы не перезаписать
+// изменения, сделанные параллельно.
+func applyModeration(tx *gorm.DB, entry *models.ModerationLog, now time.Time) error {
+	switch entry.Action {
+	case models.ModerationDismiss:
+		return nil
+	case models.ModerationWarn:
+		return tx.Model(&models.User{}).Where("id = ?", *entry.TargetUserID).
+			UpdateColumn("warning_count", gorm.Expr("warning_count + 1")).Error
+	case models.ModerationSuspend:
+		return tx.Model(&models.User{}).Where("id = ?", *entry.TargetUserID).
+			UpdateColumn("suspended_until", entry.SuspendUntil).Error
+	}
+
+	switch entry.TargetType {
+	case models.ReportTargetCollection:
+		if entry.Action == models.ModerationHide {
+			return tx.Model(&models.Collection{}).Where("id = ?", entry.TargetID).
+				UpdateColumn("hidden_at", now).Error
+		}
+		return tx.Delete(&models.Collection{}, entry.TargetID).Error
+	case models.ReportTargetAction:
+		return tx.Delete(&models.Action{}, entry.TargetID).Error
+	case models.ReportTargetComment:
+		return tx.Model(&models.Comment{}).Where("id = ? AND removed_at IS NULL", entry.TargetID).
+			UpdateColumns(map[string]interface{}{
+				"removed_at": now,
+				"text":       "",
+				"mentions":   nil,
+				"is_pinned":  false,
+				"pinned_at":  nil,
+				"updated_at": now,
+			}).Error
+	}
+	return fmt.Errorf("unsupported moderation target %s", entry.TargetType)
+}
+
+// ListLogs возвращает записи журнала модерации, начиная с последних
+func (r *moderationRepository) ListLogs(filter ModerationLogFilter, offset, limit int) ([]*models.ModerationLog, int64, error) {
+	query := r.db.Model(&models.ModerationLog{})
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.TargetUserID != nil {
+		query = query.Where("target_user_id = ?", *filter.TargetUserID)
+	}
+	if filter.ModeratorID != nil {
+		query = query.Where("moderator_id = ?", *filter.ModeratorID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		log.Printf("Error counting moderation logs: %v", err)
+		return nil, 0, fmt.Errorf("failed to get moderation logs: %w", err)
+	}
+
+	var logs []*models.ModerationLog
+	if err := query.Preload("Moderator").
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&logs).Error; err != nil {
+		log.Printf("Error getting moderation logs: %v", err)
+		return nil, 0, fmt.Errorf("failed to get moderation logs: %w", err)
+	}
+	return logs, count, nil
+}
+
+// UnhideCollection возвращает скрытую коллекцию в публичный доступ и пишет запись в журнал.
+// Возвращает false, если коллекция не скрыта.
+func (r *moderationRepository) UnhideCollection(collectionID uint, entry *models.ModerationLog) (bool, error) {
+	unhidden := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Collection{}).
+			Where("id = ? AND hidden_at IS NOT NULL", collectionID).
+			UpdateColumn("hidden_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		unhidden = true
+		return tx.Omit(clause.Associations).Create(entry).Error
+	})
+	if err != nil {
+		log.Printf("Error unhiding collection %d: %v", collectionID, err)
+		return false, fmt.Errorf("failed to unhide collection: %w", err)
+	}
+	return unhidden, nil
+}
+
+// transition выполняет условное изменение жалобы и, если оно применилось, пишет запись в журнал
+func (r *moderationRepository) transition(entry *models.ModerationLog, update func(tx *gorm.DB) *gorm.DB) (bool, error) {
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := update(tx)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		applied = true
+		return tx.Omit(clause.Associations).Create(entry).Error
+	})
+	if err != nil {
+		log.Printf("Error updating report on %s %d: %v", entry.TargetType, entry.TargetID, err)
+		return false, fmt.Errorf("failed to update report: %w", err)
+	}
+	return applied, nil
+}
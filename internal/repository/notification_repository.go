@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationRepository определяет методы для работы с уведомлениями пользователей
+type NotificationRepository interface {
+	Record(notification *models.Notification) (bool, error)
+	List(userID uint, unreadOnly bool, offset, limit int) ([]*models.Notification, int64, error)
+	CountUnread(userID uint) (int64, error)
+	MarkRead(userID, notificationID uint) (bool, error)
+	MarkAllRead(userID uint) (int64, error)
+}
+
+// notificationRepository реализует интерфейс NotificationRepository
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository создает новый экземпляр репозитория уведомлений
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+// Record создает уведомление или присоединяет событие к непрочитанному уведомлению
+// с тем же GroupKey. После вызова notification содержит итоговое уведомление с участником.
+// Возвращает false, если участник уже был в уведомлении и ActorCount не изменился.
+func (r *notificationRepository) Record(notification *models.Notification) (bool, error) {
+	added := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		notification.ActorCount = 1
+		result := tx.Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "user_id"}, {Name: "group_key"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "read_at IS NULL"}}},
+			DoNothing:   true,
+		}).Create(notification)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			added = true
+			return tx.Create(&models.NotificationActor{NotificationID: notification.ID, ActorID: notification.ActorID}).Error
+		}
+
+		// Непрочитанное уведомление уже есть - присоединяем к нему событие
+		var existing models.Notification
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND group_key = ? AND read_at IS NULL", notification.UserID, notification.GroupKey).
+			First(&existing).Error; err != nil {
+			return err
+		}
+		notification.ID = existing.ID
+
+		actor := &models.NotificationActor{NotificationID: existing.ID, ActorID: notification.ActorID}
+		result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(actor)
+		if result.Error != nil {
+			return result.Error
+		}
+		added = result.RowsAffected > 0
+
+		updates := map[string]interface{}{
+			"actor_id":   notification.ActorID,
+			"updated_at": time.Now(),
+		}
+		if added {
+			updates["actor_count"] = gorm.Expr("actor_count + 1")
+		}
+		return tx.Model(&existing).UpdateColumns(updates).Error
+	})
+	if err == nil {
+		err = r.db.Preload("Actor").First(notification, notification.ID).Error
+	}
+	if err != nil {
+		log.Printf("Error recording %s notification for user %d: %v", notification.Type, notification.UserID, err)
+		return false, fmt.Errorf("failed to record notification: %w", err)
+	}
+	return added, nil
+}
+
+// List возвращает уведомления пользователя, начиная с последних событий.
+// Уведомления о действиях заблокированных пользователей не возвращаются.
+func (r *notificationRepository) List(userID uint, unreadOnly bool, offset, limit int) ([]*models.Notification, int64, error) {
+	query := r.visible(userID, unreadOnly)
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		log.Printf("Error counting notifications of user %d: %v", userID, err)
+		return nil, 0, fmt.Errorf("failed to get notifications: %w", err)
+	}
+
+	var notifications []*models.Notification
+	if err := query.
+		Preload("Actor").
+		Order("notifications.updated_at DESC, notifications.id DESC").
+		Offset(offset).Limit(limit).
+		Find(&notifications).Error; err != nil {
+		log.Printf("Error getting notifications of user %d: %v", userID, err)
+		return nil, 0, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	return notifications, count, nil
+}
+
+// CountUnread возвращает количество непрочитанных уведомлений пользователя
+func (r *notificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	if err := r.visible(userID, true).Count(&count).Error; err != nil {
+		log.Printf("Error counting unread notifications of user %d: %v", userID, err)
+		return 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead отмечает уведомление прочитанным; возвращает false, если у пользователя его нет
+func (r *notificationRepository) MarkRead(userID, notificationID uint) (bool, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", notificationID, userID).
+		UpdateColumn("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	if result.Error != nil {
+		log.Printf("Error marking notification %d as read: %v", notificationID, result.Error)
+		return false, fmt.Errorf("failed to mark notification as read: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkAllRead отмечает прочитанными все уведомления пользователя и возвращает их количество
+func (r *notificationRepository) MarkAllRead(userID uint) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		UpdateColumn("read_at", time.Now())
+	if result.Error != nil {
+		log.Printf("Error marking notifications of user %d as read: %v", userID, result.Error)
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// visible возвращает запрос уведомлений пользователя без действий заблокированных им пользователей
+func (r *notificationRepository) visible(userID uint, unreadOnly bool) *gorm.DB {
+	query := r.db.Model(&models.Notification{}).Where("notifications.user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("notifications.read_at IS NULL")
+	}
+	return excludeBlocked(query, "notifications.actor_id", &userID)
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReviewRepository определяет методы для работы с отзывами о коллекциях
+type ReviewRepository interface {
+	GetByID(id uint) (*models.CollectionReview, error)
+	GetByUserAndCollection(userID, collectionID uint) (*models.CollectionReview, error)
+	ListByCollection(collectionID uint, viewerID *uint, offset, limit int) ([]*models.CollectionReview, int64, error)
+	Save(review *models.CollectionReview) (bool, error)
+	Delete(userID, collectionID uint) (bool, error)
+	UpdateReply(review *models.CollectionReview) error
+}
+
+// reviewRepository реализует интерфейс ReviewRepository
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewRepository создает новый экземпляр репозитория отзывов
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{
+		db: db,
+	}
+}
+
+// GetByID возвращает отзыв по ID
+func (r *reviewRepository) GetByID(id uint) (*models.CollectionReview, error) {
+	var review models.CollectionReview
+	if err := r.db.Preload("User").First(&review, id).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetByUserAndCollection возвращает отзыв пользователя о коллекции
+func (r *reviewRepository) GetByUserAndCollection(userID, collectionID uint) (*models.CollectionReview, error) {
+	var review models.CollectionReview
+	if err := r.db.Preload("User").
+		Where("user_id = ? AND collection_id = ?", userID, collectionID).
+		First(&review).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ListByCollection возвращает отзывы о коллекции, начиная с последних измененных.
+// Отзывы пользователей, заблокированных viewerID, не возвращаются.
+func (r *reviewRepository) ListByCollection(collectionID uint, viewerID *uint, offset, limit int) ([]*models.CollectionReview, int64, error) {
+	query := r.db.Model(&models.CollectionReview{}).Where("collection_id = ?", collectionID)
+	query = excludeBlocked(query, "user_id", viewerID)
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		log.Printf("Error counting reviews of collection %d: %v", collectionID, err)
+		return nil, 0, fmt.Errorf("failed to get reviews: %w", err)
+	}
+
+	var reviews []*models.CollectionReview
+	if err := query.Preload("User").
+		Order("updated_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&reviews).Error; err != nil {
+		log.Printf("Error getting reviews of collection %d: %v", collectionID, err)
+		return nil, 0, fmt.Errorf("failed to get reviews: %w", err)
+	}
+	return reviews, count, nil
+}
+
+// Save создает или обновляет отзыв пользователя и пересчитывает счетчики оценок коллекции.
+// Возвращает true, если отзыв создан.
+func (r *reviewRepository) Save(review *models.CollectionReview) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.CollectionReview
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND collection_id = ?", review.UserID, review.CollectionID).
+			First(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := tx.Omit(clause.Associations).Create(review).Error; err != nil {
+				return err
+			}
+			created = true
+			return tx.Model(&models.Collection{}).Where("id = ?", review.CollectionID).
+				UpdateColumns(map[string]interface{}{
+					"rating_count": gorm.Expr("rating_count + 1"),
+					"rating_sum":   gorm.Expr("rating_sum + ?", review.Rating),
+				}).Error
+		}
+
+		delta := review.Rating - existing.Rating
+		existing.Rating = review.Rating
+		existing.Text = review.Text
+		if err := tx.Omit(clause.Associations).Save(&existing).Error; err != nil {
+			return err
+		}
+		*review = existing
+		if delta == 0 {
+			return nil
+		}
+		return tx.Model(&models.Collection{}).Where("id = ?", review.CollectionID).
+			UpdateColumn("rating_sum", gorm.Expr("rating_sum + ?", delta)).Error
+	})
+	if err != nil {
+		log.Printf("Error saving review of user %d for collection %d: %v", review.UserID, review.CollectionID, err)
+		return false, fmt.Errorf("failed to save review: %w", err)
+	}
+	return created, nil
+}
+
+// Delete удаляет отзыв пользователя и вычитает его оценку из счетчиков коллекции.
+// Возвращает false, если отзыва не было.
+func (r *reviewRepository) Delete(userID, collectionID uint) (bool, error) {
+	deleted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var review models.CollectionReview
+		result := tx.Clauses(clause.Returning{}).
+			Where("user_id = ? AND collection_id = ?", userID, collectionID).
+			Delete(&review)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		deleted = true
+		return tx.Model(&models.Collection{}).Where("id = ?", collectionID).
+			UpdateColumns(map[string]interface{}{
+				"rating_count": gorm.Expr("GREATEST(rating_count - 1, 0)"),
+				"rating_sum":   gorm.Expr("GREATEST(rating_sum - ?, 0)", review.Rating),
+			}).Error
+	})
+	if err != nil {
+		log.Printf("Error deleting review of user %d for collection %d: %v", userID, collectionID, err)
+		return false, fmt.Errorf("failed to delete review: %w", err)
+	}
+	return deleted, nil
+}
+
+// UpdateReply сохраняет ответ владельца коллекции на отзыв
+func (r *reviewRepository) UpdateReply(review *models.CollectionReview) error {
+	if err := r.db.Model(&models.CollectionReview{}).Where("id = ?", review.ID).
+		UpdateColumns(map[string]interface{}{
+			"owner_reply":      review.OwnerReply,
+			"owner_replied_at": review.OwnerRepliedAt,
+		}).Error; err != nil {
+		log.Printf("Error saving reply to review %d: %v", review.ID, err)
+		return fmt.Errorf("failed to save reply: %w", err)
+	}
+	return nil
+}
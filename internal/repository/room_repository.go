@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RoomRepository определяет методы для работы с игровыми комнатами в базе данных
+type RoomRepository interface {
+	Create(room *models.Room) error
+	GetByCode(code string) (*models.Room, error)
+	GetMemberByToken(token string) (*models.RoomMember, error)
+	Update(room *models.Room) error
+	AddMember(member *models.RoomMember) error
+	UpdateMember(member *models.RoomMember) error
+	GetSharedRoomCodes(userID, otherID uint) ([]string, error)
+}
+
+// roomRepository реализует интерфейс RoomRepository
+type roomRepository struct {
+	db *gorm.DB
+}
+
+// NewRoomRepository создает новый экземпляр репозитория игровых комнат
+func NewRoomRepository(db *gorm.DB) RoomRepository {
+	return &roomRepository{
+		db: db,
+	}
+}
+
+// Create сохраняет новую комнату вместе с ведущим и проставляет HostMemberID
+func (r *roomRepository) Create(room *models.Room) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(room).Error; err != nil {
+			log.Printf("Error creating room: %v", err)
+			return fmt.Errorf("failed to create room: %w", err)
+		}
+
+		for _, member := range room.Members {
+			if member.IsHost {
+				room.HostMemberID = member.ID
+			}
+		}
+		if err := tx.Model(room).UpdateColumn("host_member_id", room.HostMemberID).Error; err != nil {
+			log.Printf("Error setting host of room %s: %v", room.Code, err)
+			return fmt.Errorf("failed to create room: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetByCode возвращает комнату по коду вместе с участниками
+func (r *roomRepository) GetByCode(code string) (*models.Room, error) {
+	var room models.Room
+	err := r.db.
+		Preload("Members", func(db *gorm.DB) *gorm.DB { return db.Order("joined_at ASC, id ASC") }).
+		Where("code = ?", code).
+		First(&room).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("room %s not found", code)
+		}
+		log.Printf("Error getting room %s: %v", code, err)
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+	return &room, nil
+}
+
+// GetMemberByToken возвращает участника комнаты по токену переподключения
+func (r *roomRepository) GetMemberByToken(token string) (*models.RoomMember, error) {
+	var member models.RoomMember
+	if err := r.db.Where("token = ?", token).First(&member).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("room member not found")
+		}
+		log.Printf("Error getting room member by token: %v", err)
+		return nil, fmt.Errorf("failed to get room member: %w", err)
+	}
+	return &member, nil
+}
+
+// Update сохраняет поля комнаты (без участников)
+func (r *roomRepository) Update(room *models.Room) error {
+	if err := r.db.Omit(clause.Associations).Save(room).Error; err != nil {
+		log.Printf("Error updating room %s: %v", room.Code, err)
+		return fmt.Errorf("failed to update room: %w", err)
+	}
+	return nil
+}
+
+// AddMember добавляет участника в комнату
+func (r *roomRepository) AddMember(member *models.RoomMember) error {
+	if err := r.db.Create(member).Error; err != nil {
+		log.Printf("Error adding member to room %d: %v", member.RoomID, err)
+		return fmt.Errorf("failed to add room member: %w", err)
+	}
+	return nil
+}
+
+// GetSharedRoomCodes возвращает коды незавершенных комнат, в которых оба пользователя
+// сейчас являются участниками
+func (r *roomRepository) GetSharedRoomCodes(userID, otherID uint) ([]string, error) {
+	var codes []string
+	err := r.db.Model(&models.Room{}).
+		Where("rooms.status <> ?", models.RoomStatusFinished).
+		Where("EXISTS (SELECT 1 FROM room_members m WHERE m.room_id = rooms.id AND m.user_id = ? AND m.left_at IS NULL)", userID).
+		Where("EXISTS (SELECT 1 FROM room_members m WHERE m.room_id = rooms.id AND m.user_id = ? AND m.left_at IS NULL)", otherID).
+		Pluck("rooms.code", &codes).Error
+	if err != nil {
+		log.Printf("Error getting rooms shared by users %d and %d: %v", userID, otherID, err)
+		return nil, fmt.Errorf("failed to get shared rooms: %w", err)
+	}
+	return codes, nil
+}
+
+// UpdateMember сохраняет данные участника комнаты
+func (r *roomRepository) UpdateMember(member *models.RoomMember) error {
+	if err := r.db.Save(member).Error; err != nil {
+		log.Printf("Error updating room member %d: %v", member.ID, err)
+		return fmt.Errorf("failed to update room member: %w", err)
+	}
+	return nil
+}
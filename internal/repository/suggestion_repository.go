@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SuggestionRepository определяет методы для работы с предложенными карточками
+type SuggestionRepository interface {
+	Create(suggestion *models.CardSuggestion) error
+	GetByID(id uint) (*models.CardSuggestion, error)
+	Update(suggestion *models.CardSuggestion) error
+	Review(suggestion *models.CardSuggestion) (bool, error)
+	GetInbox(ownerID uint, collectionID *uint, status models.SuggestionStatus, offset, limit int) ([]*models.CardSuggestion, int64, error)
+	GetByUserID(userID uint, status models.SuggestionStatus, offset, limit int) ([]*models.CardSuggestion, int64, error)
+}
+
+// suggestionRepository реализует интерфейс SuggestionRepository
+type suggestionRepository struct {
+	db *gorm.DB
+}
+
+// NewSuggestionRepository создает новый экземпляр репозитория предложенных карточек
+func NewSuggestionRepository(db *gorm.DB) SuggestionRepository {
+	return &suggestionRepository{
+		db: db,
+	}
+}
+
+// Create сохраняет новое предложение
+func (r *suggestionRepository) Create(suggestion *models.CardSuggestion) error {
+	if err := r.db.Omit(clause.Associations).Create(suggestion).Error; err != nil {
+		log.Printf("Error creating suggestion for collection %d: %v", suggestion.CollectionID, err)
+		return fmt.Errorf("failed to create suggestion: %w", err)
+	}
+	return nil
+}
+
+// GetByID возвращает предложение по ID вместе с автором и коллекцией
+func (r *suggestionRepository) GetByID(id uint) (*models.CardSuggestion, error) {
+	var suggestion models.CardSuggestion
+	if err := r.db.Preload("User").Preload("Collection").First(&suggestion, id).Error; err != nil {
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+// Update сохраняет изменения предложения
+func (r *suggestionRepository) Update(suggestion *models.CardSuggestion) error {
+	if err := r.db.Omit(clause.Associations).Save(suggestion).Error; err != nil {
+		log.Printf("Error updating suggestion %d: %v", suggestion.ID, err)
+		return fmt.Errorf("failed to update suggestion: %w", err)
+	}
+	return nil
+}
+
+// Review сохраняет решение по предложению, только если оно еще ожидает рассмотрения.
+// Возвращает false, если решение уже было принято другим запросом.
+func (r *suggestionRepository) Review(suggestion *models.CardSuggestion) (bool, error) {
+	result := r.db.Model(&models.CardSuggestion{}).
+		Where("id = ? AND status = ?", suggestion.ID, models.SuggestionPending).
+		Updates(map[string]interface{}{
+			"status":        suggestion.Status,
+			"reject_reason": suggestion.RejectReason,
+			"reviewed_at":   suggestion.ReviewedAt,
+		})
+	if result.Error != nil {
+		log.Printf("Error reviewing suggestion %d: %v", suggestion.ID, result.Error)
+		return false, fmt.Errorf("failed to review suggestion: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetInbox возвращает предложения к коллекциям владельца (при collectionID - к одной коллекции).
+// Пустой status не ограничивает выборку. Предложения заблокированных владельцем пользователей не возвращаются.
+func (r *suggestionRepository) GetInbox(ownerID uint, collectionID *uint, status models.SuggestionStatus, offset, limit int) ([]*models.CardSuggestion, int64, error) {
+	query := r.db.Model(&models.CardSuggestion{}).
+		Joins("JOIN collections ON collections.id = card_suggestions.collection_id AND collections.deleted_at IS NULL").
+		Where("collections.user_id = ?", ownerID)
+	query = excludeBlocked(query, "card_suggestions.user_id", &ownerID)
+	if collectionID != nil {
+		query = query.Where("card_suggestions.collection_id = ?", *collectionID)
+	}
+	if status != "" {
+		query = query.Where("card_suggestions.status = ?", status)
+	}
+	return r.list(query, offset, limit)
+}
+
+// GetByUserID возвращает предложения, отправленные пользователем
+func (r *suggestionRepository) GetByUserID(userID uint, status models.SuggestionStatus, offset, limit int) ([]*models.CardSuggestion, int64, error) {
+	query := r.db.Model(&models.CardSuggestion{}).Where("card_suggestions.user_id = ?", userID)
+	if status != "" {
+		query = query.Where("card_suggestions.status = ?", status)
+	}
+	return r.list(query, offset, limit)
+}
+
+// list выполняет постраничную выборку предложений, начиная с новых
+func (r *suggestionRepository) list(query *gorm.DB, offset, limit int) ([]*models.CardSuggestion, int64, error) {
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		log.Printf("Error counting suggestions: %v", err)
+		return nil, 0, fmt.Errorf("failed to get suggestions: %w", err)
+	}
+
+	var suggestions []*models.CardSuggestion
+	if err := query.
+		Select("card_suggestions.*").
+		Preload("User").Preload("Collection").
+		Order("card_suggestions.created_at DESC, card_suggestions.id DESC").
+		Offset(offset).Limit(limit).
+		Find(&suggestions).Error; err != nil {
+		log.Printf("Error getting suggestions: %v", err)
+		return nil, 0, fmt.Errorf("failed to get suggestions: %w", err)
+	}
+	return suggestions, count, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"gorm.io/gorm"
+)
+
+// TrendingDecay задает затухание вклада событий: каждое событие весит
+// 1 / (возраст в часах + OffsetHours)^Gravity. При Gravity = 0 события не затухают.
+type TrendingDecay struct {
+	Now         time.Time
+	Since       *time.Time // Начало периода; nil - за все время
+	OffsetHours float64
+	Gravity     float64
+}
+
+// TrendingSignal представляет сигнал популярности одной коллекции
+type TrendingSignal struct {
+	CollectionID uint
+	Count        int     // Количество событий за период
+	Weight       float64 // Сумма вкладов событий с учетом затухания
+}
+
+// TrendingRepository определяет методы для расчета и хранения популярных коллекций
+type TrendingRepository interface {
+	GetPlaySignals(decay TrendingDecay) ([]*TrendingSignal, error)
+	GetForkSignals(decay TrendingDecay) ([]*TrendingSignal, error)
+	GetLikeSignals(decay TrendingDecay) ([]*TrendingSignal, error)
+	ReplaceWindow(window models.TrendingWindow, scores []*models.TrendingScore) error
+}
+
+// trendingRepository реализует интерфейс TrendingRepository
+type trendingRepository struct {
+	db *gorm.DB
+}
+
+// NewTrendingRepository создает новый экземпляр репозитория популярных коллекций
+func NewTrendingRepository(db *gorm.DB) TrendingRepository {
+	return &trendingRepository{
+		db: db,
+	}
+}
+
+// GetPlaySignals возвращает запуски игр по коллекциям
+func (r *trendingRepository) GetPlaySignals(decay TrendingDecay) ([]*TrendingSignal, error) {
+	query := r.db.Table("game_sessions").
+		Joins("JOIN collections ON collections.id = game_sessions.collection_id AND collections.deleted_at IS NULL")
+	return r.collectSignals(query, "game_sessions.collection_id", "game_sessions.started_at", decay)
+}
+
+// GetForkSignals возвращает миксы, созданные на основе коллекций
+func (r *trendingRepository) GetForkSignals(decay TrendingDecay) ([]*TrendingSignal, error) {
+	query := r.db.Table("deck_mix_sources").
+		Joins("JOIN collections mixes ON mixes.id = deck_mix_sources.mix_collection_id AND mixes.deleted_at IS NULL").
+		Joins("JOIN collections ON collections.id = deck_mix_sources.source_collection_id AND collections.deleted_at IS NULL")
+	return r.collectSignals(query, "deck_mix_sources.source_collection_id", "mixes.created_at", decay)
+}
+
+// GetLikeSignals возвращает лайки коллекций
+func (r *trendingRepository) GetLikeSignals(decay TrendingDecay) ([]*TrendingSignal, error) {
+	query := r.db.Table("collection_likes").
+		Joins("JOIN collections ON collections.id = collection_likes.collection_id AND collections.deleted_at IS NULL")
+	return r.collectSignals(query, "collection_likes.collection_id", "collection_likes.created_at", decay)
+}
+
+// collectSignals группирует события по коллекциям и суммирует их вклад с затуханием
+func (r *trendingRepository) collectSignals(query *gorm.DB, collectionColumn, timeColumn string, decay TrendingDecay) ([]*TrendingSignal, error) {
+	if decay.Since != nil {
+		query = query.Where(timeColumn+" >= ?", *decay.Since)
+	}
+
+	weight := fmt.Sprintf(
+		"SUM(POWER(GREATEST(EXTRACT(EPOCH FROM (?::timestamptz - %s)) / 3600.0, 0) + ?, -?)) AS weight", timeColumn)
+
+	var signals []*TrendingSignal
+	if err := query.
+		Select(collectionColumn+" AS collection_id, COUNT(*) AS count, "+weight, decay.Now, decay.OffsetHours, decay.Gravity).
+		Group(collectionColumn).
+		Scan(&signals).Error; err != nil {
+		log.Printf("Error collecting trending signals from %s: %v", collectionColumn, err)
+		return nil, fmt.Errorf("failed to collect trending signals: %w", err)
+	}
+	return signals, nil
+}
+
+// ReplaceWindow атомарно заменяет рейтинг популярности за период
+func (r *trendingRepository) ReplaceWindow(window models.TrendingWindow, scores []*models.TrendingScore) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("trending_window = ?", window).Delete(&models.TrendingScore{}).Error; err != nil {
+			log.Printf("Error clearing trending window %s: %v", window, err)
+			return fmt.Errorf("failed to replace trending scores: %w", err)
+		}
+		if len(scores) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(scores, 500).Error; err != nil {
+			log.Printf("Error saving trending window %s: %v", window, err)
+			return fmt.Errorf("failed to replace trending scores: %w", err)
+		}
+		return nil
+	})
+}
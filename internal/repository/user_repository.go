@@ -1,8 +1,12 @@
 package repository
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/KoLili12/bulb-server/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CollectionRepository определяет методы для работы с коллекциями в базе данных
@@ -10,11 +14,18 @@ type CollectionRepository interface {
 	Create(collection *models.Collection) error
 	GetByID(id uint) (*models.Collection, error)
 	GetByUserID(userID uint) ([]*models.Collection, error)
-	GetTrending(limit int) ([]*models.Collection, error)
+	GetTrending(window models.TrendingWindow, limit int, visibility models.CollectionVisibility) ([]*models.Collection, error)
 	Update(collection *models.Collection) error
 	Delete(id uint) error
-	List(offset, limit int) ([]*models.Collection, int64, error)
+	List(offset, limit int, sort models.CollectionSort, visibility models.CollectionVisibility) ([]*models.Collection, int64, error)
+	ListByUser(userID uint, offset, limit int, sort models.CollectionSort, maxAgeRating int) ([]*models.Collection, int64, error)
+	GetCreatorStats(userID uint) (*models.CreatorStats, error)
 	IncrementPlayCount(id uint) error
+	SetHidden(id uint, hiddenAt *time.Time) error
+	Touch(id uint) error
+	UpdateAgeRating(id uint, ageRating int) error
+	GetMixIDsBySource(sourceID uint) ([]uint, error)
+	BackfillAgeRatings() (int64, error)
 }
 
 // collectionRepository реализует интерфейс CollectionRepository
@@ -37,7 +48,7 @@ func (r *collectionRepository) Create(collection *models.Collection) error {
 // GetByID возвращает коллекцию по ID
 func (r *collectionRepository) GetByID(id uint) (*models.Collection, error) {
 	var collection models.Collection
-	if err := r.db.Preload("Actions").First(&collection, id).Error; err != nil {
+	if err := r.db.Preload("Actions").Preload("MixSources").First(&collection, id).Error; err != nil {
 		return nil, err
 	}
 	return &collection, nil
@@ -52,18 +63,36 @@ func (r *collectionRepository) GetByUserID(userID uint) ([]*models.Collection, e
 	return collections, nil
 }
 
-// GetTrending возвращает список популярных коллекций
-func (r *collectionRepository) GetTrending(limit int) ([]*models.Collection, error) {
+// GetTrending возвращает популярные коллекции за период по предрасчитанному рейтингу.
+// Коллекции, недоступные по visibility, не возвращаются.
+func (r *collectionRepository) GetTrending(window models.TrendingWindow, limit int, visibility models.CollectionVisibility) ([]*models.Collection, error) {
 	var collections []*models.Collection
-	if err := r.db.Order("play_count DESC").Limit(limit).Find(&collections).Error; err != nil {
+	if err := r.visible(visibility).
+		Select("collections.*").
+		Joins("JOIN trending_scores ON trending_scores.collection_id = collections.id AND trending_scores.trending_window = ?", window).
+		Order("trending_scores.rank ASC").
+		Limit(limit).
+		Find(&collections).Error; err != nil {
 		return nil, err
 	}
+
+	// Рейтинг еще не рассчитан или за период не было активности - показываем самые запускаемые
+	if len(collections) == 0 {
+		if err := r.visible(visibility).Order("play_count DESC").Limit(limit).Find(&collections).Error; err != nil {
+			return nil, err
+		}
+	}
 	return collections, nil
 }
 
-// Update обновляет данные коллекции
+// Update сохраняет редактируемые автором поля коллекции. Счетчики, оценки и скрытие
+// модератором меняются отдельными запросами и не перезаписываются значениями
+// из загруженной ранее модели.
 func (r *collectionRepository) Update(collection *models.Collection) error {
-	return r.db.Save(collection).Error
+	return r.db.Model(collection).
+		Select("name", "description", "image_url", "declared_age_rating", "auto_adult", "age_rating", "updated_at").
+		Omit(clause.Associations).
+		Updates(collection).Error
 }
 
 // Delete удаляет коллекцию (soft delete через GORM)
@@ -71,30 +100,133 @@ func (r *collectionRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Collection{}, id).Error
 }
 
-// List возвращает список коллекций с пагинацией
-func (r *collectionRepository) List(offset, limit int) ([]*models.Collection, int64, error) {
+// List возвращает список коллекций с пагинацией в заданном порядке.
+// Коллекции, недоступные по visibility, не возвращаются.
+func (r *collectionRepository) List(offset, limit int, sort models.CollectionSort, visibility models.CollectionVisibility) ([]*models.Collection, int64, error) {
+	return r.list(r.visible(visibility), offset, limit, sort)
+}
+
+// visible отбирает коллекции для публичных списков: не скрытые модератором, подходящие
+// по возрастному рейтингу и не принадлежащие авторам, которых заблокировал пользователь
+func (r *collectionRepository) visible(visibility models.CollectionVisibility) *gorm.DB {
+	query := r.db.Model(&models.Collection{}).
+		Where("collections.hidden_at IS NULL AND collections.age_rating <= ?", visibility.MaxAgeRating)
+	return excludeBlocked(query, "collections.user_id", visibility.ViewerID)
+}
+
+// ListByUser возвращает публичные коллекции автора с пагинацией в заданном порядке.
+// Коллекции с возрастным рейтингом выше maxAgeRating не возвращаются.
+func (r *collectionRepository) ListByUser(userID uint, offset, limit int, sort models.CollectionSort, maxAgeRating int) ([]*models.Collection, int64, error) {
+	visible := r.db.Model(&models.Collection{}).
+		Where("user_id = ? AND hidden_at IS NULL AND age_rating <= ?", userID, maxAgeRating)
+	return r.list(visible, offset, limit, sort)
+}
+
+// list возвращает страницу коллекций из visible в заданном порядке и их общее количество
+func (r *collectionRepository) list(visible *gorm.DB, offset, limit int, sort models.CollectionSort) ([]*models.Collection, int64, error) {
 	var collections []*models.Collection
 	var count int64
 
 	// Получаем общее количество коллекций
-	if err := r.db.Model(&models.Collection{}).Count(&count).Error; err != nil {
+	if err := visible.Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Получаем список коллекций с пагинацией
-	if err := r.db.Offset(offset).Limit(limit).Find(&collections).Error; err != nil {
+	query := visible
+	switch sort {
+	case models.CollectionSortNewest:
+		query = query.Order("created_at DESC, id DESC")
+	case models.CollectionSortPopular:
+		query = query.Order("play_count DESC, id DESC")
+	case models.CollectionSortRating:
+		query = query.Order(bayesianRatingSQL + " DESC, rating_count DESC, id DESC")
+	}
+	if err := query.Offset(offset).Limit(limit).Find(&collections).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return collections, count, nil
 }
 
+// GetCreatorStats возвращает суммарную статистику коллекций автора, не скрытых модератором
+func (r *collectionRepository) GetCreatorStats(userID uint) (*models.CreatorStats, error) {
+	var stats models.CreatorStats
+	if err := r.db.Model(&models.Collection{}).
+		Select("COUNT(*) AS collection_count, COALESCE(SUM(play_count), 0) AS total_plays, "+
+			"COALESCE(SUM(rating_count), 0) AS rating_count, COALESCE(SUM(rating_sum), 0) AS rating_sum").
+		Where("user_id = ? AND hidden_at IS NULL", userID).
+		Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// bayesianRatingSQL вычисляет models.Collection.BayesianRating на стороне базы
+var bayesianRatingSQL = fmt.Sprintf("(rating_sum + %g) / (rating_count + %d.0)",
+	models.RatingPriorMean*models.RatingPriorWeight, models.RatingPriorWeight)
+
 // IncrementPlayCount увеличивает счетчик запусков коллекции
 func (r *collectionRepository) IncrementPlayCount(id uint) error {
 	return r.db.Model(&models.Collection{}).Where("id = ?", id).
 		UpdateColumn("play_count", gorm.Expr("play_count + ?", 1)).Error
 }
 
+// SetHidden скрывает коллекцию из публичного доступа (nil - снова показывает)
+func (r *collectionRepository) SetHidden(id uint, hiddenAt *time.Time) error {
+	return r.db.Model(&models.Collection{}).Where("id = ?", id).
+		UpdateColumn("hidden_at", hiddenAt).Error
+}
+
+// Touch отмечает коллекцию измененной, например после изменения карточек,
+// чтобы она поднялась в ленте подписчиков
+func (r *collectionRepository) Touch(id uint) error {
+	return r.db.Model(&models.Collection{}).Where("id = ?", id).
+		UpdateColumn("updated_at", time.Now()).Error
+}
+
+// UpdateAgeRating сохраняет пересчитанный возрастной рейтинг коллекции
+func (r *collectionRepository) UpdateAgeRating(id uint, ageRating int) error {
+	return r.db.Model(&models.Collection{}).Where("id = ?", id).
+		UpdateColumn("age_rating", ageRating).Error
+}
+
+// BackfillAgeRatings повышает возрастной рейтинг коллекций, у которых он ниже рассчитанного
+// по карточкам (для миксов - по карточкам источников). Нужен для коллекций, созданных
+// до появления рейтинга; повторный запуск ничего не меняет.
+func (r *collectionRepository) BackfillAgeRatings() (int64, error) {
+	result := r.db.Exec(fmt.Sprintf(
+		"UPDATE collections SET age_rating = %[1]s WHERE deleted_at IS NULL AND age_rating < %[1]s",
+		resolvedAgeRatingSQL))
+	return result.RowsAffected, result.Error
+}
+
+// resolvedAgeRatingSQL вычисляет models.Collection.ResolveAgeRating на стороне базы
+var resolvedAgeRatingSQL = func() string {
+	spiciness := "CASE"
+	for level := models.MaxSpiciness; level > models.MinSpiciness; level-- {
+		spiciness += fmt.Sprintf(" WHEN MAX(a.spiciness) >= %d THEN %d", level, models.AgeRatingForSpiciness(level))
+	}
+	spiciness += fmt.Sprintf(" ELSE %d END", models.AgeRatingAll)
+
+	return fmt.Sprintf("GREATEST(declared_age_rating, CASE WHEN auto_adult THEN %d ELSE %d END, "+
+		"(SELECT %s FROM actions a WHERE a.deleted_at IS NULL AND (a.collection_id = collections.id OR "+
+		"a.collection_id IN (SELECT source_collection_id FROM deck_mix_sources WHERE mix_collection_id = collections.id))))",
+		models.AgeRating18, models.AgeRatingAll, spiciness)
+}()
+
+// GetMixIDsBySource возвращает ID виртуальных коллекций, собирающих карточки из коллекции sourceID
+func (r *collectionRepository) GetMixIDsBySource(sourceID uint) ([]uint, error) {
+	var ids []uint
+	if err := r.db.Model(&models.DeckMixSource{}).
+		Where("source_collection_id = ?", sourceID).
+		Distinct().
+		Pluck("mix_collection_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 // UserRepository определяет методы для работы с пользователями в базе данных
 type UserRepository interface {
 	Create(user *models.User) error
@@ -140,9 +272,10 @@ func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
-// Update обновляет данные пользователя
+// Update обновляет данные пользователя. Счетчики подписок меняются только вместе
+// с подписками, поэтому не перезаписываются значениями из загруженной ранее модели.
 func (r *userRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+	return r.db.Omit("FollowerCount", "FollowingCount").Save(user).Error
 }
 
 // Delete удаляет пользователя (soft delete через GORM)
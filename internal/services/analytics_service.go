@@ -0,0 +1,164 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+var (
+	ErrInvalidAnalyticsRange = errors.New("invalid analytics date range")
+)
+
+// MaxAnalyticsDays ограничивает длину периода в запросе аналитики
+const MaxAnalyticsDays = 366
+
+// rollupOverlap компенсирует события, транзакции которых завершились во время предыдущего пересчета
+const rollupOverlap = time.Minute
+
+// DailyPlayStats представляет статистику партий коллекции за один день
+type DailyPlayStats struct {
+	Day                    time.Time
+	Plays                  int
+	CompletedPlays         int
+	AnonymousPlays         int
+	UniqueUsers            int
+	CardsDrawn             int
+	AverageDurationSeconds float64
+}
+
+// CollectionAnalytics представляет статистику партий коллекции за период
+type CollectionAnalytics struct {
+	CollectionID           uint
+	From                   time.Time
+	To                     time.Time
+	Days                   []DailyPlayStats // Каждый день периода, включая дни без партий
+	TotalPlays             int
+	CompletedPlays         int
+	AnonymousPlays         int
+	UniqueUsers            int64
+	CardsDrawn             int
+	AverageDurationSeconds float64
+	CompletionRate         float64 // Доля партий, завершенных игроками, от 0 до 1
+	AllTimePlayCount       int     // Счетчик запусков коллекции за все время
+}
+
+// AnalyticsService определяет методы сервиса статистики партий
+type AnalyticsService interface {
+	GetCollectionAnalytics(collectionID, userID uint, from, to time.Time) (*CollectionAnalytics, error)
+	Rollup() (int64, error)
+}
+
+// analyticsService реализует интерфейс AnalyticsService
+type analyticsService struct {
+	analyticsRepo     repository.AnalyticsRepository
+	collectionService CollectionService
+
+	mu         sync.Mutex
+	lastRollup time.Time // Нулевое значение - при первом запуске пересчитываются все дни
+}
+
+// NewAnalyticsService создает новый экземпляр сервиса статистики партий
+func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository, collectionService CollectionService) AnalyticsService {
+	return &analyticsService{
+		analyticsRepo:     analyticsRepo,
+		collectionService: collectionService,
+	}
+}
+
+// GetCollectionAnalytics возвращает статистику коллекции по дням за период [from, to].
+// Доступна только владельцу коллекции.
+func (s *analyticsService) GetCollectionAnalytics(collectionID, userID uint, from, to time.Time) (*CollectionAnalytics, error) {
+	collection, err := s.collectionService.GetByID(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if collection.UserID != userID {
+		return nil, ErrNotCollectionOwner
+	}
+
+	from = truncateToDay(from)
+	to = truncateToDay(to)
+	if to.Before(from) || to.Sub(from) >= MaxAnalyticsDays*24*time.Hour {
+		return nil, ErrInvalidAnalyticsRange
+	}
+
+	stats, err := s.analyticsRepo.GetDailyStats(collectionID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	uniqueUsers, err := s.analyticsRepo.CountUniqueUsers(collectionID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &CollectionAnalytics{
+		CollectionID:     collectionID,
+		From:             from,
+		To:               to,
+		UniqueUsers:      uniqueUsers,
+		AllTimePlayCount: collection.PlayCount,
+	}
+
+	byDay := make(map[string]int, len(stats))
+	for i, stat := range stats {
+		byDay[stat.Day.Format("2006-01-02")] = i
+	}
+
+	var totalDuration int64
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		daily := DailyPlayStats{Day: day}
+		if i, ok := byDay[day.Format("2006-01-02")]; ok {
+			stat := stats[i]
+			daily.Plays = stat.Plays
+			daily.CompletedPlays = stat.CompletedPlays
+			daily.AnonymousPlays = stat.AnonymousPlays
+			daily.UniqueUsers = stat.UniqueUsers
+			daily.CardsDrawn = stat.CardsDrawn
+			if stat.Plays > 0 {
+				daily.AverageDurationSeconds = float64(stat.TotalDurationSeconds) / float64(stat.Plays)
+			}
+			totalDuration += stat.TotalDurationSeconds
+		}
+
+		analytics.Days = append(analytics.Days, daily)
+		analytics.TotalPlays += daily.Plays
+		analytics.CompletedPlays += daily.CompletedPlays
+		analytics.AnonymousPlays += daily.AnonymousPlays
+		analytics.CardsDrawn += daily.CardsDrawn
+	}
+
+	if analytics.TotalPlays > 0 {
+		analytics.AverageDurationSeconds = float64(totalDuration) / float64(analytics.TotalPlays)
+		analytics.CompletionRate = float64(analytics.CompletedPlays) / float64(analytics.TotalPlays)
+	}
+
+	return analytics, nil
+}
+
+// Rollup пересчитывает дневные сводки за дни, в которые появились новые события
+func (s *analyticsService) Rollup() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	startedAt := time.Now()
+	since := s.lastRollup
+	if !since.IsZero() {
+		since = since.Add(-rollupOverlap)
+	}
+
+	rows, err := s.analyticsRepo.RollupDays(since)
+	if err != nil {
+		return 0, err
+	}
+	s.lastRollup = startedAt
+	return rows, nil
+}
+
+// truncateToDay отбрасывает время, оставляя начало дня в UTC
+func truncateToDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
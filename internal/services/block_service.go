@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+var (
+	ErrUserBlocked     = errors.New("interaction is blocked between users")
+	ErrCannotBlockSelf = errors.New("cannot block yourself")
+)
+
+// BlockService определяет методы сервиса блокировок пользователей.
+// Блокировка и разблокировка идемпотентны.
+type BlockService interface {
+	Block(blockerID, blockedID uint) error
+	Unblock(blockerID, blockedID uint) error
+	GetBlocked(blockerID uint, page, pageSize int) ([]*models.User, int64, error)
+}
+
+// blockService реализует интерфейс BlockService
+type blockService struct {
+	blockRepo   repository.BlockRepository
+	followRepo  repository.FollowRepository
+	userRepo    repository.UserRepository
+	roomService RoomService
+}
+
+// NewBlockService создает новый экземпляр сервиса блокировок
+func NewBlockService(blockRepo repository.BlockRepository, followRepo repository.FollowRepository, userRepo repository.UserRepository, roomService RoomService) BlockService {
+	return &blockService{
+		blockRepo:   blockRepo,
+		followRepo:  followRepo,
+		userRepo:    userRepo,
+		roomService: roomService,
+	}
+}
+
+// Block блокирует пользователя, снимает подписки между пользователями в обе стороны
+// и выводит заблокированного из комнат, где они играют вместе
+func (s *blockService) Block(blockerID, blockedID uint) error {
+	if err := s.validate(blockerID, blockedID); err != nil {
+		return err
+	}
+
+	if _, err := s.blockRepo.Block(blockerID, blockedID); err != nil {
+		return err
+	}
+	if _, err := s.followRepo.Unfollow(blockerID, blockedID); err != nil {
+		return err
+	}
+	if _, err := s.followRepo.Unfollow(blockedID, blockerID); err != nil {
+		return err
+	}
+	return s.roomService.RemoveBlocked(blockerID, blockedID)
+}
+
+// Unblock снимает блокировку; снятые при блокировке подписки не восстанавливаются
+func (s *blockService) Unblock(blockerID, blockedID uint) error {
+	if err := s.validate(blockerID, blockedID); err != nil {
+		return err
+	}
+
+	_, err := s.blockRepo.Unblock(blockerID, blockedID)
+	return err
+}
+
+// GetBlocked возвращает пользователей, заблокированных blockerID
+func (s *blockService) GetBlocked(blockerID uint, page, pageSize int) ([]*models.User, int64, error) {
+	if blockerID == 0 {
+		return nil, 0, ErrInvalidUserID
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return s.blockRepo.GetBlocked(blockerID, (page-1)*pageSize, pageSize)
+}
+
+// validate проверяет участников блокировки
+func (s *blockService) validate(blockerID, blockedID uint) error {
+	if blockerID == 0 {
+		return ErrInvalidUserID
+	}
+	if blockerID == blockedID {
+		return ErrCannotBlockSelf
+	}
+	if _, err := s.userRepo.GetByID(blockedID); err != nil {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// checkNotBlocked возвращает ErrUserBlocked, если один из пользователей заблокировал другого
+func checkNotBlocked(blockRepo repository.BlockRepository, userID, otherID uint) error {
+	blocked, err := blockRepo.IsBlockedBetween(userID, otherID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrUserBlocked
+	}
+	return nil
+}
+
+// checkProfileVisible возвращает ErrUserNotFound, если владелец профиля заблокировал зрителя:
+// для заблокированного пользователя профиль выглядит несуществующим
+func checkProfileVisible(blockRepo repository.BlockRepository, ownerID uint, viewerID *uint) error {
+	if viewerID == nil || *viewerID == ownerID {
+		return nil
+	}
+	blocked, err := blockRepo.HasBlocked(ownerID, *viewerID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+var (
+	ErrActionNotFound = errors.New("action not found")
+	ErrInvalidVote    = errors.New("vote must be 1 or -1")
+)
+
+// CardQualityReport содержит карточку коллекции и обратную связь по ней
+type CardQualityReport struct {
+	Action  *models.Action
+	Quality *models.CardQuality
+}
+
+// CardFeedbackService определяет методы сервиса обратной связи по карточкам
+type CardFeedbackService interface {
+	Vote(userID, actionID uint, value int) (*models.CardQuality, error)
+	RemoveVote(userID, actionID uint) (*models.CardQuality, error)
+	GetCollectionQuality(collectionID, userID uint) ([]*CardQualityReport, error)
+}
+
+// cardFeedbackService реализует интерфейс CardFeedbackService
+type cardFeedbackService struct {
+	feedbackRepo      repository.CardFeedbackRepository
+	actionRepo        repository.ActionRepository
+	collectionService CollectionService
+}
+
+// NewCardFeedbackService создает новый экземпляр сервиса обратной связи по карточкам
+func NewCardFeedbackService(
+	feedbackRepo repository.CardFeedbackRepository,
+	actionRepo repository.ActionRepository,
+	collectionService CollectionService,
+) CardFeedbackService {
+	return &cardFeedbackService{
+		feedbackRepo:      feedbackRepo,
+		actionRepo:        actionRepo,
+		collectionService: collectionService,
+	}
+}
+
+// Vote сохраняет голос пользователя за карточку (повторный голос заменяет предыдущий)
+func (s *cardFeedbackService) Vote(userID, actionID uint, value int) (*models.CardQuality, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if value != models.VoteUp && value != models.VoteDown {
+		return nil, ErrInvalidVote
+	}
+	if _, err := s.actionRepo.GetByID(actionID); err != nil {
+		return nil, ErrActionNotFound
+	}
+
+	if err := s.feedbackRepo.SetVote(&models.ActionVote{UserID: userID, ActionID: actionID, Value: value}); err != nil {
+		return nil, err
+	}
+	return s.quality(actionID)
+}
+
+// RemoveVote удаляет голос пользователя за карточку
+func (s *cardFeedbackService) RemoveVote(userID, actionID uint) (*models.CardQuality, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if _, err := s.actionRepo.GetByID(actionID); err != nil {
+		return nil, ErrActionNotFound
+	}
+
+	if err := s.feedbackRepo.DeleteVote(userID, actionID); err != nil {
+		return nil, err
+	}
+	return s.quality(actionID)
+}
+
+// GetCollectionQuality возвращает отчет о качестве карточек коллекции для ее владельца,
+// начиная с карточек с худшей оценкой
+func (s *cardFeedbackService) GetCollectionQuality(collectionID, userID uint) ([]*CardQualityReport, error) {
+	collection, err := s.collectionService.GetByID(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if collection.UserID != userID {
+		return nil, ErrNotCollectionOwner
+	}
+
+	ids := make([]uint, 0, len(collection.Actions))
+	for _, action := range collection.Actions {
+		ids = append(ids, action.ID)
+	}
+	quality, err := s.feedbackRepo.GetQuality(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	report := make([]*CardQualityReport, 0, len(collection.Actions))
+	for _, action := range collection.Actions {
+		report = append(report, &CardQualityReport{Action: action, Quality: quality[action.ID]})
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].Quality.Score() < report[j].Quality.Score()
+	})
+	return report, nil
+}
+
+// quality возвращает обратную связь по одной карточке
+func (s *cardFeedbackService) quality(actionID uint) (*models.CardQuality, error) {
+	quality, err := s.feedbackRepo.GetQuality([]uint{actionID})
+	if err != nil {
+		return nil, err
+	}
+	return quality[actionID], nil
+}
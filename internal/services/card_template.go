@@ -0,0 +1,215 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Синтаксис шаблонов в тексте карточки:
+//
+//	{player}          - игрок, чей сейчас ход
+//	{other}           - случайный другой игрок (один и тот же в пределах карточки)
+//	{number:MIN-MAX}  - случайное целое число от MIN до MAX включительно
+//	{{ и }}           - фигурные скобки как обычный текст
+const (
+	placeholderPlayer = "player"
+	placeholderOther  = "other"
+	placeholderNumber = "number"
+
+	maxTemplateNumber = 1000000
+	MaxRenderPlayers  = 20
+)
+
+var (
+	ErrInvalidActionTemplate = errors.New("invalid action template")
+	ErrInvalidPlayers        = errors.New("invalid player list")
+)
+
+// templateToken представляет фрагмент разобранного шаблона: либо текст, либо подстановку
+type templateToken struct {
+	literal     string
+	placeholder string
+	min, max    int
+}
+
+// CardRenderContext содержит данные для подстановки в шаблон карточки
+type CardRenderContext struct {
+	Players       []string
+	CurrentPlayer string
+	Rand          *rand.Rand
+}
+
+// parseCardTemplate разбирает текст карточки на фрагменты
+func parseCardTemplate(text string) ([]templateToken, error) {
+	var tokens []templateToken
+	var literal strings.Builder
+
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case ch == '{' && i+1 < len(text) && text[i+1] == '{':
+			literal.WriteByte('{')
+			i++
+		case ch == '}' && i+1 < len(text) && text[i+1] == '}':
+			literal.WriteByte('}')
+			i++
+		case ch == '}':
+			return nil, fmt.Errorf("unexpected '}' at position %d", i)
+		case ch == '{':
+			end := strings.IndexByte(text[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '{' at position %d", i)
+			}
+			token, err := parsePlaceholder(text[i+1 : i+end])
+			if err != nil {
+				return nil, err
+			}
+			if literal.Len() > 0 {
+				tokens = append(tokens, templateToken{literal: literal.String()})
+				literal.Reset()
+			}
+			tokens = append(tokens, token)
+			i += end
+		default:
+			literal.WriteByte(ch)
+		}
+	}
+
+	if literal.Len() > 0 {
+		tokens = append(tokens, templateToken{literal: literal.String()})
+	}
+	return tokens, nil
+}
+
+// parsePlaceholder разбирает содержимое фигурных скобок
+func parsePlaceholder(body string) (templateToken, error) {
+	name, args, hasArgs := strings.Cut(strings.TrimSpace(body), ":")
+
+	switch name {
+	case placeholderPlayer, placeholderOther:
+		if hasArgs {
+			return templateToken{}, fmt.Errorf("placeholder {%s} does not take arguments", name)
+		}
+		return templateToken{placeholder: name}, nil
+	case placeholderNumber:
+		minStr, maxStr, ok := strings.Cut(args, "-")
+		if !ok {
+			return templateToken{}, fmt.Errorf("placeholder {number} must look like {number:MIN-MAX}")
+		}
+		lo, err1 := strconv.Atoi(strings.TrimSpace(minStr))
+		hi, err2 := strconv.Atoi(strings.TrimSpace(maxStr))
+		if err1 != nil || err2 != nil || lo < 0 || hi > maxTemplateNumber || lo > hi {
+			return templateToken{}, fmt.Errorf("invalid range in {number:%s}", args)
+		}
+		return templateToken{placeholder: name, min: lo, max: hi}, nil
+	default:
+		return templateToken{}, fmt.Errorf("unknown placeholder {%s}", body)
+	}
+}
+
+// ValidateCardTemplate проверяет синтаксис шаблона в тексте карточки
+func ValidateCardTemplate(text string) error {
+	if _, err := parseCardTemplate(text); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidActionTemplate, err)
+	}
+	return nil
+}
+
+// IsCardTemplate сообщает, содержит ли текст карточки подстановки
+func IsCardTemplate(text string) bool {
+	tokens, err := parseCardTemplate(text)
+	if err != nil {
+		return false
+	}
+	for _, token := range tokens {
+		if token.placeholder != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// needsOtherPlayer сообщает, есть ли в шаблоне подстановка {other}, для которой
+// нужен хотя бы второй игрок
+func needsOtherPlayer(text string) bool {
+	tokens, err := parseCardTemplate(text)
+	if err != nil {
+		return false
+	}
+	for _, token := range tokens {
+		if token.placeholder == placeholderOther {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderCardTemplate подставляет в шаблон имена игроков и случайные числа
+func RenderCardTemplate(text string, ctx CardRenderContext) (string, error) {
+	tokens, err := parseCardTemplate(text)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidActionTemplate, err)
+	}
+
+	var result strings.Builder
+	other := ""
+	for _, token := range tokens {
+		switch token.placeholder {
+		case "":
+			result.WriteString(token.literal)
+		case placeholderPlayer:
+			if ctx.CurrentPlayer == "" {
+				return "", fmt.Errorf("%w: current player is required", ErrInvalidPlayers)
+			}
+			result.WriteString(ctx.CurrentPlayer)
+		case placeholderOther:
+			if other == "" {
+				other, err = pickOtherPlayer(ctx)
+				if err != nil {
+					return "", err
+				}
+			}
+			result.WriteString(other)
+		case placeholderNumber:
+			result.WriteString(strconv.Itoa(token.min + ctx.Rand.Intn(token.max-token.min+1)))
+		}
+	}
+
+	return result.String(), nil
+}
+
+// pickOtherPlayer выбирает случайного игрока, отличного от текущего
+func pickOtherPlayer(ctx CardRenderContext) (string, error) {
+	candidates := make([]string, 0, len(ctx.Players))
+	for _, player := range ctx.Players {
+		if player != ctx.CurrentPlayer {
+			candidates = append(candidates, player)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("%w: {other} requires at least two players", ErrInvalidPlayers)
+	}
+	return candidates[ctx.Rand.Intn(len(candidates))], nil
+}
+
+// validatePlayers проверяет список имен игроков
+func validatePlayers(players []string) error {
+	if len(players) == 0 || len(players) > MaxRenderPlayers {
+		return fmt.Errorf("%w: expected 1 to %d players", ErrInvalidPlayers, MaxRenderPlayers)
+	}
+
+	seen := make(map[string]bool, len(players))
+	for _, player := range players {
+		if strings.TrimSpace(player) == "" {
+			return fmt.Errorf("%w: player name must not be empty", ErrInvalidPlayers)
+		}
+		if seen[player] {
+			return fmt.Errorf("%w: duplicate player %s", ErrInvalidPlayers, player)
+		}
+		seen[player] = true
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+)
+
+func TestRenderCardTemplate(t *testing.T) {
+	players := []string{"Аня", "Борис", "Вика"}
+
+	tests := []struct {
+		name    string
+		text    string
+		ctx     CardRenderContext
+		want    string
+		wantErr error
+	}{
+		{
+			name: "plain text",
+			text: "Расскажи секрет",
+			ctx:  CardRenderContext{Players: players, CurrentPlayer: "Аня"},
+			want: "Расскажи секрет",
+		},
+		{
+			name: "current player",
+			text: "{player}, спой песню",
+			ctx:  CardRenderContext{Players: players, CurrentPlayer: "Борис"},
+			want: "Борис, спой песню",
+		},
+		{
+			name: "spaces inside braces",
+			text: "{ player } ходит",
+			ctx:  CardRenderContext{Players: players, CurrentPlayer: "Вика"},
+			want: "Вика ходит",
+		},
+		{
+			name: "escaped braces",
+			text: "{{player}} - это {player}",
+			ctx:  CardRenderContext{Players: players, CurrentPlayer: "Аня"},
+			want: "{player} - это Аня",
+		},
+		{
+			name: "other with two players",
+			text: "{player} обнимает {other}",
+			ctx:  CardRenderContext{Players: []string{"Аня", "Борис"}, CurrentPlayer: "Аня"},
+			want: "Аня обнимает Борис",
+		},
+		{
+			name: "fixed number range",
+			text: "Присядь {number:7-7} раз",
+			ctx:  CardRenderContext{Players: players, CurrentPlayer: "Аня"},
+			want: "Присядь 7 раз",
+		},
+		{
+			name:    "player without current player",
+			text:    "{player}, ходи",
+			ctx:     CardRenderContext{Players: players},
+			wantErr: ErrInvalidPlayers,
+		},
+		{
+			name:    "other with single player",
+			text:    "Поцелуй {other}",
+			ctx:     CardRenderContext{Players: []string{"Аня"}, CurrentPlayer: "Аня"},
+			wantErr: ErrInvalidPlayers,
+		},
+		{
+			name:    "unknown placeholder",
+			text:    "{name} ходит",
+			ctx:     CardRenderContext{Players: players, CurrentPlayer: "Аня"},
+			wantErr: ErrInvalidActionTemplate,
+		},
+		{
+			name:    "unclosed brace",
+			text:    "{player ходит",
+			ctx:     CardRenderContext{Players: players, CurrentPlayer: "Аня"},
+			wantErr: ErrInvalidActionTemplate,
+		},
+		{
+			name:    "unexpected closing brace",
+			text:    "player} ходит",
+			ctx:     CardRenderContext{Players: players, CurrentPlayer: "Аня"},
+			wantErr: ErrInvalidActionTemplate,
+		},
+		{
+			name:    "reversed number range",
+			text:    "{number:10-1}",
+			ctx:     CardRenderContext{Players: players, CurrentPlayer: "Аня"},
+			wantErr: ErrInvalidActionTemplate,
+		},
+		{
+			name:    "number range above limit",
+			text:    "{number:1-1000001}",
+			ctx:     CardRenderContext{Players: players, CurrentPlayer: "Аня"},
+			wantErr: ErrInvalidActionTemplate,
+		},
+		{
+			name:    "number with arguments missing",
+			text:    "{number}",
+			ctx:     CardRenderContext{Players: players, CurrentPlayer: "Аня"},
+			wantErr: ErrInvalidActionTemplate,
+		},
+		{
+			name:    "player with arguments",
+			text:    "{player:1}",
+			ctx:     CardRenderContext{Players: players, CurrentPlayer: "Аня"},
+			wantErr: ErrInvalidActionTemplate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.ctx.Rand = rand.New(rand.NewSource(1))
+			got, err := RenderCardTemplate(tt.text, tt.ctx)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RenderCardTemplate(%q) error = %v, want %v", tt.text, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderCardTemplate(%q) unexpected error: %v", tt.text, err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderCardTemplate(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderCardTemplateOtherIsStableWithinCard(t *testing.T) {
+	players := []string{"Аня", "Борис", "Вика", "Гоша"}
+	for seed := int64(1); seed <= 50; seed++ {
+		ctx := CardRenderContext{Players: players, CurrentPlayer: "Аня", Rand: rand.New(rand.NewSource(seed))}
+		got, err := RenderCardTemplate("{other}|{other}", ctx)
+		if err != nil {
+			t.Fatalf("seed %d: unexpected error: %v", seed, err)
+		}
+		first, second, _ := strings.Cut(got, "|")
+		if first != second {
+			t.Fatalf("seed %d: {other} rendered as %q and %q within one card", seed, first, second)
+		}
+		if first == "Аня" {
+			t.Fatalf("seed %d: {other} picked the current player", seed)
+		}
+	}
+}
+
+func TestRenderCardTemplateNumberRange(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	for i := 0; i < 200; i++ {
+		got, err := RenderCardTemplate("{number:3-5}", CardRenderContext{Rand: rng})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		n, err := strconv.Atoi(got)
+		if err != nil || n < 3 || n > 5 {
+			t.Fatalf("{number:3-5} rendered as %q", got)
+		}
+	}
+}
+
+func TestRenderCardTemplateSameSeedSameResult(t *testing.T) {
+	players := []string{"Аня", "Борис", "Вика"}
+	text := "{player} и {other}: {number:1-100} раз"
+	render := func() string {
+		ctx := CardRenderContext{Players: players, CurrentPlayer: "Борис", Rand: rand.New(rand.NewSource(7))}
+		got, err := RenderCardTemplate(text, ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return got
+	}
+	if first, second := render(), render(); first != second {
+		t.Errorf("same seed rendered %q and %q", first, second)
+	}
+}
+
+func TestRenderActionExtraIsDeterministic(t *testing.T) {
+	action := &models.Action{
+		Text: "{player}: {number:1-1000}",
+		Extra: models.ActionExtra{
+			"a": "{number:1-1000}",
+			"b": "{other}",
+			"c": "{number:1-1000}",
+			"d": "{other} и {number:1-1000}",
+		},
+	}
+	players := []string{"Аня", "Борис", "Вика", "Гоша"}
+	render := func() *RenderedAction {
+		rendered, err := renderAction(action, CardRenderContext{
+			Players:       players,
+			CurrentPlayer: "Аня",
+			Rand:          rand.New(rand.NewSource(3)),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return rendered
+	}
+
+	want := render()
+	// Порядок обхода map меняется от запуска к запуску, поэтому повторяем несколько раз
+	for i := 0; i < 20; i++ {
+		got := render()
+		if got.Text != want.Text {
+			t.Fatalf("text rendered as %q, then %q", want.Text, got.Text)
+		}
+		for name, value := range want.Extra {
+			if got.Extra[name] != value {
+				t.Fatalf("extra %s rendered as %q, then %q", name, value, got.Extra[name])
+			}
+		}
+	}
+}
+
+func TestRenderActionsSkipsOtherForSinglePlayer(t *testing.T) {
+	collections := &fakeCollectionRepo{collections: map[uint]*models.Collection{1: {ID: 1}}}
+	actions := &fakeActionRepo{actions: map[uint][]*models.Action{1: {
+		{ID: 1, CollectionID: 1, Text: "{player}, расскажи секрет"},
+		{ID: 2, CollectionID: 1, Text: "Обними {other}"},
+		{ID: 3, CollectionID: 1, Text: "Спой песню", Extra: models.ActionExtra{"hint": "{other} выбирает песню"}},
+	}}}
+	service := &collectionService{collectionRepo: collections, actionRepo: actions}
+
+	tests := []struct {
+		name    string
+		players []string
+		wantIDs []uint
+	}{
+		{name: "single player", players: []string{"Аня"}, wantIDs: []uint{1}},
+		{name: "two players", players: []string{"Аня", "Борис"}, wantIDs: []uint{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := service.RenderActions(1, tt.players, "", 1)
+			if err != nil {
+				t.Fatalf("RenderActions() unexpected error: %v", err)
+			}
+			ids := make([]uint, 0, len(rendered))
+			for _, item := range rendered {
+				ids = append(ids, item.Action.ID)
+			}
+			if !sameIDs(ids, tt.wantIDs) {
+				t.Errorf("rendered cards %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"math/rand"
 	"time"
 
 	"github.com/KoLili12/bulb-server/internal/models"
@@ -9,27 +11,96 @@ import (
 )
 
 var (
-	ErrCollectionNotFound = errors.New("collection not found")
-	ErrInvalidUserID      = errors.New("invalid user ID")
-	ErrNotCollectionOwner = errors.New("user is not the owner of this collection")
-	ErrInvalidActionType  = errors.New("invalid action type")
+	ErrCollectionNotFound    = errors.New("collection not found")
+	ErrInvalidUserID         = errors.New("invalid user ID")
+	ErrNotCollectionOwner    = errors.New("user is not the owner of this collection")
+	ErrInvalidActionType     = errors.New("invalid action type")
+	ErrInvalidActionData     = models.ErrInvalidActionData
+	ErrInvalidActionBatch    = errors.New("invalid action batch")
+	ErrInvalidTrendingWindow = errors.New("invalid trending window")
+	ErrInvalidCollectionSort = errors.New("invalid collection sort")
+	ErrInvalidAgeRating      = errors.New("invalid age rating, expected 0, 12, 16 or 18")
 )
 
+// MaxActionBatchSize ограничивает количество операций в одном пакетном запросе
+const MaxActionBatchSize = 500
+
+// ActionOperationType представляет тип операции над карточкой в пакетном запросе
+type ActionOperationType string
+
+const (
+	ActionOperationCreate ActionOperationType = "create"
+	ActionOperationUpdate ActionOperationType = "update"
+	ActionOperationDelete ActionOperationType = "delete"
+)
+
+// ActionOperation описывает одну операцию пакетного изменения карточек.
+// Для create используется Action, для update - ActionID и непустые поля Action,
+// для delete - только ActionID.
+type ActionOperation struct {
+	Op       ActionOperationType
+	ActionID uint
+	Action   *models.Action
+	Metadata ActionMetadataPatch
+}
+
+// ActionMetadataPatch содержит явно заданные метаданные карточки для операции update.
+// nil означает, что значение не меняется (0 - допустимое значение).
+type ActionMetadataPatch struct {
+	Difficulty   *int
+	Spiciness    *int
+	TimerSeconds *int
+	Points       *int
+}
+
+// RenderedAction содержит карточку с подставленными значениями шаблона
+type RenderedAction struct {
+	Action *models.Action
+	Player string
+	Text   string
+	Extra  models.ActionExtra
+}
+
+// CollectionStats содержит агрегированную статистику по карточкам коллекции
+type CollectionStats struct {
+	TotalActions        int
+	TruthCount          int
+	DareCount           int
+	TypeCounts          map[models.ActionType]int
+	DifficultyCounts    map[int]int
+	SpicinessCounts     map[int]int
+	TimedActions        int
+	AverageTimerSeconds float64
+	TotalPoints         int
+}
+
+// ActionOperationResult содержит результат выполнения (или валидации) одной операции
+type ActionOperationResult struct {
+	Index    int
+	Op       ActionOperationType
+	ActionID uint
+	Error    string
+}
+
 // CollectionService определяет методы сервиса коллекций
 type CollectionService interface {
 	Create(collection *models.Collection) error
 	CreateWithActions(collection *models.Collection, actions []*models.Action) error
 	GetByID(id uint) (*models.Collection, error)
 	GetByUserID(userID uint) ([]*models.Collection, error)
-	GetTrending(limit int) ([]*models.Collection, error)
+	GetTrending(window models.TrendingWindow, limit int, visibility models.CollectionVisibility) ([]*models.Collection, error)
 	Update(collection *models.Collection, userID uint) error
 	Delete(id uint, userID uint) error
-	List(page int, pageSize int) ([]*models.Collection, int64, error)
+	List(page int, pageSize int, sort models.CollectionSort, visibility models.CollectionVisibility) ([]*models.Collection, int64, error)
 	IncrementPlayCount(id uint) error
 	AddAction(collectionID uint, action *models.Action) error
 	GetActions(collectionID uint) ([]*models.Action, error)
+	FilterActions(collectionID uint, filter models.ActionFilter) ([]*models.Action, error)
 	RemoveAction(actionID uint, userID uint) error
 	GetActionCounts(collectionID uint) (truthCount int, dareCount int, total int, err error)
+	GetStats(collectionID uint) (*CollectionStats, error)
+	ApplyActionBatch(collectionID uint, userID uint, ops []ActionOperation) ([]ActionOperationResult, error)
+	RenderActions(collectionID uint, players []string, currentPlayer string, seed int64) ([]*RenderedAction, error)
 }
 
 // collectionService реализует интерфейс CollectionService
@@ -37,6 +108,7 @@ type collectionService struct {
 	collectionRepo repository.CollectionRepository
 	actionRepo     repository.ActionRepository
 	userRepo       repository.UserRepository
+	content        ContentPolicy
 }
 
 // NewCollectionService создает новый экземпляр сервиса коллекций
@@ -44,11 +116,13 @@ func NewCollectionService(
 	collectionRepo repository.CollectionRepository,
 	actionRepo repository.ActionRepository,
 	userRepo repository.UserRepository,
+	content ContentPolicy,
 ) CollectionService {
 	return &collectionService{
 		collectionRepo: collectionRepo,
 		actionRepo:     actionRepo,
 		userRepo:       userRepo,
+		content:        content,
 	}
 }
 
@@ -59,6 +133,9 @@ func (s *collectionService) Create(collection *models.Collection) error {
 	if err != nil {
 		return ErrInvalidUserID
 	}
+	if !models.IsValidAgeRating(collection.DeclaredAgeRating) {
+		return ErrInvalidAgeRating
+	}
 
 	// Устанавливаем время создания и обновления
 	now := time.Now()
@@ -66,8 +143,19 @@ func (s *collectionService) Create(collection *models.Collection) error {
 	collection.UpdatedAt = now
 	collection.PlayCount = 0
 
+	// Проверяем название и описание фильтром контента
+	verdict, err := s.content.Review(collection, nil)
+	if err != nil {
+		return err
+	}
+	collection.ResolveAgeRating(models.MinSpiciness)
+
 	// Сохраняем коллекцию
-	return s.collectionRepo.Create(collection)
+	if err := s.collectionRepo.Create(collection); err != nil {
+		return err
+	}
+	s.content.Flag(collection, nil, verdict)
+	return nil
 }
 
 // CreateWithActions создает коллекцию с действиями в одной транзакции
@@ -77,6 +165,9 @@ func (s *collectionService) CreateWithActions(collection *models.Collection, act
 	if err != nil {
 		return ErrInvalidUserID
 	}
+	if !models.IsValidAgeRating(collection.DeclaredAgeRating) {
+		return ErrInvalidAgeRating
+	}
 
 	// Устанавливаем время создания и обновления
 	now := time.Now()
@@ -84,6 +175,13 @@ func (s *collectionService) CreateWithActions(collection *models.Collection, act
 	collection.UpdatedAt = now
 	collection.PlayCount = 0
 
+	// Проверяем коллекцию и карточки фильтром контента до сохранения
+	verdict, err := s.content.Review(collection, actions)
+	if err != nil {
+		return err
+	}
+	collection.ResolveAgeRating(maxSpiciness(actions))
+
 	// Сохраняем коллекцию
 	if err := s.collectionRepo.Create(collection); err != nil {
 		return err
@@ -97,18 +195,22 @@ func (s *collectionService) CreateWithActions(collection *models.Collection, act
 		if action.Order == 0 {
 			action.Order = i + 1
 		}
+		applyActionDefaults(action)
 
-		// Валидируем тип действия
-		if action.Type != models.ActionTypeTruth && action.Type != models.ActionTypeDare {
-			return ErrInvalidActionType
+		// Валидируем действие по реестру типов
+		if err := validateAction(action); err != nil {
+			return err
 		}
 	}
 
 	// Сохраняем все действия одним запросом
 	if len(actions) > 0 {
-		return s.actionRepo.BatchCreate(actions)
+		if err := s.actionRepo.BatchCreate(actions); err != nil {
+			return err
+		}
 	}
 
+	s.content.Flag(collection, actions, verdict)
 	return nil
 }
 
@@ -120,7 +222,7 @@ func (s *collectionService) GetByID(id uint) (*models.Collection, error) {
 	}
 
 	// Получаем действия для коллекции
-	actions, err := s.actionRepo.GetByCollectionID(id)
+	actions, err := s.collectionActions(collection)
 	if err != nil {
 		return nil, err
 	}
@@ -140,12 +242,18 @@ func (s *collectionService) GetByUserID(userID uint) ([]*models.Collection, erro
 	return s.collectionRepo.GetByUserID(userID)
 }
 
-// GetTrending возвращает список популярных коллекций
-func (s *collectionService) GetTrending(limit int) ([]*models.Collection, error) {
+// GetTrending возвращает список популярных коллекций за период
+func (s *collectionService) GetTrending(window models.TrendingWindow, limit int, visibility models.CollectionVisibility) ([]*models.Collection, error) {
 	if limit <= 0 {
 		limit = 10
 	}
-	return s.collectionRepo.GetTrending(limit)
+	if window == "" {
+		window = models.TrendingWindowWeek
+	}
+	if !window.IsValid() {
+		return nil, ErrInvalidTrendingWindow
+	}
+	return s.collectionRepo.GetTrending(window, limit, visibility)
 }
 
 // Update обновляет данные коллекции
@@ -161,16 +269,34 @@ func (s *collectionService) Update(collection *models.Collection, userID uint) e
 		return ErrNotCollectionOwner
 	}
 
+	if !models.IsValidAgeRating(collection.DeclaredAgeRating) {
+		return ErrInvalidAgeRating
+	}
+
 	// Обновляем только разрешенные поля
 	existingCollection.Name = collection.Name
 	existingCollection.Description = collection.Description
 	if collection.ImageURL != "" {
 		existingCollection.ImageURL = collection.ImageURL
 	}
+	existingCollection.DeclaredAgeRating = collection.DeclaredAgeRating
 	existingCollection.UpdatedAt = time.Now()
 
+	// Проверяем новое название и описание фильтром контента
+	verdict, err := s.content.Review(existingCollection, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.resolveAgeRating(existingCollection); err != nil {
+		return err
+	}
+
 	// Сохраняем обновленную коллекцию
-	return s.collectionRepo.Update(existingCollection)
+	if err := s.collectionRepo.Update(existingCollection); err != nil {
+		return err
+	}
+	s.content.Flag(existingCollection, nil, verdict)
+	return nil
 }
 
 // Delete удаляет коллекцию
@@ -191,7 +317,10 @@ func (s *collectionService) Delete(id uint, userID uint) error {
 }
 
 // List возвращает список коллекций с пагинацией
-func (s *collectionService) List(page int, pageSize int) ([]*models.Collection, int64, error) {
+func (s *collectionService) List(page int, pageSize int, sort models.CollectionSort, visibility models.CollectionVisibility) ([]*models.Collection, int64, error) {
+	if !sort.IsValid() {
+		return nil, 0, ErrInvalidCollectionSort
+	}
 	if page < 1 {
 		page = 1
 	}
@@ -200,7 +329,7 @@ func (s *collectionService) List(page int, pageSize int) ([]*models.Collection,
 	}
 
 	offset := (page - 1) * pageSize
-	return s.collectionRepo.List(offset, pageSize)
+	return s.collectionRepo.List(offset, pageSize, sort, visibility)
 }
 
 // IncrementPlayCount увеличивает счетчик запусков коллекции
@@ -217,14 +346,27 @@ func (s *collectionService) IncrementPlayCount(id uint) error {
 // AddAction добавляет новое действие в коллекцию
 func (s *collectionService) AddAction(collectionID uint, action *models.Action) error {
 	// Проверяем существование коллекции
-	_, err := s.collectionRepo.GetByID(collectionID)
+	collection, err := s.collectionRepo.GetByID(collectionID)
 	if err != nil {
 		return ErrCollectionNotFound
 	}
 
-	// Валидируем тип действия
-	if action.Type != models.ActionTypeTruth && action.Type != models.ActionTypeDare {
-		return ErrInvalidActionType
+	// В виртуальную коллекцию нельзя добавлять карточки
+	if collection.IsVirtual {
+		return ErrVirtualCollection
+	}
+
+	// Валидируем действие по реестру типов
+	applyActionDefaults(action)
+	if err := validateAction(action); err != nil {
+		return err
+	}
+
+	// Проверяем текст карточки фильтром контента
+	actions := []*models.Action{action}
+	verdict, err := s.content.Review(nil, actions)
+	if err != nil {
+		return err
 	}
 
 	// Устанавливаем ID коллекции для действия
@@ -233,11 +375,11 @@ func (s *collectionService) AddAction(collectionID uint, action *models.Action)
 	// Устанавливаем порядок действия (если не указан)
 	if action.Order == 0 {
 		// Получаем существующие действия
-		actions, err := s.actionRepo.GetByCollectionID(collectionID)
+		existing, err := s.actionRepo.GetByCollectionID(collectionID)
 		if err != nil {
 			return err
 		}
-		action.Order = len(actions) + 1
+		action.Order = len(existing) + 1
 	}
 
 	// Устанавливаем время создания и обновления
@@ -246,18 +388,123 @@ func (s *collectionService) AddAction(collectionID uint, action *models.Action)
 	action.UpdatedAt = now
 
 	// Сохраняем действие
-	return s.actionRepo.Create(action)
+	if err := s.actionRepo.Create(action); err != nil {
+		return err
+	}
+	s.content.Flag(collection, actions, verdict)
+	return s.actionsChanged(collectionID)
 }
 
 // GetActions возвращает действия коллекции
 func (s *collectionService) GetActions(collectionID uint) ([]*models.Action, error) {
 	// Проверяем существование коллекции
-	_, err := s.collectionRepo.GetByID(collectionID)
+	collection, err := s.collectionRepo.GetByID(collectionID)
 	if err != nil {
 		return nil, ErrCollectionNotFound
 	}
 
-	return s.actionRepo.GetByCollectionID(collectionID)
+	return s.collectionActions(collection)
+}
+
+// collectionActions возвращает карточки коллекции; для виртуальной коллекции
+// карточки собираются из источников микса
+func (s *collectionService) collectionActions(collection *models.Collection) ([]*models.Action, error) {
+	if !collection.IsVirtual {
+		return s.actionRepo.GetByCollectionID(collection.ID)
+	}
+
+	// Рейтинг сохраненного микса уже учитывает все его источники (см. SaveMix)
+	deck, err := buildMixedDeck(s.collectionRepo, s.actionRepo, mixSpecFromCollection(collection), models.CollectionVisibility{
+		MaxAgeRating: models.AgeRating18,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deck.Actions, nil
+}
+
+// resolveAgeRating пересчитывает возрастной рейтинг коллекции по ее текущим карточкам
+func (s *collectionService) resolveAgeRating(collection *models.Collection) error {
+	actions, err := s.collectionActions(collection)
+	if err != nil {
+		return err
+	}
+	collection.ResolveAgeRating(maxSpiciness(actions))
+	return nil
+}
+
+// actionsChanged вызывается после изменения карточек: отмечает коллекцию измененной для ленты
+// подписок, пересчитывает ее рейтинг и рейтинги миксов, которые берут из нее карточки
+func (s *collectionService) actionsChanged(collectionID uint) error {
+	if err := s.collectionRepo.Touch(collectionID); err != nil {
+		return err
+	}
+	collection, err := s.collectionRepo.GetByID(collectionID)
+	if err != nil {
+		return ErrCollectionNotFound
+	}
+	if err := s.resolveAgeRating(collection); err != nil {
+		return err
+	}
+	if err := s.collectionRepo.UpdateAgeRating(collection.ID, collection.AgeRating); err != nil {
+		return err
+	}
+
+	mixIDs, err := s.collectionRepo.GetMixIDsBySource(collectionID)
+	if err != nil {
+		return err
+	}
+	for _, mixID := range mixIDs {
+		mix, err := s.collectionRepo.GetByID(mixID)
+		if err != nil {
+			continue // Микс удален
+		}
+		// Микс с недоступными источниками не собирается; его рейтинг остается прежним
+		if err := s.resolveAgeRating(mix); err != nil {
+			continue
+		}
+		if err := s.collectionRepo.UpdateAgeRating(mix.ID, mix.AgeRating); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxSpiciness возвращает наибольший уровень откровенности среди карточек
+func maxSpiciness(actions []*models.Action) int {
+	result := models.MinSpiciness
+	for _, action := range actions {
+		if action != nil && action.Spiciness > result {
+			result = action.Spiciness
+		}
+	}
+	return result
+}
+
+// FilterActions возвращает действия коллекции, удовлетворяющие фильтру
+func (s *collectionService) FilterActions(collectionID uint, filter models.ActionFilter) ([]*models.Action, error) {
+	// Проверяем существование коллекции
+	collection, err := s.collectionRepo.GetByID(collectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+
+	if !collection.IsVirtual {
+		return s.actionRepo.FindByCollectionID(collectionID, filter)
+	}
+
+	// Карточки виртуальной коллекции фильтруем после сборки микса
+	actions, err := s.collectionActions(collection)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*models.Action, 0, len(actions))
+	for _, action := range actions {
+		if filter.Matches(action) {
+			filtered = append(filtered, action)
+		}
+	}
+	return filtered, nil
 }
 
 // RemoveAction удаляет действие
@@ -280,7 +527,10 @@ func (s *collectionService) RemoveAction(actionID uint, userID uint) error {
 	}
 
 	// Удаляем действие
-	return s.actionRepo.Delete(actionID)
+	if err := s.actionRepo.Delete(actionID); err != nil {
+		return err
+	}
+	return s.actionsChanged(collection.ID)
 }
 
 // GetActionCounts возвращает количество действий по типам
@@ -302,3 +552,333 @@ func (s *collectionService) GetActionCounts(collectionID uint) (truthCount int,
 
 	return truthCount, dareCount, total, nil
 }
+
+// GetStats возвращает статистику по карточкам коллекции: распределение по типам,
+// сложности и откровенности, средний таймер и сумму очков
+func (s *collectionService) GetStats(collectionID uint) (*CollectionStats, error) {
+	// Проверяем существование коллекции
+	collection, err := s.collectionRepo.GetByID(collectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+
+	actions, err := s.collectionActions(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &CollectionStats{
+		TotalActions:     len(actions),
+		TypeCounts:       make(map[models.ActionType]int),
+		DifficultyCounts: make(map[int]int),
+		SpicinessCounts:  make(map[int]int),
+	}
+
+	timerSum := 0
+	for _, action := range actions {
+		stats.TypeCounts[action.Type]++
+		stats.DifficultyCounts[action.Difficulty]++
+		stats.SpicinessCounts[action.Spiciness]++
+		stats.TotalPoints += action.Points
+		if action.TimerSeconds > 0 {
+			stats.TimedActions++
+			timerSum += action.TimerSeconds
+		}
+	}
+	stats.TruthCount = stats.TypeCounts[models.ActionTypeTruth]
+	stats.DareCount = stats.TypeCounts[models.ActionTypeDare]
+	if stats.TimedActions > 0 {
+		stats.AverageTimerSeconds = float64(timerSum) / float64(stats.TimedActions)
+	}
+
+	return stats, nil
+}
+
+// ApplyActionBatch атомарно применяет пакет операций create/update/delete к карточкам коллекции.
+// При ошибках валидации пакет отклоняется целиком: возвращаются результаты с описанием
+// ошибок по каждой операции и ErrInvalidActionBatch.
+func (s *collectionService) ApplyActionBatch(collectionID uint, userID uint, ops []ActionOperation) ([]ActionOperationResult, error) {
+	// Проверяем существование коллекции
+	collection, err := s.collectionRepo.GetByID(collectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+
+	// Проверяем, что пользователь является владельцем коллекции
+	if collection.UserID != userID {
+		return nil, ErrNotCollectionOwner
+	}
+
+	// Карточки виртуальной коллекции задаются источниками микса
+	if collection.IsVirtual {
+		return nil, ErrVirtualCollection
+	}
+
+	if len(ops) == 0 || len(ops) > MaxActionBatchSize {
+		return nil, ErrInvalidActionBatch
+	}
+
+	// Получаем существующие действия коллекции
+	existing, err := s.actionRepo.GetByCollectionID(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	existingByID := make(map[uint]*models.Action, len(existing))
+	for _, action := range existing {
+		existingByID[action.ID] = action
+	}
+
+	// Валидируем все операции и готовим изменения до начала записи
+	now := time.Now()
+	nextOrder := len(existing) + 1
+	results := make([]ActionOperationResult, len(ops))
+	prepared := make([]*models.Action, len(ops))
+	touched := make(map[uint]bool)
+	hasErrors := false
+	for i, op := range ops {
+		results[i] = ActionOperationResult{Index: i, Op: op.Op, ActionID: op.ActionID}
+		action, err := prepareActionOperation(op, existingByID, touched)
+		if err != nil {
+			results[i].Error = err.Error()
+			hasErrors = true
+			continue
+		}
+		prepared[i] = action
+	}
+	if hasErrors {
+		return results, ErrInvalidActionBatch
+	}
+
+	// Проверяем создаваемые и измененные карточки фильтром контента
+	verdict, err := s.content.Review(nil, prepared)
+	if errors.Is(err, ErrContentRejected) {
+		for i := range verdict.ActionMatches {
+			results[i].Error = ErrContentRejected.Error()
+		}
+		return results, ErrInvalidActionBatch
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var creates, updates []*models.Action
+	var deleteIDs []uint
+	createIndexes := make([]int, 0, len(ops))
+
+	for i, op := range ops {
+		switch op.Op {
+		case ActionOperationCreate:
+			action := prepared[i]
+			action.CollectionID = collectionID
+			action.CreatedAt = now
+			action.UpdatedAt = now
+			if action.Order == 0 {
+				action.Order = nextOrder
+				nextOrder++
+			}
+			creates = append(creates, action)
+			createIndexes = append(createIndexes, i)
+		case ActionOperationUpdate:
+			action := prepared[i]
+			action.UpdatedAt = now
+			updates = append(updates, action)
+		case ActionOperationDelete:
+			deleteIDs = append(deleteIDs, op.ActionID)
+		}
+	}
+
+	// Применяем все изменения в одной транзакции
+	if err := s.actionRepo.ApplyBatch(creates, updates, deleteIDs); err != nil {
+		return nil, err
+	}
+
+	// Проставляем ID созданных действий
+	for k, i := range createIndexes {
+		results[i].ActionID = creates[k].ID
+	}
+
+	s.content.Flag(collection, prepared, verdict)
+	if err := s.actionsChanged(collectionID); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// prepareActionOperation проверяет одну операцию пакетного запроса и возвращает
+// карточку, которую нужно сохранить (для delete - nil)
+func prepareActionOperation(op ActionOperation, existing map[uint]*models.Action, touched map[uint]bool) (*models.Action, error) {
+	switch op.Op {
+	case ActionOperationCreate:
+		if op.Action == nil {
+			return nil, errors.New("action data is required")
+		}
+		applyActionDefaults(op.Action)
+		if err := validateAction(op.Action); err != nil {
+			return nil, err
+		}
+		return op.Action, nil
+	case ActionOperationUpdate, ActionOperationDelete:
+		if op.ActionID == 0 {
+			return nil, errors.New("id is required")
+		}
+		current, ok := existing[op.ActionID]
+		if !ok {
+			return nil, fmt.Errorf("action with id %d not found in collection", op.ActionID)
+		}
+		if touched[op.ActionID] {
+			return nil, fmt.Errorf("action with id %d is used in several operations", op.ActionID)
+		}
+		touched[op.ActionID] = true
+
+		if op.Op == ActionOperationDelete {
+			return nil, nil
+		}
+		if op.Action == nil {
+			return nil, errors.New("nothing to update")
+		}
+
+		// Применяем изменения к копии, чтобы проверить итоговое состояние карточки
+		updated := *current
+		if op.Action.Text != "" {
+			updated.Text = op.Action.Text
+		}
+		if op.Action.Type != "" {
+			updated.Type = op.Action.Type
+		}
+		if op.Action.Extra != nil {
+			updated.Extra = op.Action.Extra
+		}
+		if op.Action.Order != 0 {
+			updated.Order = op.Action.Order
+		}
+		if op.Metadata.Difficulty != nil {
+			updated.Difficulty = *op.Metadata.Difficulty
+		}
+		if op.Metadata.Spiciness != nil {
+			updated.Spiciness = *op.Metadata.Spiciness
+		}
+		if op.Metadata.TimerSeconds != nil {
+			updated.TimerSeconds = *op.Metadata.TimerSeconds
+		}
+		if op.Metadata.Points != nil {
+			updated.Points = *op.Metadata.Points
+		}
+		if err := validateAction(&updated); err != nil {
+			return nil, err
+		}
+		return &updated, nil
+	default:
+		return nil, fmt.Errorf("unknown operation: %s", op.Op)
+	}
+}
+
+// applyActionDefaults заполняет незаданные метаданные карточки значениями по умолчанию
+func applyActionDefaults(action *models.Action) {
+	if action.Difficulty == 0 {
+		action.Difficulty = models.MinDifficulty
+	}
+}
+
+// validateAction проверяет карточку по реестру типов и приводит ошибку к ошибкам сервиса
+func validateAction(action *models.Action) error {
+	if err := models.ValidateAction(action); err != nil {
+		if errors.Is(err, models.ErrUnknownActionType) {
+			return fmt.Errorf("%w: %s", ErrInvalidActionType, action.Type)
+		}
+		return err
+	}
+
+	// Текст и дополнительные поля могут содержать подстановки игроков
+	if err := ValidateCardTemplate(action.Text); err != nil {
+		return err
+	}
+	for _, name := range action.Extra.Keys() {
+		if err := ValidateCardTemplate(action.Extra[name]); err != nil {
+			return fmt.Errorf("%w (field %s)", err, name)
+		}
+	}
+	return nil
+}
+
+// RenderActions подставляет имена игроков и случайные значения в шаблоны карточек коллекции.
+// Если текущий игрок не указан, карточки распределяются между игроками по очереди.
+// Карточки с {other} пропускаются, если игрок один, как и в игровой сессии.
+// Одинаковый seed дает одинаковый результат.
+func (s *collectionService) RenderActions(collectionID uint, players []string, currentPlayer string, seed int64) ([]*RenderedAction, error) {
+	if err := validatePlayers(players); err != nil {
+		return nil, err
+	}
+	if currentPlayer != "" {
+		found := false
+		for _, player := range players {
+			if player == currentPlayer {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: current player %s is not in the list", ErrInvalidPlayers, currentPlayer)
+		}
+	}
+
+	actions, err := s.GetActions(collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	rendered := make([]*RenderedAction, 0, len(actions))
+	for i, action := range actions {
+		if len(players) < 2 && actionNeedsOtherPlayer(action) {
+			continue
+		}
+		ctx := CardRenderContext{
+			Players:       players,
+			CurrentPlayer: currentPlayer,
+			Rand:          rng,
+		}
+		if ctx.CurrentPlayer == "" {
+			ctx.CurrentPlayer = players[i%len(players)]
+		}
+
+		item, err := renderAction(action, ctx)
+		if err != nil {
+			return nil, err
+		}
+		rendered = append(rendered, item)
+	}
+
+	return rendered, nil
+}
+
+// renderAction подставляет значения в текст и дополнительные поля одной карточки
+func renderAction(action *models.Action, ctx CardRenderContext) (*RenderedAction, error) {
+	text, err := RenderCardTemplate(action.Text, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var extra models.ActionExtra
+	if len(action.Extra) > 0 {
+		extra = make(models.ActionExtra, len(action.Extra))
+		// Поля обходятся в фиксированном порядке: они берут значения из общего rng,
+		// и при одинаковом seed результат должен совпадать
+		for _, name := range action.Extra.Keys() {
+			extra[name], err = RenderCardTemplate(action.Extra[name], ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &RenderedAction{
+		Action: action,
+		Player: ctx.CurrentPlayer,
+		Text:   text,
+		Extra:  extra,
+	}, nil
+}
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+var (
+	ErrCommentNotFound       = errors.New("comment not found")
+	ErrCommentRemoved        = errors.New("comment has been deleted")
+	ErrInvalidComment        = errors.New("invalid comment")
+	ErrNotCommentAuthor      = errors.New("user is not the author of this comment")
+	ErrCommentRateLimited    = errors.New("too many comments, try again later")
+	ErrTooManyPinnedComments = errors.New("too many pinned comments")
+)
+
+const (
+	// MaxCommentLength ограничивает длину комментария в символах
+	MaxCommentLength = 1000
+	// MaxCommentMentions ограничивает количество упоминаний в одном комментарии
+	MaxCommentMentions = 10
+	// MaxPinnedComments ограничивает количество закрепленных комментариев коллекции
+	MaxPinnedComments = 3
+
+	// Не больше commentRateLimit новых комментариев за commentRateWindow от одного пользователя
+	commentRateLimit  = 5
+	commentRateWindow = time.Minute
+)
+
+// CommentInput содержит текст комментария и упомянутых пользователей
+type CommentInput struct {
+	Text     string
+	Mentions []uint
+}
+
+// CommentService определяет методы сервиса комментариев
+type CommentService interface {
+	Create(userID, collectionID uint, parentID *uint, input CommentInput) (*models.Comment, error)
+	Update(userID, commentID uint, input CommentInput) (*models.Comment, error)
+	Delete(userID, commentID uint) error
+	SetPinned(userID, commentID uint, pinned bool) (*models.Comment, error)
+	// ListThreads и ListReplies не возвращают комментарии пользователей, заблокированных viewerID
+	ListThreads(collectionID uint, viewerID *uint, page, pageSize int) ([]*models.Comment, int64, error)
+	ListReplies(commentID uint, viewerID *uint, page, pageSize int) ([]*models.Comment, int64, error)
+}
+
+// commentService реализует интерфейс CommentService
+type commentService struct {
+	commentRepo         repository.CommentRepository
+	collectionRepo      repository.CollectionRepository
+	userRepo            repository.UserRepository
+	blockRepo           repository.BlockRepository
+	notificationService NotificationService
+	limiter             *rateLimiter
+}
+
+// NewCommentService создает новый экземпляр сервиса комментариев
+func NewCommentService(
+	commentRepo repository.CommentRepository,
+	collectionRepo repository.CollectionRepository,
+	userRepo repository.UserRepository,
+	blockRepo repository.BlockRepository,
+	notificationService NotificationService,
+) CommentService {
+	return &commentService{
+		commentRepo:         commentRepo,
+		collectionRepo:      collectionRepo,
+		userRepo:            userRepo,
+		blockRepo:           blockRepo,
+		notificationService: notificationService,
+		limiter:             newRateLimiter(commentRateLimit, commentRateWindow),
+	}
+}
+
+// Create добавляет комментарий к коллекции или ответ на комментарий (parentID).
+// Блокировка с владельцем коллекции или автором комментария запрещает писать.
+func (s *commentService) Create(userID, collectionID uint, parentID *uint, input CommentInput) (*models.Comment, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	collection, err := s.collectionRepo.GetByID(collectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+	if err := checkNotBlocked(s.blockRepo, userID, collection.UserID); err != nil {
+		return nil, err
+	}
+
+	text, mentions, err := s.prepareInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		CollectionID: collectionID,
+		UserID:       userID,
+		Text:         text,
+		Mentions:     mentions,
+	}
+	var replyTo uint
+	if parentID != nil {
+		parent, err := s.commentRepo.GetByID(*parentID)
+		if err != nil || parent.CollectionID != collectionID {
+			return nil, ErrCommentNotFound
+		}
+		if parent.IsRemoved() {
+			return nil, ErrCommentRemoved
+		}
+		if err := checkNotBlocked(s.blockRepo, userID, parent.UserID); err != nil {
+			return nil, err
+		}
+		rootID := parent.ID
+		if parent.RootID != nil {
+			rootID = *parent.RootID
+		}
+		comment.ParentID = &parent.ID
+		comment.RootID = &rootID
+		replyTo = parent.UserID
+	}
+
+	// Лимит проверяется последним, чтобы невалидные запросы его не расходовали
+	if !s.limiter.Allow(userID) {
+		return nil, ErrCommentRateLimited
+	}
+
+	if err := s.commentRepo.Create(comment); err != nil {
+		return nil, err
+	}
+
+	// Автор коллекции узнает о любом комментарии, автор комментария - об ответах на него
+	s.notificationService.Notify(NotificationEvent{
+		Type:         models.NotificationComment,
+		RecipientID:  collection.UserID,
+		ActorID:      userID,
+		CollectionID: &collection.ID,
+	})
+	if replyTo != 0 && replyTo != collection.UserID {
+		s.notificationService.Notify(NotificationEvent{
+			Type:         models.NotificationReply,
+			RecipientID:  replyTo,
+			ActorID:      userID,
+			CollectionID: &collection.ID,
+			CommentID:    comment.RootID,
+		})
+	}
+	return s.get(comment.ID)
+}
+
+// Update изменяет текст и упоминания комментария; доступно только автору
+func (s *commentService) Update(userID, commentID uint, input CommentInput) (*models.Comment, error) {
+	comment, err := s.get(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, ErrNotCommentAuthor
+	}
+	if comment.IsRemoved() {
+		return nil, ErrCommentRemoved
+	}
+
+	text, mentions, err := s.prepareInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	comment.Text = text
+	comment.Mentions = mentions
+	comment.EditedAt = &now
+	if err := s.commentRepo.Update(comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// Delete удаляет комментарий, сохраняя ответы на него. Удалить комментарий
+// может его автор или владелец коллекции.
+func (s *commentService) Delete(userID, commentID uint) error {
+	comment, err := s.get(commentID)
+	if err != nil {
+		return err
+	}
+	if comment.IsRemoved() {
+		return nil
+	}
+	if comment.UserID != userID {
+		collection, err := s.collectionRepo.GetByID(comment.CollectionID)
+		if err != nil {
+			return ErrCollectionNotFound
+		}
+		if collection.UserID != userID {
+			return ErrNotCommentAuthor
+		}
+	}
+
+	now := time.Now()
+	comment.RemovedAt = &now
+	comment.Text = ""
+	comment.Mentions = nil
+	comment.IsPinned = false
+	comment.PinnedAt = nil
+	return s.commentRepo.Update(comment)
+}
+
+// SetPinned закрепляет или открепляет комментарий верхнего уровня; доступно владельцу коллекции
+func (s *commentService) SetPinned(userID, commentID uint, pinned bool) (*models.Comment, error) {
+	comment, err := s.get(commentID)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := s.collectionRepo.GetByID(comment.CollectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+	if collection.UserID != userID {
+		return nil, ErrNotCollectionOwner
+	}
+	if comment.IsPinned == pinned {
+		return comment, nil
+	}
+
+	if pinned {
+		if comment.IsRemoved() {
+			return nil, ErrCommentRemoved
+		}
+		if comment.ParentID != nil {
+			return nil, ErrInvalidComment
+		}
+		count, err := s.commentRepo.CountPinned(comment.CollectionID)
+		if err != nil {
+			return nil, err
+		}
+		if count >= MaxPinnedComments {
+			return nil, ErrTooManyPinnedComments
+		}
+		now := time.Now()
+		comment.PinnedAt = &now
+	} else {
+		comment.PinnedAt = nil
+	}
+	comment.IsPinned = pinned
+
+	if err := s.commentRepo.Update(comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// ListThreads возвращает комментарии верхнего уровня коллекции с пагинацией
+func (s *commentService) ListThreads(collectionID uint, viewerID *uint, page, pageSize int) ([]*models.Comment, int64, error) {
+	if _, err := s.collectionRepo.GetByID(collectionID); err != nil {
+		return nil, 0, ErrCollectionNotFound
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return s.commentRepo.ListRoots(collectionID, viewerID, (page-1)*pageSize, pageSize)
+}
+
+// ListReplies возвращает ответы в ветке комментария с пагинацией
+func (s *commentService) ListReplies(commentID uint, viewerID *uint, page, pageSize int) ([]*models.Comment, int64, error) {
+	comment, err := s.get(commentID)
+	if err != nil {
+		return nil, 0, err
+	}
+	rootID := comment.ID
+	if comment.RootID != nil {
+		rootID = *comment.RootID
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return s.commentRepo.ListReplies(rootID, viewerID, (page-1)*pageSize, pageSize)
+}
+
+// get возвращает комментарий или ErrCommentNotFound
+func (s *commentService) get(commentID uint) (*models.Comment, error) {
+	comment, err := s.commentRepo.GetByID(commentID)
+	if err != nil {
+		return nil, ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// prepareInput проверяет текст и превращает ID упомянутых пользователей в упоминания
+func (s *commentService) prepareInput(input CommentInput) (string, models.CommentMentions, error) {
+	text := strings.TrimSpace(input.Text)
+	if text == "" || utf8.RuneCountInString(text) > MaxCommentLength {
+		return "", nil, ErrInvalidComment
+	}
+	if len(input.Mentions) > MaxCommentMentions {
+		return "", nil, ErrInvalidComment
+	}
+
+	var mentions models.CommentMentions
+	seen := make(map[uint]bool, len(input.Mentions))
+	for _, id := range input.Mentions {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		user, err := s.userRepo.GetByID(id)
+		if err != nil {
+			return "", nil, ErrUserNotFound
+		}
+		mentions = append(mentions, models.CommentMention{UserID: user.ID, Name: user.Name})
+	}
+	return text, mentions, nil
+}
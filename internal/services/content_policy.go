@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/KoLili12/bulb-server/internal/contentfilter"
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+var ErrContentRejected = errors.New("content contains prohibited words")
+
+// ContentVerdict содержит результат проверки коллекции и карточек фильтром контента
+type ContentVerdict struct {
+	CollectionMatches []contentfilter.Match         // Название и описание коллекции
+	ActionMatches     map[int][]contentfilter.Match // По индексу карточки в проверенном списке
+	fields            []string                      // Поля с нарушениями для сообщения об ошибке
+}
+
+// Empty проверяет, что фильтр ничего не нашел
+func (v *ContentVerdict) Empty() bool {
+	return v == nil || (len(v.CollectionMatches) == 0 && len(v.ActionMatches) == 0)
+}
+
+// ContentPolicy применяет фильтр контента к коллекциям и карточкам согласно режиму фильтра
+type ContentPolicy interface {
+	// Review проверяет коллекцию (может быть nil) и карточки до сохранения.
+	// В режиме reject возвращает ErrContentRejected вместе с результатом проверки,
+	// в режиме adult помечает найденное как 18+.
+	Review(collection *models.Collection, actions []*models.Action) (*ContentVerdict, error)
+	// Flag ставит найденное в очередь модерации в режиме flag.
+	// Вызывается после сохранения, когда у коллекции и карточек уже есть ID.
+	// Жалобы создаются по возможности: изменения уже сохранены, и сбой очереди
+	// модерации не должен превращать успешный запрос в ошибку.
+	Flag(collection *models.Collection, actions []*models.Action, verdict *ContentVerdict)
+}
+
+// contentPolicy реализует интерфейс ContentPolicy
+type contentPolicy struct {
+	filter         contentfilter.Filter
+	mode           contentfilter.Mode
+	moderationRepo repository.ModerationRepository
+}
+
+// NewContentPolicy создает политику фильтрации контента. filter == nil отключает проверку.
+func NewContentPolicy(filter contentfilter.Filter, mode contentfilter.Mode, moderationRepo repository.ModerationRepository) ContentPolicy {
+	return &contentPolicy{
+		filter:         filter,
+		mode:           mode,
+		moderationRepo: moderationRepo,
+	}
+}
+
+// Review проверяет коллекцию и карточки до сохранения
+func (p *contentPolicy) Review(collection *models.Collection, actions []*models.Action) (*ContentVerdict, error) {
+	if p.filter == nil || p.mode == contentfilter.ModeOff {
+		return nil, nil
+	}
+
+	verdict := &ContentVerdict{}
+	if collection != nil {
+		for _, field := range []struct{ name, text string }{
+			{"name", collection.Name},
+			{"description", collection.Description},
+		} {
+			if matches := p.filter.Check(field.text); len(matches) > 0 {
+				verdict.CollectionMatches = append(verdict.CollectionMatches, matches...)
+				verdict.fields = append(verdict.fields, field.name)
+			}
+		}
+	}
+	for i, action := range actions {
+		if action == nil {
+			continue
+		}
+		matches := p.checkAction(action)
+		if len(matches) == 0 {
+			continue
+		}
+		if verdict.ActionMatches == nil {
+			verdict.ActionMatches = make(map[int][]contentfilter.Match)
+		}
+		verdict.ActionMatches[i] = matches
+		if len(actions) == 1 {
+			verdict.fields = append(verdict.fields, "text")
+		} else {
+			verdict.fields = append(verdict.fields, fmt.Sprintf("card %d", i+1))
+		}
+	}
+
+	switch p.mode {
+	case contentfilter.ModeReject:
+		if !verdict.Empty() {
+			return verdict, fmt.Errorf("%w: %s", ErrContentRejected, strings.Join(verdict.fields, ", "))
+		}
+	case contentfilter.ModeAdult:
+		// Пометка коллекции пересчитывается при каждом изменении названия и описания
+		if collection != nil {
+			collection.AutoAdult = len(verdict.CollectionMatches) > 0
+		}
+		for i := range verdict.ActionMatches {
+			actions[i].Spiciness = models.MaxSpiciness
+		}
+	}
+	return verdict, nil
+}
+
+// Flag создает автоматические жалобы на коллекцию и карточки с найденными нарушениями
+// Ошибки репозитория уже записаны в лог и здесь не возвращаются: жалоба на объект,
+// которую не удалось создать, будет создана при следующем сохранении.
+func (p *contentPolicy) Flag(collection *models.Collection, actions []*models.Action, verdict *ContentVerdict) {
+	if p.mode != contentfilter.ModeFlag || verdict.Empty() {
+		return
+	}
+
+	if len(verdict.CollectionMatches) > 0 {
+		_ = p.report(models.ReportTargetCollection, collection.ID, collection.UserID, verdict.CollectionMatches)
+	}
+	for i, action := range actions {
+		matches, ok := verdict.ActionMatches[i]
+		if !ok {
+			continue
+		}
+		_ = p.report(models.ReportTargetAction, action.ID, collection.UserID, matches)
+	}
+}
+
+// checkAction проверяет текст карточки и ее дополнительные поля
+func (p *contentPolicy) checkAction(action *models.Action) []contentfilter.Match {
+	matches := p.filter.Check(action.Text)
+	for _, value := range action.Extra {
+		matches = append(matches, p.filter.Check(value)...)
+	}
+	return matches
+}
+
+// report ставит объект в очередь модерации, если на него еще нет открытой жалобы фильтра
+func (p *contentPolicy) report(targetType models.ReportTargetType, targetID, authorID uint, matches []contentfilter.Match) error {
+	exists, err := p.moderationRepo.HasUnresolvedReport(nil, targetType, targetID)
+	if err != nil || exists {
+		return err
+	}
+
+	words := make([]string, 0, len(matches))
+	for _, match := range matches {
+		words = append(words, match.Word)
+	}
+	return p.moderationRepo.CreateReport(&models.Report{
+		TargetType:   targetType,
+		TargetID:     targetID,
+		TargetUserID: &authorID,
+		Reason:       models.ReportReasonContentFilter,
+		Details:      "matched words: " + strings.Join(words, ", "),
+		Status:       models.ReportOpen,
+	})
+}
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+var (
+	ErrInvalidDeckMix     = errors.New("invalid deck mix")
+	ErrVirtualCollection  = errors.New("operation is not supported for virtual collections")
+	ErrMixSourceNotFound  = errors.New("mix source collection not found")
+	ErrNestedVirtualMixes = errors.New("virtual collections cannot be used as mix sources")
+)
+
+const (
+	MaxMixSources = 10
+	MaxMixCount   = 500
+)
+
+// DeckMixSourceSpec описывает коллекцию-источник и ее вес в миксе
+type DeckMixSourceSpec struct {
+	CollectionID uint
+	Weight       float64
+}
+
+// DeckMixSpec описывает параметры смешивания нескольких коллекций.
+// Если задан TruthRatio, колода собирается только из карточек "правда" и "действие"
+// в указанной пропорции; иначе используются карточки всех типов.
+type DeckMixSpec struct {
+	Sources    []DeckMixSourceSpec
+	Count      int
+	TruthRatio *float64
+	Seed       int64
+}
+
+// MixedDeck содержит результат смешивания и seed, с которым его можно воспроизвести
+type MixedDeck struct {
+	Seed    int64
+	Actions []*models.Action
+}
+
+// DeckService определяет методы сервиса смешанных колод
+type DeckService interface {
+	// Mix собирает колоду; коллекции, которые нельзя показать в запросе (visibility), пропускаются
+	Mix(spec DeckMixSpec, visibility models.CollectionVisibility) (*MixedDeck, error)
+	SaveMix(collection *models.Collection, spec DeckMixSpec, visibility models.CollectionVisibility) error
+}
+
+// deckService реализует интерфейс DeckService
+type deckService struct {
+	collectionRepo      repository.CollectionRepository
+	actionRepo          repository.ActionRepository
+	userRepo            repository.UserRepository
+	blockRepo           repository.BlockRepository
+	notificationService NotificationService
+}
+
+// NewDeckService создает новый экземпляр сервиса смешанных колод
+func NewDeckService(
+	collectionRepo repository.CollectionRepository,
+	actionRepo repository.ActionRepository,
+	userRepo repository.UserRepository,
+	blockRepo repository.BlockRepository,
+	notificationService NotificationService,
+) DeckService {
+	return &deckService{
+		collectionRepo:      collectionRepo,
+		actionRepo:          actionRepo,
+		userRepo:            userRepo,
+		blockRepo:           blockRepo,
+		notificationService: notificationService,
+	}
+}
+
+// Mix собирает перемешанную колоду из нескольких коллекций
+func (s *deckService) Mix(spec DeckMixSpec, visibility models.CollectionVisibility) (*MixedDeck, error) {
+	if spec.Seed == 0 {
+		spec.Seed = newSeed()
+	}
+	// buildMixedDeck пропускает удаленные источники сохраненных миксов,
+	// а коллекция, указанная в запросе, должна существовать
+	for _, source := range spec.Sources {
+		if _, err := s.collectionRepo.GetByID(source.CollectionID); err != nil {
+			return nil, fmt.Errorf("%w: %d", ErrMixSourceNotFound, source.CollectionID)
+		}
+	}
+	return buildMixedDeck(s.collectionRepo, s.actionRepo, spec, visibility)
+}
+
+// SaveMix сохраняет параметры микса как виртуальную коллекцию. Источники, которые
+// нельзя показать в запросе (visibility), считаются ненайденными: сохраненный микс
+// собирается без ограничения рейтинга и иначе выдавал бы их карточки.
+func (s *deckService) SaveMix(collection *models.Collection, spec DeckMixSpec, visibility models.CollectionVisibility) error {
+	// Проверяем существование пользователя
+	_, err := s.userRepo.GetByID(collection.UserID)
+	if err != nil {
+		return ErrInvalidUserID
+	}
+
+	// Сохраняем seed, чтобы виртуальная коллекция всегда выдавала одну и ту же колоду
+	if spec.Seed == 0 {
+		spec.Seed = newSeed()
+	}
+
+	if err := validateMixSpec(&spec); err != nil {
+		return err
+	}
+	if !models.IsValidAgeRating(collection.DeclaredAgeRating) {
+		return ErrInvalidAgeRating
+	}
+
+	// Источники должны быть доступны в запросе; нельзя сохранить себе колоду
+	// из коллекций пользователя, с которым есть блокировка
+	forked := make([]*models.Collection, 0, len(spec.Sources))
+	for _, source := range spec.Sources {
+		sourceCollection, err := s.collectionRepo.GetByID(source.CollectionID)
+		if err != nil || !visibility.Allows(sourceCollection) {
+			return fmt.Errorf("%w: %d", ErrMixSourceNotFound, source.CollectionID)
+		}
+		if sourceCollection.UserID == collection.UserID {
+			continue
+		}
+		if err := checkNotBlocked(s.blockRepo, collection.UserID, sourceCollection.UserID); err != nil {
+			return err
+		}
+		forked = append(forked, sourceCollection)
+	}
+
+	// Проверяем, что микс собирается
+	deck, err := buildMixedDeck(s.collectionRepo, s.actionRepo, spec, visibility)
+	if err != nil {
+		return err
+	}
+
+	collection.ResolveAgeRating(maxSpiciness(deck.Actions))
+
+	now := time.Now()
+	collection.CreatedAt = now
+	collection.UpdatedAt = now
+	collection.PlayCount = 0
+	collection.IsVirtual = true
+	collection.MixCount = spec.Count
+	collection.MixTruthRatio = spec.TruthRatio
+	collection.MixSeed = spec.Seed
+	collection.MixSources = make([]*models.DeckMixSource, 0, len(spec.Sources))
+	for _, source := range spec.Sources {
+		collection.MixSources = append(collection.MixSources, &models.DeckMixSource{
+			SourceCollectionID: source.CollectionID,
+			Weight:             source.Weight,
+		})
+	}
+
+	if err := s.collectionRepo.Create(collection); err != nil {
+		return err
+	}
+
+	// Авторы чужих коллекций узнают, что их коллекции смешали в колоду
+	for _, source := range forked {
+		s.notificationService.Notify(NotificationEvent{
+			Type:         models.NotificationFork,
+			RecipientID:  source.UserID,
+			ActorID:      collection.UserID,
+			CollectionID: &source.ID,
+		})
+	}
+	return nil
+}
+
+// newSeed возвращает случайный seed, который без потерь передается в JSON (не больше 2^53)
+func newSeed() int64 {
+	return time.Now().UnixNano()&(1<<53-1) | 1
+}
+
+// mixSpecFromCollection восстанавливает параметры микса виртуальной коллекции
+func mixSpecFromCollection(collection *models.Collection) DeckMixSpec {
+	spec := DeckMixSpec{
+		Count:      collection.MixCount,
+		TruthRatio: collection.MixTruthRatio,
+		Seed:       collection.MixSeed,
+	}
+	for _, source := range collection.MixSources {
+		spec.Sources = append(spec.Sources, DeckMixSourceSpec{
+			CollectionID: source.SourceCollectionID,
+			Weight:       source.Weight,
+		})
+	}
+	return spec
+}
+
+// validateMixSpec проверяет параметры микса и проставляет вес по умолчанию
+func validateMixSpec(spec *DeckMixSpec) error {
+	if len(spec.Sources) == 0 || len(spec.Sources) > MaxMixSources {
+		return fmt.Errorf("%w: expected 1 to %d collections", ErrInvalidDeckMix, MaxMixSources)
+	}
+	if spec.Count < 1 || spec.Count > MaxMixCount {
+		return fmt.Errorf("%w: count must be between 1 and %d", ErrInvalidDeckMix, MaxMixCount)
+	}
+	if spec.TruthRatio != nil && (*spec.TruthRatio < 0 || *spec.TruthRatio > 1) {
+		return fmt.Errorf("%w: truthRatio must be between 0 and 1", ErrInvalidDeckMix)
+	}
+
+	seen := make(map[uint]bool, len(spec.Sources))
+	for i := range spec.Sources {
+		source := &spec.Sources[i]
+		if seen[source.CollectionID] {
+			return fmt.Errorf("%w: collection %d is listed twice", ErrInvalidDeckMix, source.CollectionID)
+		}
+		seen[source.CollectionID] = true
+
+		if source.Weight == 0 {
+			source.Weight = 1
+		}
+		if source.Weight < 0 || math.IsInf(source.Weight, 0) || math.IsNaN(source.Weight) {
+			return fmt.Errorf("%w: weight of collection %d must be positive", ErrInvalidDeckMix, source.CollectionID)
+		}
+	}
+	return nil
+}
+
+// mixPool содержит еще не выбранные карточки одного источника
+type mixPool struct {
+	weight  float64
+	actions []*models.Action
+}
+
+// buildMixedDeck загружает карточки источников, убирает дубликаты и собирает колоду.
+// Источники, которые visibility не разрешает показать, и удаленные источники
+// пропускаются: сохраненный микс продолжает работать без них.
+// Результат полностью определяется spec (включая Seed) и набором разрешенных источников.
+func buildMixedDeck(
+	collectionRepo repository.CollectionRepository,
+	actionRepo repository.ActionRepository,
+	spec DeckMixSpec,
+	visibility models.CollectionVisibility,
+) (*MixedDeck, error) {
+	if err := validateMixSpec(&spec); err != nil {
+		return nil, err
+	}
+
+	// Разбиваем карточки источников на пулы: общий, "правда" и "действие"
+	seen := make(map[string]bool)
+	var anyPools, truthPools, darePools []*mixPool
+	for _, source := range spec.Sources {
+		collection, err := collectionRepo.GetByID(source.CollectionID)
+		if err != nil {
+			continue
+		}
+		if collection.IsVirtual {
+			return nil, ErrNestedVirtualMixes
+		}
+		if !visibility.Allows(collection) {
+			continue
+		}
+
+		actions, err := actionRepo.GetByCollectionID(source.CollectionID)
+		if err != nil {
+			return nil, err
+		}
+		// Порядок из базы может совпадать у нескольких карточек, поэтому упорядочиваем явно
+		sort.SliceStable(actions, func(i, j int) bool {
+			if actions[i].Order != actions[j].Order {
+				return actions[i].Order < actions[j].Order
+			}
+			return actions[i].ID < actions[j].ID
+		})
+
+		anyPool := &mixPool{weight: source.Weight}
+		truthPool := &mixPool{weight: source.Weight}
+		darePool := &mixPool{weight: source.Weight}
+		for _, action := range actions {
+			key := string(action.Type) + "|" + strings.ToLower(strings.Join(strings.Fields(action.Text), " "))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			anyPool.actions = append(anyPool.actions, action)
+			switch action.Type {
+			case models.ActionTypeTruth:
+				truthPool.actions = append(truthPool.actions, action)
+			case models.ActionTypeDare:
+				darePool.actions = append(darePool.actions, action)
+			}
+		}
+		anyPools = append(anyPools, anyPool)
+		truthPools = append(truthPools, truthPool)
+		darePools = append(darePools, darePool)
+	}
+
+	rng := rand.New(rand.NewSource(spec.Seed))
+
+	var deck []*models.Action
+	if spec.TruthRatio == nil {
+		deck = sampleFromPools(anyPools, spec.Count, rng)
+	} else {
+		truthTarget := int(math.Round(float64(spec.Count) * *spec.TruthRatio))
+		deck = sampleFromPools(truthPools, truthTarget, rng)
+		deck = append(deck, sampleFromPools(darePools, spec.Count-len(deck), rng)...)
+		// Если карточек одного типа не хватило, добираем другим
+		if len(deck) < spec.Count {
+			deck = append(deck, sampleFromPools(truthPools, spec.Count-len(deck), rng)...)
+		}
+	}
+
+	rng.Shuffle(len(deck), func(i, j int) {
+		deck[i], deck[j] = deck[j], deck[i]
+	})
+
+	return &MixedDeck{Seed: spec.Seed, Actions: deck}, nil
+}
+
+// sampleFromPools выбирает до n карточек без повторов: источник выбирается
+// пропорционально весу, карточка внутри источника - равновероятно
+func sampleFromPools(pools []*mixPool, n int, rng *rand.Rand) []*models.Action {
+	result := make([]*models.Action, 0, n)
+	for len(result) < n {
+		total := 0.0
+		for _, pool := range pools {
+			if len(pool.actions) > 0 {
+				total += pool.weight
+			}
+		}
+		if total == 0 {
+			break
+		}
+
+		r := rng.Float64() * total
+		var chosen *mixPool
+		for _, pool := range pools {
+			if len(pool.actions) == 0 {
+				continue
+			}
+			chosen = pool
+			if r < pool.weight {
+				break
+			}
+			r -= pool.weight
+		}
+
+		idx := rng.Intn(len(chosen.actions))
+		result = append(result, chosen.actions[idx])
+		last := len(chosen.actions) - 1
+		chosen.actions[idx] = chosen.actions[last]
+		chosen.actions = chosen.actions[:last]
+	}
+	return result
+}
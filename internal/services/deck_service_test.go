@@ -0,0 +1,274 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+// fakeCollectionRepo отдает коллекции из памяти; остальные методы не используются
+type fakeCollectionRepo struct {
+	repository.CollectionRepository
+	collections map[uint]*models.Collection
+}
+
+func (r *fakeCollectionRepo) GetByID(id uint) (*models.Collection, error) {
+	collection, ok := r.collections[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return collection, nil
+}
+
+// fakeActionRepo отдает карточки коллекций из памяти; остальные методы не используются
+type fakeActionRepo struct {
+	repository.ActionRepository
+	actions map[uint][]*models.Action
+}
+
+func (r *fakeActionRepo) GetByCollectionID(collectionID uint) ([]*models.Action, error) {
+	// Копия, чтобы сортировка в buildMixedDeck не меняла общие данные тестов
+	actions := make([]*models.Action, len(r.actions[collectionID]))
+	copy(actions, r.actions[collectionID])
+	return actions, nil
+}
+
+// newMixFixture создает коллекции:
+//
+//	1 - 4 "правды" и 4 "действия" (0+)
+//	2 - 2 "правды" и 6 "действий", одна "правда" повторяет карточку коллекции 1 (0+)
+//	3 - 4 "правды" (18+)
+//	4 - 4 "действия", скрыта модератором
+//	5 - виртуальная коллекция
+func newMixFixture() (*fakeCollectionRepo, *fakeActionRepo) {
+	hiddenAt := time.Now()
+	collections := &fakeCollectionRepo{collections: map[uint]*models.Collection{
+		1: {ID: 1, UserID: 100},
+		2: {ID: 2, UserID: 100},
+		3: {ID: 3, UserID: 200, AgeRating: models.AgeRating18},
+		4: {ID: 4, UserID: 200, HiddenAt: &hiddenAt},
+		5: {ID: 5, UserID: 100, IsVirtual: true},
+	}}
+
+	actions := &fakeActionRepo{actions: make(map[uint][]*models.Action)}
+	nextID := uint(1)
+	add := func(collectionID uint, actionType models.ActionType, text string) {
+		actions.actions[collectionID] = append(actions.actions[collectionID], &models.Action{
+			ID:           nextID,
+			CollectionID: collectionID,
+			Type:         actionType,
+			Text:         text,
+			Order:        len(actions.actions[collectionID]) + 1,
+		})
+		nextID++
+	}
+	for _, text := range []string{"Правда 1", "Правда 2", "Правда 3", "Правда 4"} {
+		add(1, models.ActionTypeTruth, text)
+	}
+	for _, text := range []string{"Действие 1", "Действие 2", "Действие 3", "Действие 4"} {
+		add(1, models.ActionTypeDare, text)
+	}
+	add(2, models.ActionTypeTruth, "  правда   1 ") // Дубликат "Правда 1" с другим регистром и пробелами
+	add(2, models.ActionTypeTruth, "Правда 5")
+	for _, text := range []string{"Действие 5", "Действие 6", "Действие 7", "Действие 8", "Действие 9", "Действие 10"} {
+		add(2, models.ActionTypeDare, text)
+	}
+	for _, text := range []string{"Взрослая 1", "Взрослая 2", "Взрослая 3", "Взрослая 4"} {
+		add(3, models.ActionTypeTruth, text)
+	}
+	for _, text := range []string{"Скрытая 1", "Скрытая 2", "Скрытая 3", "Скрытая 4"} {
+		add(4, models.ActionTypeDare, text)
+	}
+	return collections, actions
+}
+
+func TestBuildMixedDeck(t *testing.T) {
+	ratio := func(v float64) *float64 { return &v }
+	all := models.CollectionVisibility{MaxAgeRating: models.AgeRating18}
+	owner := uint(200)
+
+	tests := []struct {
+		name       string
+		spec       DeckMixSpec
+		visibility models.CollectionVisibility
+		wantCount  int
+		wantTruths int // -1 - не проверять
+		wantFrom   []uint
+		wantErr    error
+	}{
+		{
+			name:       "all types from one source",
+			spec:       DeckMixSpec{Sources: []DeckMixSourceSpec{{CollectionID: 1}}, Count: 5, Seed: 1},
+			visibility: all,
+			wantCount:  5,
+			wantTruths: -1,
+			wantFrom:   []uint{1},
+		},
+		{
+			name:       "count above available cards skips duplicates",
+			spec:       DeckMixSpec{Sources: []DeckMixSourceSpec{{CollectionID: 1}, {CollectionID: 2}}, Count: 100, Seed: 1},
+			visibility: all,
+			wantCount:  15,
+			wantTruths: 5,
+			wantFrom:   []uint{1, 2},
+		},
+		{
+			name:       "truth ratio",
+			spec:       DeckMixSpec{Sources: []DeckMixSourceSpec{{CollectionID: 1}, {CollectionID: 2}}, Count: 8, TruthRatio: ratio(0.25), Seed: 2},
+			visibility: all,
+			wantCount:  8,
+			wantTruths: 2,
+			wantFrom:   []uint{1, 2},
+		},
+		{
+			name:       "missing truths are filled with dares",
+			spec:       DeckMixSpec{Sources: []DeckMixSourceSpec{{CollectionID: 2}}, Count: 6, TruthRatio: ratio(1), Seed: 3},
+			visibility: all,
+			wantCount:  6,
+			wantTruths: 2,
+			wantFrom:   []uint{2},
+		},
+		{
+			name:       "age rating above limit is skipped",
+			spec:       DeckMixSpec{Sources: []DeckMixSourceSpec{{CollectionID: 1}, {CollectionID: 3}}, Count: 12, Seed: 4},
+			visibility: models.CollectionVisibility{MaxAgeRating: models.AgeRating16},
+			wantCount:  8,
+			wantTruths: 4,
+			wantFrom:   []uint{1},
+		},
+		{
+			name:       "hidden collection is skipped",
+			spec:       DeckMixSpec{Sources: []DeckMixSourceSpec{{CollectionID: 1}, {CollectionID: 4}}, Count: 12, Seed: 5},
+			visibility: all,
+			wantCount:  8,
+			wantTruths: 4,
+			wantFrom:   []uint{1},
+		},
+		{
+			name:       "owner sees own hidden and adult collections",
+			spec:       DeckMixSpec{Sources: []DeckMixSourceSpec{{CollectionID: 3}, {CollectionID: 4}}, Count: 8, Seed: 6},
+			visibility: models.CollectionVisibility{MaxAgeRating: models.AgeRatingAll, ViewerID: &owner},
+			wantCount:  8,
+			wantTruths: 4,
+			wantFrom:   []uint{3, 4},
+		},
+		{
+			name:       "deleted source is skipped",
+			spec:       DeckMixSpec{Sources: []DeckMixSourceSpec{{CollectionID: 1}, {CollectionID: 42}}, Count: 12, Seed: 7},
+			visibility: all,
+			wantCount:  8,
+			wantTruths: 4,
+			wantFrom:   []uint{1},
+		},
+		{
+			name:    "virtual source",
+			spec:    DeckMixSpec{Sources: []DeckMixSourceSpec{{CollectionID: 5}}, Count: 5},
+			wantErr: ErrNestedVirtualMixes,
+		},
+		{
+			name:    "duplicate source",
+			spec:    DeckMixSpec{Sources: []DeckMixSourceSpec{{CollectionID: 1}, {CollectionID: 1}}, Count: 5},
+			wantErr: ErrInvalidDeckMix,
+		},
+		{
+			name:    "zero count",
+			spec:    DeckMixSpec{Sources: []DeckMixSourceSpec{{CollectionID: 1}}},
+			wantErr: ErrInvalidDeckMix,
+		},
+		{
+			name:    "ratio out of range",
+			spec:    DeckMixSpec{Sources: []DeckMixSourceSpec{{CollectionID: 1}}, Count: 5, TruthRatio: ratio(1.5)},
+			wantErr: ErrInvalidDeckMix,
+		},
+		{
+			name:    "negative weight",
+			spec:    DeckMixSpec{Sources: []DeckMixSourceSpec{{CollectionID: 1, Weight: -1}}, Count: 5},
+			wantErr: ErrInvalidDeckMix,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collectionRepo, actionRepo := newMixFixture()
+			deck, err := buildMixedDeck(collectionRepo, actionRepo, tt.spec, tt.visibility)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("buildMixedDeck() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildMixedDeck() unexpected error: %v", err)
+			}
+
+			if len(deck.Actions) != tt.wantCount {
+				t.Fatalf("deck has %d cards, want %d", len(deck.Actions), tt.wantCount)
+			}
+			allowed := make(map[uint]bool, len(tt.wantFrom))
+			for _, id := range tt.wantFrom {
+				allowed[id] = true
+			}
+			seenIDs := make(map[uint]bool, len(deck.Actions))
+			truths := 0
+			for _, action := range deck.Actions {
+				if seenIDs[action.ID] {
+					t.Errorf("card %d is in the deck twice", action.ID)
+				}
+				seenIDs[action.ID] = true
+				if !allowed[action.CollectionID] {
+					t.Errorf("card %d comes from collection %d", action.ID, action.CollectionID)
+				}
+				if action.Type == models.ActionTypeTruth {
+					truths++
+				}
+			}
+			if tt.wantTruths >= 0 && truths != tt.wantTruths {
+				t.Errorf("deck has %d truths, want %d", truths, tt.wantTruths)
+			}
+		})
+	}
+}
+
+func TestBuildMixedDeckSameSeedSameDeck(t *testing.T) {
+	spec := DeckMixSpec{
+		Sources: []DeckMixSourceSpec{{CollectionID: 1, Weight: 2}, {CollectionID: 2}},
+		Count:   10,
+		Seed:    12345,
+	}
+	visibility := models.CollectionVisibility{MaxAgeRating: models.AgeRating18}
+
+	build := func() []uint {
+		collectionRepo, actionRepo := newMixFixture()
+		deck, err := buildMixedDeck(collectionRepo, actionRepo, spec, visibility)
+		if err != nil {
+			t.Fatalf("buildMixedDeck() unexpected error: %v", err)
+		}
+		ids := make([]uint, 0, len(deck.Actions))
+		for _, action := range deck.Actions {
+			ids = append(ids, action.ID)
+		}
+		return ids
+	}
+
+	want := build()
+	for i := 0; i < 5; i++ {
+		got := build()
+		for j := range want {
+			if got[j] != want[j] {
+				t.Fatalf("same seed built %v, then %v", want, got)
+			}
+		}
+	}
+}
+
+func TestDeckServiceMixRejectsUnknownSource(t *testing.T) {
+	collectionRepo, actionRepo := newMixFixture()
+	service := &deckService{collectionRepo: collectionRepo, actionRepo: actionRepo}
+	spec := DeckMixSpec{Sources: []DeckMixSourceSpec{{CollectionID: 1}, {CollectionID: 42}}, Count: 5}
+	if _, err := service.Mix(spec, models.CollectionVisibility{MaxAgeRating: models.AgeRating18}); !errors.Is(err, ErrMixSourceNotFound) {
+		t.Fatalf("Mix() error = %v, want %v", err, ErrMixSourceNotFound)
+	}
+}
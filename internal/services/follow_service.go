@@ -0,0 +1,237 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+var (
+	ErrCannotFollowSelf  = errors.New("cannot follow yourself")
+	ErrInvalidFeedCursor = errors.New("invalid feed cursor")
+)
+
+// Количество коллекций на странице ленты по умолчанию и наибольшее
+const (
+	defaultFeedSize = 20
+	maxFeedSize     = 100
+)
+
+// FollowState представляет подписку текущего пользователя на автора и счетчики автора.
+// Если автор скрыл подписки, ShowCounts == false и счетчики не заполняются.
+type FollowState struct {
+	UserID         uint
+	Following      bool
+	ShowCounts     bool
+	FollowerCount  int
+	FollowingCount int
+}
+
+// FeedPage представляет страницу ленты подписок.
+// NextCursor пустой, если более ранних коллекций нет.
+type FeedPage struct {
+	Collections []*models.Collection
+	NextCursor  string
+}
+
+// FollowService определяет методы сервиса подписок на авторов и ленты подписок.
+// Подписка и отписка идемпотентны: повторный запрос не меняет счетчики.
+type FollowService interface {
+	Follow(followerID, followeeID uint) (*FollowState, error)
+	Unfollow(followerID, followeeID uint) (*FollowState, error)
+	IsFollowing(followerID, followeeID uint) (bool, error)
+	// GetFollowers и GetFollowing возвращают ErrProfileSectionHidden, если пользователь
+	// скрыл подписки, а список запрашивает не он сам (viewerID == nil - без авторизации)
+	GetFollowers(userID uint, viewerID *uint, page, pageSize int) ([]*models.User, int64, error)
+	GetFollowing(userID uint, viewerID *uint, page, pageSize int) ([]*models.User, int64, error)
+	GetFeed(userID uint, cursor string, size, maxAgeRating int) (*FeedPage, error)
+}
+
+// followService реализует интерфейс FollowService
+type followService struct {
+	followRepo          repository.FollowRepository
+	userRepo            repository.UserRepository
+	blockRepo           repository.BlockRepository
+	notificationService NotificationService
+}
+
+// NewFollowService создает новый экземпляр сервиса подписок
+func NewFollowService(followRepo repository.FollowRepository, userRepo repository.UserRepository, blockRepo repository.BlockRepository, notificationService NotificationService) FollowService {
+	return &followService{
+		followRepo:          followRepo,
+		userRepo:            userRepo,
+		blockRepo:           blockRepo,
+		notificationService: notificationService,
+	}
+}
+
+// Follow подписывает пользователя на автора и уведомляет автора о новом подписчике.
+// При блокировке между пользователями возвращает ErrUserBlocked.
+func (s *followService) Follow(followerID, followeeID uint) (*FollowState, error) {
+	return s.apply(followerID, followeeID, true, s.followRepo.Follow)
+}
+
+// Unfollow отписывает пользователя от автора
+func (s *followService) Unfollow(followerID, followeeID uint) (*FollowState, error) {
+	return s.apply(followerID, followeeID, false, s.followRepo.Unfollow)
+}
+
+// IsFollowing проверяет, подписан ли followerID на followeeID
+func (s *followService) IsFollowing(followerID, followeeID uint) (bool, error) {
+	followed, err := s.followRepo.GetFollowedIDs(followerID, []uint{followeeID})
+	if err != nil {
+		return false, err
+	}
+	return followed[followeeID], nil
+}
+
+// GetFollowers возвращает подписчиков пользователя
+func (s *followService) GetFollowers(userID uint, viewerID *uint, page, pageSize int) ([]*models.User, int64, error) {
+	return s.list(userID, viewerID, page, pageSize, s.followRepo.GetFollowers)
+}
+
+// GetFollowing возвращает авторов, на которых подписан пользователь
+func (s *followService) GetFollowing(userID uint, viewerID *uint, page, pageSize int) ([]*models.User, int64, error) {
+	return s.list(userID, viewerID, page, pageSize, s.followRepo.GetFollowing)
+}
+
+// GetFeed возвращает страницу ленты: новые и измененные коллекции авторов из подписок.
+// Пустой cursor - первая страница, дальше передается NextCursor предыдущей страницы.
+func (s *followService) GetFeed(userID uint, cursor string, size, maxAgeRating int) (*FeedPage, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if size < 1 || size > maxFeedSize {
+		size = defaultFeedSize
+	}
+
+	var position *models.FeedCursor
+	if cursor != "" {
+		parsed, err := parseFeedCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		position = parsed
+	}
+
+	// Лишняя коллекция показывает, есть ли следующая страница
+	collections, err := s.followRepo.GetFeed(userID, position, size+1, maxAgeRating)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &FeedPage{Collections: collections}
+	if len(collections) > size {
+		page.Collections = collections[:size]
+		last := page.Collections[size-1]
+		page.NextCursor = encodeFeedCursor(models.FeedCursor{UpdatedAt: last.UpdatedAt, CollectionID: last.ID})
+	}
+	return page, nil
+}
+
+// apply выполняет операцию над подпиской и возвращает актуальное состояние автора.
+// follow означает создание подписки: оно запрещено, если пользователи заблокировали
+// друг друга, а новая подписка отправляет автору уведомление.
+func (s *followService) apply(followerID, followeeID uint, follow bool, operation func(followerID, followeeID uint) (bool, error)) (*FollowState, error) {
+	if followerID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if followerID == followeeID {
+		return nil, ErrCannotFollowSelf
+	}
+	if _, err := s.userRepo.GetByID(followeeID); err != nil {
+		return nil, ErrUserNotFound
+	}
+	if follow {
+		if err := checkNotBlocked(s.blockRepo, followerID, followeeID); err != nil {
+			return nil, err
+		}
+	}
+
+	changed, err := operation(followerID, followeeID)
+	if err != nil {
+		return nil, err
+	}
+	if changed && follow {
+		s.notificationService.Notify(NotificationEvent{
+			Type:        models.NotificationFollow,
+			RecipientID: followeeID,
+			ActorID:     followerID,
+		})
+	}
+
+	// Перечитываем автора, чтобы вернуть счетчики с учетом параллельных подписок
+	followee, err := s.userRepo.GetByID(followeeID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	following, err := s.IsFollowing(followerID, followeeID)
+	if err != nil {
+		return nil, err
+	}
+	state := &FollowState{
+		UserID:    followee.ID,
+		Following: following,
+	}
+	if !followee.Privacy.HideFollows {
+		state.ShowCounts = true
+		state.FollowerCount = followee.FollowerCount
+		state.FollowingCount = followee.FollowingCount
+	}
+	return state, nil
+}
+
+// list проверяет пользователя и его настройки приватности и возвращает страницу его подписчиков или подписок
+func (s *followService) list(userID uint, viewerID *uint, page, pageSize int, query func(userID uint, offset, limit int) ([]*models.User, int64, error)) ([]*models.User, int64, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, 0, ErrUserNotFound
+	}
+	if err := checkProfileVisible(s.blockRepo, user.ID, viewerID); err != nil {
+		return nil, 0, err
+	}
+	if user.Privacy.HideFollows && (viewerID == nil || *viewerID != user.ID) {
+		return nil, 0, ErrProfileSectionHidden
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return query(userID, (page-1)*pageSize, pageSize)
+}
+
+// encodeFeedCursor кодирует позицию в ленте в непрозрачную для клиента строку.
+// Время хранится в микросекундах - с такой точностью его хранит PostgreSQL.
+func encodeFeedCursor(cursor models.FeedCursor) string {
+	raw := strconv.FormatInt(cursor.UpdatedAt.UnixMicro(), 10) + ":" + strconv.FormatUint(uint64(cursor.CollectionID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// parseFeedCursor разбирает строку, полученную из encodeFeedCursor
+func parseFeedCursor(value string) (*models.FeedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidFeedCursor
+	}
+	micros, id, found := strings.Cut(string(raw), ":")
+	if !found {
+		return nil, ErrInvalidFeedCursor
+	}
+	updatedAt, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidFeedCursor
+	}
+	collectionID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, ErrInvalidFeedCursor
+	}
+	return &models.FeedCursor{UpdatedAt: time.UnixMicro(updatedAt), CollectionID: uint(collectionID)}, nil
+}
@@ -0,0 +1,1029 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrGameNotFound       = errors.New("game session not found")
+	ErrGameFinished       = errors.New("game session is already finished")
+	ErrNoPlayers          = errors.New("game session has no players")
+	ErrNoCardsAvailable   = errors.New("no cards available for this draw")
+	ErrPlayerNameTaken    = errors.New("player name is already taken")
+	ErrTooManyPlayers     = errors.New("too many players")
+	ErrNotGameParticipant = errors.New("user is not allowed to manage this game session")
+	ErrInvalidGameRules   = errors.New("invalid game rules")
+	ErrNoPendingDraw      = errors.New("no drawn card is waiting to be completed or skipped")
+	ErrSkipLimitReached   = errors.New("player has no skips left")
+	ErrDrawPending        = errors.New("previous card must be completed or skipped before the next draw")
+)
+
+const (
+	// DefaultGamePoints начисляется за карточку, если правила не задают другое значение
+	DefaultGamePoints = 1
+	// MaxSkipLimit ограничивает количество пропусков в правилах
+	MaxSkipLimit = 100
+	// staleSessionBatchSize ограничивает количество сессий, закрываемых за один проход
+	staleSessionBatchSize = 500
+)
+
+// GamePlayerSpec описывает игрока при создании сессии
+type GamePlayerSpec struct {
+	Name     string
+	UserID   *uint // Зарегистрированный пользователь, за которым сохраняется результат
+	MemberID *uint // Участник комнаты, который ходит за игрока
+}
+
+// GameOptions задает параметры новой игровой сессии
+type GameOptions struct {
+	Players      []GamePlayerSpec
+	Seed         int64             // 0 - случайный seed
+	Rules        *models.GameRules // nil - правила по умолчанию
+	RoomID       *uint             // Комната, в истории которой учитываются карточки
+	PreferUnseen bool              // Сначала выдавать карточки, которых участники еще не видели
+	// Перемешивать колоду с учетом качества карточек: хорошо оцененные выпадают раньше
+	QualityWeighted bool
+	// Наибольший допустимый возрастной рейтинг коллекции и коллекции штрафов
+	// (владелец коллекции играет в нее без ограничения)
+	MaxAgeRating int
+}
+
+// TurnResult содержит исход хода после выполнения или пропуска карточки
+type TurnResult struct {
+	Draw    *models.GameDraw
+	Player  *models.GamePlayer
+	Penalty *models.Action // Штрафная карточка за пропуск (текст с подстановкой в Draw.PenaltyText)
+}
+
+// ScoreEntry представляет строку таблицы результатов
+type ScoreEntry struct {
+	Player *models.GamePlayer
+	Rank   int // Игроки с равным счетом делят место
+}
+
+// DrawResult содержит вытянутую карточку и состояние сессии после хода
+type DrawResult struct {
+	Draw     *models.GameDraw
+	Action   *models.Action
+	Player   *models.GamePlayer
+	Extra    models.ActionExtra
+	NewRound bool // Колода была исчерпана и перемешана заново перед этим ходом
+}
+
+// GameService определяет методы сервиса игровых сессий
+type GameService interface {
+	Start(collectionID uint, userID *uint, options GameOptions) (*models.GameSession, error)
+	GetByID(id string) (*models.GameSession, error)
+	AddPlayer(sessionID string, userID *uint, player GamePlayerSpec) (*models.GamePlayer, error)
+	// RemovePlayer выводит игрока из очереди ходов; его очки остаются в таблице результатов
+	RemovePlayer(sessionID string, userID *uint, playerID uint) error
+	Draw(sessionID string, userID *uint, actionType models.ActionType) (*DrawResult, error)
+	Complete(sessionID string, userID *uint) (*TurnResult, error)
+	Skip(sessionID string, userID *uint) (*TurnResult, error)
+	End(sessionID string, userID *uint) (*models.GameSession, error)
+	RemainingCards(session *models.GameSession) int
+	CurrentPlayer(session *models.GameSession) *models.GamePlayer
+	CloseStale(inactiveFor time.Duration) (int, error)
+	Scoreboard(session *models.GameSession) []ScoreEntry
+	GetUserResults(userID uint, page, pageSize int) ([]*models.GameResult, int64, error)
+}
+
+// gameService реализует интерфейс GameService
+type gameService struct {
+	gameRepo          repository.GameRepository
+	historyRepo       repository.HistoryRepository
+	feedbackRepo      repository.CardFeedbackRepository
+	collectionService CollectionService
+
+	// newSeed генерирует seed для сессий, запущенных без явного seed
+	newSeed func() int64
+
+	// locks сериализует ходы внутри одной сессии
+	locks sync.Map
+}
+
+// NewGameService создает новый экземпляр сервиса игровых сессий
+func NewGameService(
+	gameRepo repository.GameRepository,
+	historyRepo repository.HistoryRepository,
+	feedbackRepo repository.CardFeedbackRepository,
+	collectionService CollectionService,
+) GameService {
+	return &gameService{
+		gameRepo:          gameRepo,
+		historyRepo:       historyRepo,
+		feedbackRepo:      feedbackRepo,
+		collectionService: collectionService,
+		newSeed:           newSeed,
+	}
+}
+
+// Start создает игровую сессию по коллекции и увеличивает счетчик запусков коллекции
+func (s *gameService) Start(collectionID uint, userID *uint, options GameOptions) (*models.GameSession, error) {
+	if len(options.Players) > 0 {
+		names := make([]string, 0, len(options.Players))
+		linked := make(map[uint]bool)
+		for _, player := range options.Players {
+			names = append(names, player.Name)
+			if player.UserID != nil {
+				if linked[*player.UserID] {
+					return nil, fmt.Errorf("%w: user can be linked to only one player", ErrInvalidPlayers)
+				}
+				linked[*player.UserID] = true
+			}
+		}
+		if err := validatePlayers(names); err != nil {
+			return nil, err
+		}
+	}
+
+	visibility := models.CollectionVisibility{MaxAgeRating: options.MaxAgeRating, ViewerID: userID}
+	rules, err := s.prepareRules(options.Rules, visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := s.collectionService.GetByID(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if !visibility.Allows(collection) {
+		return nil, ErrCollectionNotFound
+	}
+	actions := collection.Actions
+	if len(actions) == 0 {
+		return nil, ErrNoCardsAvailable
+	}
+
+	seed := options.Seed
+	if seed == 0 {
+		seed = s.newSeed()
+	}
+
+	now := time.Now()
+	session := &models.GameSession{
+		ID:              uuid.New().String(),
+		CollectionID:    collectionID,
+		UserID:          userID,
+		RoomID:          options.RoomID,
+		Status:          models.GameSessionActive,
+		Seed:            seed,
+		Round:           1,
+		Rules:           rules,
+		PreferUnseen:    options.PreferUnseen,
+		QualityWeighted: options.QualityWeighted,
+		StartedAt:       now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	for i, player := range options.Players {
+		session.Players = append(session.Players, &models.GamePlayer{
+			UserID:    player.UserID,
+			MemberID:  player.MemberID,
+			Name:      strings.TrimSpace(player.Name),
+			Position:  i,
+			CreatedAt: now,
+		})
+	}
+
+	if session.DeckOrder, err = s.deckOrder(session, actions); err != nil {
+		return nil, err
+	}
+
+	if err := s.gameRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	if err := s.collectionService.IncrementPlayCount(collectionID); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// GetByID возвращает игровую сессию
+func (s *gameService) GetByID(id string) (*models.GameSession, error) {
+	session, err := s.gameRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrGameNotFound
+	}
+	return session, nil
+}
+
+// AddPlayer регистрирует нового игрока, он ходит последним. Зарегистрированный
+// пользователь, который раньше вышел из сессии, возвращается на свое место со своими очками.
+func (s *gameService) AddPlayer(sessionID string, userID *uint, spec GamePlayerSpec) (*models.GamePlayer, error) {
+	unlock := s.lock(sessionID)
+	defer unlock()
+
+	session, err := s.activeSession(sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(spec.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: player name must not be empty", ErrInvalidPlayers)
+	}
+	active := activePlayers(session)
+	if len(active) >= MaxRenderPlayers {
+		return nil, ErrTooManyPlayers
+	}
+	for _, player := range active {
+		if strings.EqualFold(player.Name, name) {
+			return nil, ErrPlayerNameTaken
+		}
+	}
+
+	for _, player := range session.Players {
+		if spec.UserID == nil || player.UserID == nil || *player.UserID != *spec.UserID {
+			continue
+		}
+		if player.LeftAt == nil {
+			return nil, fmt.Errorf("%w: user can be linked to only one player", ErrInvalidPlayers)
+		}
+		player.LeftAt = nil
+		player.MemberID = spec.MemberID
+		player.Name = name
+		if err := s.gameRepo.UpdatePlayer(player); err != nil {
+			return nil, err
+		}
+		return player, nil
+	}
+
+	player := &models.GamePlayer{
+		SessionID: session.ID,
+		UserID:    spec.UserID,
+		MemberID:  spec.MemberID,
+		Name:      name,
+		Position:  len(session.Players),
+		CreatedAt: time.Now(),
+	}
+	if err := s.gameRepo.AddPlayer(player); err != nil {
+		return nil, err
+	}
+	return player, nil
+}
+
+// RemovePlayer отмечает игрока вышедшим. Если сейчас ожидается его карточка,
+// ее по-прежнему можно выполнить или пропустить.
+func (s *gameService) RemovePlayer(sessionID string, userID *uint, playerID uint) error {
+	unlock := s.lock(sessionID)
+	defer unlock()
+
+	session, err := s.activeSession(sessionID, userID)
+	if err != nil {
+		return err
+	}
+	for _, player := range session.Players {
+		if player.ID != playerID {
+			continue
+		}
+		if player.LeftAt != nil {
+			return nil
+		}
+		now := time.Now()
+		player.LeftAt = &now
+		return s.gameRepo.UpdatePlayer(player)
+	}
+	return fmt.Errorf("%w: player %d not found", ErrInvalidPlayers, playerID)
+}
+
+// Draw вытягивает следующую карточку для игрока, чей сейчас ход.
+// Карточки не повторяются, пока колода не исчерпана; после этого колода
+// перемешивается заново. Если указан тип, выбирается ближайшая карточка этого типа.
+func (s *gameService) Draw(sessionID string, userID *uint, actionType models.ActionType) (*DrawResult, error) {
+	unlock := s.lock(sessionID)
+	defer unlock()
+
+	session, err := s.activeSession(sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	players := activePlayers(session)
+	if len(players) == 0 {
+		return nil, ErrNoPlayers
+	}
+	// Следующую карточку можно вытянуть только после выполнения или пропуска предыдущей,
+	// иначе повторный Draw обходил бы штрафы и лимит пропусков
+	if n := len(session.Draws); n > 0 && session.Draws[n-1].Outcome == models.DrawPending {
+		return nil, ErrDrawPending
+	}
+	if actionType != "" && !models.IsValidActionType(actionType) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidActionType, actionType)
+	}
+
+	actions, err := s.sessionActions(session, session.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	// Карточки с {other} нельзя разыграть в одиночку: пропускаем их, чтобы сессия не застряла.
+	// В колоде они остаются и выпадут, когда в сессии появится второй игрок.
+	playable := playableActions(actions, len(players))
+	byID := make(map[uint]*models.Action, len(playable))
+	for _, action := range playable {
+		byID[action.ID] = action
+	}
+
+	// Ищем карточку в текущем круге, при необходимости начинаем новый круг
+	newRound := false
+	action := nextCard(session, byID, actionType)
+	if action == nil {
+		if !hasCardOfType(playable, actionType) {
+			return nil, ErrNoCardsAvailable
+		}
+		session.Round++
+		if session.DeckOrder, err = s.deckOrder(session, actions); err != nil {
+			return nil, err
+		}
+		newRound = true
+		action = nextCard(session, byID, actionType)
+	}
+
+	player := s.CurrentPlayer(session)
+	names := playerNames(players)
+
+	// Шаблон карточки рендерится детерминированно от seed сессии и номера хода
+	rendered, err := renderAction(action, CardRenderContext{
+		Players:       names,
+		CurrentPlayer: player.Name,
+		Rand:          rand.New(rand.NewSource(session.Seed + int64(session.Turn+1)*7919)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	session.Turn++
+	session.UpdatedAt = time.Now()
+	draw := &models.GameDraw{
+		SessionID: session.ID,
+		ActionID:  action.ID,
+		PlayerID:  player.ID,
+		Round:     session.Round,
+		Turn:      session.Turn,
+		Type:      action.Type,
+		Text:      rendered.Text,
+		Outcome:   models.DrawPending,
+		CreatedAt: session.UpdatedAt,
+	}
+	if err := s.gameRepo.SaveDraw(session, draw, cardViews(session, action, session.UpdatedAt)); err != nil {
+		return nil, err
+	}
+	session.Draws = append(session.Draws, draw)
+
+	return &DrawResult{
+		Draw:     draw,
+		Action:   action,
+		Player:   player,
+		Extra:    rendered.Extra,
+		NewRound: newRound,
+	}, nil
+}
+
+// Complete отмечает последнюю вытянутую карточку как выполненную и начисляет очки ее игроку
+func (s *gameService) Complete(sessionID string, userID *uint) (*TurnResult, error) {
+	unlock := s.lock(sessionID)
+	defer unlock()
+
+	session, err := s.activeSession(sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	draw, player, err := pendingDraw(session)
+	if err != nil {
+		return nil, err
+	}
+
+	actions, err := s.collectionService.GetActions(session.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	var action *models.Action
+	for _, a := range actions {
+		if a.ID == draw.ActionID {
+			action = a
+			break
+		}
+	}
+
+	draw.Outcome = models.DrawCompleted
+	draw.Points = cardPoints(session.Rules, draw, action)
+	player.Score += draw.Points
+	player.Completed++
+	if err := s.gameRepo.ResolveDraw(draw, player); err != nil {
+		return nil, err
+	}
+
+	return &TurnResult{Draw: draw, Player: player}, nil
+}
+
+// Skip отмечает последнюю вытянутую карточку как пропущенную: списывает штрафные
+// очки и, если в правилах указана коллекция штрафов, выдает штрафную карточку
+func (s *gameService) Skip(sessionID string, userID *uint) (*TurnResult, error) {
+	unlock := s.lock(sessionID)
+	defer unlock()
+
+	session, err := s.activeSession(sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	draw, player, err := pendingDraw(session)
+	if err != nil {
+		return nil, err
+	}
+	rules := session.Rules
+	if rules.SkipLimit > 0 && player.Skipped >= rules.SkipLimit {
+		return nil, ErrSkipLimitReached
+	}
+
+	result := &TurnResult{Draw: draw, Player: player}
+	if rules.PenaltyCollectionID != nil {
+		penalty, text, err := s.penaltyCard(session, player, *rules.PenaltyCollectionID)
+		if err != nil {
+			return nil, err
+		}
+		if penalty != nil {
+			draw.PenaltyActionID = &penalty.ID
+			draw.PenaltyText = text
+			result.Penalty = penalty
+		}
+	}
+
+	draw.Outcome = models.DrawSkipped
+	draw.Points = -rules.SkipPenalty
+	player.Score += draw.Points
+	player.Skipped++
+	if err := s.gameRepo.ResolveDraw(draw, player); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// End завершает игровую сессию и сохраняет результаты зарегистрированных игроков
+func (s *gameService) End(sessionID string, userID *uint) (*models.GameSession, error) {
+	unlock := s.lock(sessionID)
+	defer unlock()
+
+	session, err := s.activeSession(sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session.Status = models.GameSessionFinished
+	session.EndedAt = &now
+	session.UpdatedAt = now
+
+	var results []*models.GameResult
+	for _, entry := range s.Scoreboard(session) {
+		if entry.Player.UserID == nil {
+			continue
+		}
+		results = append(results, &models.GameResult{
+			SessionID:    session.ID,
+			UserID:       *entry.Player.UserID,
+			CollectionID: session.CollectionID,
+			PlayerName:   entry.Player.Name,
+			Score:        entry.Player.Score,
+			Completed:    entry.Player.Completed,
+			Skipped:      entry.Player.Skipped,
+			Rank:         entry.Rank,
+			PlayerCount:  len(session.Players),
+			FinishedAt:   now,
+		})
+	}
+
+	if err := s.gameRepo.Finish(session, results, newPlayEvent(session, true)); err != nil {
+		return nil, err
+	}
+	s.release(sessionID)
+	return session, nil
+}
+
+// CloseStale закрывает сессии без ходов дольше inactiveFor и записывает для них
+// незавершенные партии. Результаты игроков для брошенных сессий не сохраняются.
+func (s *gameService) CloseStale(inactiveFor time.Duration) (int, error) {
+	ids, err := s.gameRepo.GetStaleSessionIDs(time.Now().Add(-inactiveFor), staleSessionBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, id := range ids {
+		ok, err := s.closeStale(id, inactiveFor)
+		if err != nil {
+			return closed, err
+		}
+		if ok {
+			closed++
+		}
+	}
+	return closed, nil
+}
+
+// closeStale закрывает одну сессию, если она по-прежнему активна и простаивает
+func (s *gameService) closeStale(sessionID string, inactiveFor time.Duration) (bool, error) {
+	unlock := s.lock(sessionID)
+	defer unlock()
+
+	session, err := s.GetByID(sessionID)
+	if err != nil {
+		s.release(sessionID)
+		return false, err
+	}
+	// Пока сессия ждала блокировки, в ней могли сделать ход
+	if session.Status != models.GameSessionActive {
+		s.release(sessionID)
+		return false, nil
+	}
+	if time.Since(session.UpdatedAt) < inactiveFor {
+		return false, nil
+	}
+
+	session.Status = models.GameSessionAbandoned
+	session.EndedAt = &session.UpdatedAt
+	if err := s.gameRepo.Finish(session, nil, newPlayEvent(session, false)); err != nil {
+		return false, err
+	}
+	s.release(sessionID)
+	return true, nil
+}
+
+// Scoreboard возвращает таблицу результатов: по убыванию очков, затем по количеству
+// выполненных карточек и порядку хода
+func (s *gameService) Scoreboard(session *models.GameSession) []ScoreEntry {
+	players := make([]*models.GamePlayer, len(session.Players))
+	copy(players, session.Players)
+	sort.SliceStable(players, func(i, j int) bool {
+		if players[i].Score != players[j].Score {
+			return players[i].Score > players[j].Score
+		}
+		if players[i].Completed != players[j].Completed {
+			return players[i].Completed > players[j].Completed
+		}
+		return players[i].Position < players[j].Position
+	})
+
+	entries := make([]ScoreEntry, 0, len(players))
+	for i, player := range players {
+		rank := i + 1
+		if i > 0 && player.Score == players[i-1].Score {
+			rank = entries[i-1].Rank
+		}
+		entries = append(entries, ScoreEntry{Player: player, Rank: rank})
+	}
+	return entries
+}
+
+// GetUserResults возвращает результаты завершенных игр пользователя
+func (s *gameService) GetUserResults(userID uint, page, pageSize int) ([]*models.GameResult, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+	return s.gameRepo.GetResultsByUserID(userID, offset, pageSize)
+}
+
+// deckOrder перемешивает колоду для текущего круга сессии. В режиме QualityWeighted
+// карточки с лучшей обратной связью чаще оказываются в начале колоды, в режиме
+// PreferUnseen карточки, которых не видел ни один участник (и комната), идут первыми.
+func (s *gameService) deckOrder(session *models.GameSession, actions []*models.Action) (models.IDList, error) {
+	order := shuffleDeck(actions, session.Seed, session.Round)
+	ids := make([]uint, 0, len(actions))
+	for _, action := range actions {
+		ids = append(ids, action.ID)
+	}
+	if session.QualityWeighted {
+		quality, err := s.feedbackRepo.GetQuality(ids)
+		if err != nil {
+			return nil, err
+		}
+		order = weightedShuffleDeck(actions, quality, session.Seed, session.Round)
+	}
+	if !session.PreferUnseen {
+		return order, nil
+	}
+
+	seen, err := s.historyRepo.GetSeenActionIDs(ids, sessionUserIDs(session), session.RoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	unseenFirst := make(models.IDList, 0, len(order))
+	for _, id := range order {
+		if !seen[id] {
+			unseenFirst = append(unseenFirst, id)
+		}
+	}
+	for _, id := range order {
+		if seen[id] {
+			unseenFirst = append(unseenFirst, id)
+		}
+	}
+	return unseenFirst, nil
+}
+
+// prepareRules проверяет правила сессии и заполняет значения по умолчанию.
+// Коллекция штрафов должна быть доступна по visibility.
+func (s *gameService) prepareRules(rules *models.GameRules, visibility models.CollectionVisibility) (models.GameRules, error) {
+	if rules == nil {
+		return models.GameRules{DefaultPoints: DefaultGamePoints}, nil
+	}
+
+	prepared := *rules
+	if prepared.DefaultPoints == 0 {
+		prepared.DefaultPoints = DefaultGamePoints
+	}
+	if prepared.DefaultPoints < 0 || prepared.DefaultPoints > models.MaxPoints {
+		return prepared, fmt.Errorf("%w: defaultPoints must be between 0 and %d", ErrInvalidGameRules, models.MaxPoints)
+	}
+	for actionType, points := range prepared.PointsByType {
+		if !models.IsValidActionType(actionType) {
+			return prepared, fmt.Errorf("%w: unknown card type %s", ErrInvalidGameRules, actionType)
+		}
+		if points < 0 || points > models.MaxPoints {
+			return prepared, fmt.Errorf("%w: points for %s must be between 0 and %d", ErrInvalidGameRules, actionType, models.MaxPoints)
+		}
+	}
+	if prepared.SkipPenalty < 0 || prepared.SkipPenalty > models.MaxPoints {
+		return prepared, fmt.Errorf("%w: skipPenalty must be between 0 and %d", ErrInvalidGameRules, models.MaxPoints)
+	}
+	if prepared.SkipLimit < 0 || prepared.SkipLimit > MaxSkipLimit {
+		return prepared, fmt.Errorf("%w: skipLimit must be between 0 and %d", ErrInvalidGameRules, MaxSkipLimit)
+	}
+	if prepared.PenaltyCollectionID != nil {
+		penaltyCollection, err := s.collectionService.GetByID(*prepared.PenaltyCollectionID)
+		if err != nil || !visibility.Allows(penaltyCollection) {
+			return prepared, fmt.Errorf("%w: penalty collection not found", ErrInvalidGameRules)
+		}
+		if len(penaltyCollection.Actions) == 0 {
+			return prepared, fmt.Errorf("%w: penalty collection has no cards", ErrInvalidGameRules)
+		}
+	}
+	return prepared, nil
+}
+
+// penaltyCard выбирает штрафную карточку для игрока. Карточки типа penalty имеют
+// приоритет; выбор детерминирован от seed сессии и номера хода. Если подходящих
+// карточек нет (например, все требуют второго игрока), возвращается nil.
+func (s *gameService) penaltyCard(session *models.GameSession, player *models.GamePlayer, collectionID uint) (*models.Action, string, error) {
+	actions, err := s.sessionActions(session, collectionID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	players := activePlayers(session)
+	actions = playableActions(actions, len(players))
+
+	candidates := make([]*models.Action, 0, len(actions))
+	for _, action := range actions {
+		if action.Type == models.ActionTypePenalty {
+			candidates = append(candidates, action)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = actions
+	}
+	if len(candidates) == 0 {
+		return nil, "", nil
+	}
+
+	rng := rand.New(rand.NewSource(session.Seed + int64(session.Turn)*104729))
+	penalty := candidates[rng.Intn(len(candidates))]
+
+	names := playerNames(players)
+	if player.LeftAt != nil {
+		names = append(names, player.Name) // Штраф за карточку, пропущенную после выхода игрока
+	}
+	rendered, err := renderAction(penalty, CardRenderContext{
+		Players:       names,
+		CurrentPlayer: player.Name,
+		Rand:          rng,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return penalty, rendered.Text, nil
+}
+
+// sessionActions возвращает карточки коллекции, из которой сессия выдает карточки.
+// Коллекция, скрытая модератором после начала игры, считается ненайденной
+// (кроме сессии, начатой ее владельцем).
+func (s *gameService) sessionActions(session *models.GameSession, collectionID uint) ([]*models.Action, error) {
+	collection, err := s.collectionService.GetByID(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	visibility := models.CollectionVisibility{MaxAgeRating: models.AgeRating18, ViewerID: session.UserID}
+	if !visibility.Allows(collection) {
+		return nil, ErrCollectionNotFound
+	}
+	return collection.Actions, nil
+}
+
+// RemainingCards возвращает количество карточек, оставшихся в текущем круге
+func (s *gameService) RemainingCards(session *models.GameSession) int {
+	drawn := drawnInRound(session)
+	remaining := 0
+	for _, id := range session.DeckOrder {
+		if !drawn[id] {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// CurrentPlayer возвращает игрока, чей сейчас ход; вышедшие игроки пропускаются
+func (s *gameService) CurrentPlayer(session *models.GameSession) *models.GamePlayer {
+	players := activePlayers(session)
+	if len(players) == 0 {
+		return nil
+	}
+	return players[session.Turn%len(players)]
+}
+
+// activePlayers возвращает игроков, которые не вышли из сессии, в порядке хода
+func activePlayers(session *models.GameSession) []*models.GamePlayer {
+	players := make([]*models.GamePlayer, 0, len(session.Players))
+	for _, player := range session.Players {
+		if player.LeftAt == nil {
+			players = append(players, player)
+		}
+	}
+	return players
+}
+
+// playerNames возвращает имена игроков для подстановки в шаблоны карточек
+func playerNames(players []*models.GamePlayer) []string {
+	names := make([]string, 0, len(players))
+	for _, player := range players {
+		names = append(names, player.Name)
+	}
+	return names
+}
+
+// newPlayEvent формирует событие партии для аналитики. Для брошенной сессии
+// временем окончания считается последний ход.
+func newPlayEvent(session *models.GameSession, completed bool) *models.PlayEvent {
+	endedAt := session.UpdatedAt
+	if session.EndedAt != nil {
+		endedAt = *session.EndedAt
+	}
+
+	cardsCompleted := 0
+	for _, draw := range session.Draws {
+		if draw.Outcome == models.DrawCompleted {
+			cardsCompleted++
+		}
+	}
+
+	return &models.PlayEvent{
+		CollectionID:    session.CollectionID,
+		UserID:          session.UserID,
+		SessionID:       session.ID,
+		StartedAt:       session.StartedAt,
+		EndedAt:         endedAt,
+		DurationSeconds: int(endedAt.Sub(session.StartedAt).Seconds()),
+		CardsDrawn:      len(session.Draws),
+		CardsCompleted:  cardsCompleted,
+		Completed:       completed,
+		CreatedAt:       time.Now(),
+	}
+}
+
+// pendingDraw возвращает последний ход, ожидающий выполнения или пропуска, и его игрока
+func pendingDraw(session *models.GameSession) (*models.GameDraw, *models.GamePlayer, error) {
+	if len(session.Draws) == 0 {
+		return nil, nil, ErrNoPendingDraw
+	}
+	draw := session.Draws[len(session.Draws)-1]
+	if draw.Outcome != models.DrawPending {
+		return nil, nil, ErrNoPendingDraw
+	}
+	for _, player := range session.Players {
+		if player.ID == draw.PlayerID {
+			return draw, player, nil
+		}
+	}
+	return nil, nil, ErrNoPendingDraw
+}
+
+// sessionUserIDs возвращает зарегистрированных участников сессии: владельца и привязанных игроков
+func sessionUserIDs(session *models.GameSession) []uint {
+	var ids []uint
+	added := make(map[uint]bool)
+	add := func(id *uint) {
+		if id != nil && !added[*id] {
+			added[*id] = true
+			ids = append(ids, *id)
+		}
+	}
+
+	add(session.UserID)
+	for _, player := range session.Players {
+		add(player.UserID)
+	}
+	return ids
+}
+
+// cardViews формирует записи истории просмотров карточки для всех участников сессии
+func cardViews(session *models.GameSession, action *models.Action, seenAt time.Time) []*models.CardView {
+	newView := func() *models.CardView {
+		return &models.CardView{
+			CollectionID: session.CollectionID,
+			ActionID:     action.ID,
+			TimesSeen:    1,
+			FirstSeenAt:  seenAt,
+			LastSeenAt:   seenAt,
+		}
+	}
+
+	var views []*models.CardView
+	for _, userID := range sessionUserIDs(session) {
+		view := newView()
+		view.UserID = &userID
+		views = append(views, view)
+	}
+	if session.RoomID != nil {
+		view := newView()
+		view.RoomID = session.RoomID
+		views = append(views, view)
+	}
+	return views
+}
+
+// cardPoints определяет очки за выполнение карточки: собственные очки карточки
+// имеют приоритет над очками типа из правил
+func cardPoints(rules models.GameRules, draw *models.GameDraw, action *models.Action) int {
+	if action != nil && action.Points > 0 {
+		return action.Points
+	}
+	if points, ok := rules.PointsByType[draw.Type]; ok {
+		return points
+	}
+	if rules.DefaultPoints == 0 {
+		return DefaultGamePoints // Сессии, созданные до появления правил
+	}
+	return rules.DefaultPoints
+}
+
+// activeSession загружает сессию и проверяет, что ею можно управлять
+func (s *gameService) activeSession(sessionID string, userID *uint) (*models.GameSession, error) {
+	session, err := s.GetByID(sessionID)
+	if err != nil {
+		// Блокировку несуществующей сессии не оставляем, иначе запросы с
+		// произвольными ID копили бы их без ограничений
+		s.release(sessionID)
+		return nil, err
+	}
+	if session.Status != models.GameSessionActive {
+		s.release(sessionID)
+		return nil, ErrGameFinished
+	}
+	// Сессией, начатой авторизованным пользователем, управляет только он
+	if session.UserID != nil && (userID == nil || *userID != *session.UserID) {
+		return nil, ErrNotGameParticipant
+	}
+	return session, nil
+}
+
+// lock захватывает блокировку сессии и возвращает функцию для ее освобождения
+func (s *gameService) lock(sessionID string) func() {
+	value, _ := s.locks.LoadOrStore(sessionID, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// release удаляет блокировку завершенной или несуществующей сессии. Вызывается под
+// блокировкой: сессия больше не станет активной, и запросы, которые успели ее дождаться,
+// получат ErrGameFinished или ErrGameNotFound.
+func (s *gameService) release(sessionID string) {
+	s.locks.Delete(sessionID)
+}
+
+// shuffleDeck перемешивает карточки для указанного круга; результат зависит только от seed и круга
+func shuffleDeck(actions []*models.Action, seed int64, round int) models.IDList {
+	ids := make(models.IDList, 0, len(actions))
+	for _, action := range actions {
+		ids = append(ids, action.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	rng := rand.New(rand.NewSource(seed + int64(round)))
+	rng.Shuffle(len(ids), func(i, j int) {
+		ids[i], ids[j] = ids[j], ids[i]
+	})
+	return ids
+}
+
+// minQualityWeight не дает карточкам с плохой оценкой полностью выпасть из колоды
+const minQualityWeight = 0.1
+
+// weightedShuffleDeck перемешивает карточки так, что вероятность оказаться раньше
+// пропорциональна оценке качества (взвешенная выборка без возвращения по Эфраимидису-Спиракису).
+// Как и shuffleDeck, результат зависит только от seed, круга и оценок.
+func weightedShuffleDeck(actions []*models.Action, quality map[uint]*models.CardQuality, seed int64, round int) models.IDList {
+	sorted := make([]*models.Action, len(actions))
+	copy(sorted, actions)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	rng := rand.New(rand.NewSource(seed + int64(round)))
+	keys := make(map[uint]float64, len(sorted))
+	for _, action := range sorted {
+		weight := minQualityWeight
+		if q, ok := quality[action.ID]; ok {
+			weight += q.Score()
+		} else {
+			weight += 0.5
+		}
+		keys[action.ID] = math.Pow(rng.Float64(), 1/weight)
+	}
+
+	ids := make(models.IDList, 0, len(sorted))
+	for _, action := range sorted {
+		ids = append(ids, action.ID)
+	}
+	sort.SliceStable(ids, func(i, j int) bool { return keys[ids[i]] > keys[ids[j]] })
+	return ids
+}
+
+// drawnInRound возвращает карточки, уже вытянутые в текущем круге
+func drawnInRound(session *models.GameSession) map[uint]bool {
+	drawn := make(map[uint]bool, len(session.Draws))
+	for _, draw := range session.Draws {
+		if draw.Round == session.Round {
+			drawn[draw.ActionID] = true
+		}
+	}
+	return drawn
+}
+
+// nextCard возвращает первую невытянутую в текущем круге карточку нужного типа
+func nextCard(session *models.GameSession, byID map[uint]*models.Action, actionType models.ActionType) *models.Action {
+	drawn := drawnInRound(session)
+	for _, id := range session.DeckOrder {
+		action, ok := byID[id]
+		if !ok || drawn[id] {
+			continue // Карточка удалена из коллекции или уже была
+		}
+		if actionType == "" || action.Type == actionType {
+			return action
+		}
+	}
+	return nil
+}
+
+// playableActions убирает карточки, которые нельзя разыграть при playerCount игроках:
+// подстановке {other} в тексте или дополнительных полях нужен второй игрок
+func playableActions(actions []*models.Action, playerCount int) []*models.Action {
+	if playerCount >= 2 {
+		return actions
+	}
+	playable := make([]*models.Action, 0, len(actions))
+	for _, action := range actions {
+		if !actionNeedsOtherPlayer(action) {
+			playable = append(playable, action)
+		}
+	}
+	return playable
+}
+
+// actionNeedsOtherPlayer сообщает, использует ли карточка подстановку {other}
+func actionNeedsOtherPlayer(action *models.Action) bool {
+	if needsOtherPlayer(action.Text) {
+		return true
+	}
+	for _, value := range action.Extra {
+		if needsOtherPlayer(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCardOfType проверяет, есть ли в коллекции карточки указанного типа
+func hasCardOfType(actions []*models.Action, actionType models.ActionType) bool {
+	for _, action := range actions {
+		if actionType == "" || action.Type == actionType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,350 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+// testActions создает карточки с ID от 1 до n
+func testActions(n int) []*models.Action {
+	actions := make([]*models.Action, 0, n)
+	for i := 1; i <= n; i++ {
+		actions = append(actions, &models.Action{ID: uint(i), Type: models.ActionTypeTruth, Text: "Карточка"})
+	}
+	return actions
+}
+
+// sameIDs проверяет, что списки совпадают поэлементно
+func sameIDs(a, b models.IDList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isPermutation проверяет, что order содержит каждую карточку ровно один раз
+func isPermutation(order models.IDList, actions []*models.Action) bool {
+	if len(order) != len(actions) {
+		return false
+	}
+	got := make([]uint, len(order))
+	copy(got, order)
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	want := make([]uint, 0, len(actions))
+	for _, action := range actions {
+		want = append(want, action.ID)
+	}
+	sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+	for i := range want {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestWeightedShuffleDeck(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []*models.Action
+		quality map[uint]*models.CardQuality
+	}{
+		{name: "empty deck", actions: nil},
+		{name: "single card", actions: testActions(1)},
+		{name: "no feedback", actions: testActions(20)},
+		{
+			name:    "partial feedback",
+			actions: testActions(20),
+			quality: map[uint]*models.CardQuality{
+				1: {ActionID: 1, Upvotes: 10},
+				5: {ActionID: 5, Downvotes: 10, Skipped: 10},
+			},
+		},
+		{
+			name:    "worst feedback keeps cards in the deck",
+			actions: testActions(5),
+			quality: map[uint]*models.CardQuality{
+				1: {ActionID: 1, Downvotes: 1000, Skipped: 1000},
+				2: {ActionID: 2, Downvotes: 1000, Skipped: 1000},
+				3: {ActionID: 3, Downvotes: 1000, Skipped: 1000},
+				4: {ActionID: 4, Downvotes: 1000, Skipped: 1000},
+				5: {ActionID: 5, Downvotes: 1000, Skipped: 1000},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := weightedShuffleDeck(tt.actions, tt.quality, 99, 0)
+			if !isPermutation(order, tt.actions) {
+				t.Fatalf("weightedShuffleDeck() = %v, want every card exactly once", order)
+			}
+
+			// Результат зависит только от seed, круга и оценок, но не от порядка карточек
+			reversed := make([]*models.Action, len(tt.actions))
+			for i, action := range tt.actions {
+				reversed[len(tt.actions)-1-i] = action
+			}
+			if again := weightedShuffleDeck(reversed, tt.quality, 99, 0); !sameIDs(order, again) {
+				t.Errorf("input order changed the deck: %v, then %v", order, again)
+			}
+		})
+	}
+}
+
+func TestWeightedShuffleDeckRoundsDiffer(t *testing.T) {
+	actions := testActions(20)
+	first := weightedShuffleDeck(actions, nil, 7, 0)
+	second := weightedShuffleDeck(actions, nil, 7, 1)
+	if sameIDs(first, second) {
+		t.Errorf("rounds 0 and 1 produced the same deck %v", first)
+	}
+}
+
+func TestWeightedShuffleDeckPrefersBetterCards(t *testing.T) {
+	actions := testActions(10)
+	quality := map[uint]*models.CardQuality{
+		1: {ActionID: 1, Upvotes: 50, Completed: 50},
+		2: {ActionID: 2, Downvotes: 50, Skipped: 50},
+	}
+
+	const seeds = 500
+	positions := make(map[uint]int)
+	for seed := int64(1); seed <= seeds; seed++ {
+		for i, id := range weightedShuffleDeck(actions, quality, seed, 0) {
+			positions[id] += i
+		}
+	}
+
+	best := float64(positions[1]) / seeds
+	neutral := float64(positions[5]) / seeds
+	worst := float64(positions[2]) / seeds
+	if !(best < neutral && neutral < worst) {
+		t.Errorf("average positions: best %.2f, neutral %.2f, worst %.2f; want best < neutral < worst", best, neutral, worst)
+	}
+}
+
+// fakeGameRepo хранит сессии в памяти; остальные методы не используются
+type fakeGameRepo struct {
+	repository.GameRepository
+	sessions map[string]*models.GameSession
+}
+
+func (r *fakeGameRepo) GetByID(id string) (*models.GameSession, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return session, nil
+}
+
+func (r *fakeGameRepo) SaveDraw(session *models.GameSession, draw *models.GameDraw, views []*models.CardView) error {
+	return nil
+}
+
+func (r *fakeGameRepo) ResolveDraw(draw *models.GameDraw, player *models.GamePlayer) error {
+	return nil
+}
+
+// fakeGameCollections отдает одну коллекцию с карточками; остальные методы не используются
+type fakeGameCollections struct {
+	CollectionService
+	collection *models.Collection
+}
+
+func (s *fakeGameCollections) GetByID(id uint) (*models.Collection, error) {
+	if id != s.collection.ID {
+		return nil, ErrCollectionNotFound
+	}
+	return s.collection, nil
+}
+
+func (s *fakeGameCollections) GetActions(collectionID uint) ([]*models.Action, error) {
+	return s.collection.Actions, nil
+}
+
+// fakeHistoryRepo отмечает просмотренными карточки из seen и запоминает запрошенные ID
+type fakeHistoryRepo struct {
+	repository.HistoryRepository
+	seen      map[uint]bool
+	requested []uint
+}
+
+func (r *fakeHistoryRepo) GetSeenActionIDs(actionIDs []uint, userIDs []uint, roomID *uint) (map[uint]bool, error) {
+	r.requested = actionIDs
+	result := make(map[uint]bool)
+	for _, id := range actionIDs {
+		if r.seen[id] {
+			result[id] = true
+		}
+	}
+	return result, nil
+}
+
+// newTestGame создает сервис и активную сессию с игроками players по карточкам actions
+func newTestGame(seed int64, actions []*models.Action, players ...string) (*gameService, *models.GameSession) {
+	session := &models.GameSession{
+		ID:           "session",
+		CollectionID: 1,
+		Status:       models.GameSessionActive,
+		Seed:         seed,
+		Rules:        models.GameRules{DefaultPoints: DefaultGamePoints},
+	}
+	for i, name := range players {
+		session.Players = append(session.Players, &models.GamePlayer{ID: uint(i + 1), SessionID: session.ID, Name: name, Position: i})
+	}
+	service := &gameService{
+		gameRepo:          &fakeGameRepo{sessions: map[string]*models.GameSession{session.ID: session}},
+		collectionService: &fakeGameCollections{collection: &models.Collection{ID: 1, Actions: actions}},
+		newSeed:           newSeed,
+	}
+	return service, session
+}
+
+// drawAll вытягивает и выполняет n карточек подряд, возвращая их ID
+func drawAll(t *testing.T, service *gameService, session *models.GameSession, n int) []uint {
+	t.Helper()
+	ids := make([]uint, 0, n)
+	for i := 0; i < n; i++ {
+		result, err := service.Draw(session.ID, nil, "")
+		if err != nil {
+			t.Fatalf("draw %d: unexpected error: %v", i+1, err)
+		}
+		if _, err := service.Complete(session.ID, nil); err != nil {
+			t.Fatalf("complete %d: unexpected error: %v", i+1, err)
+		}
+		ids = append(ids, result.Action.ID)
+	}
+	return ids
+}
+
+func TestGameServiceDrawOrderIsSeeded(t *testing.T) {
+	const cards = 8
+	play := func(seed int64) []uint {
+		service, session := newTestGame(seed, testActions(cards), "Аня", "Борис")
+		return drawAll(t, service, session, 2*cards)
+	}
+
+	first := play(2024)
+	if second := play(2024); !sameIDs(first, second) {
+		t.Fatalf("same seed drew %v, then %v", first, second)
+	}
+	if other := play(2025); sameIDs(first, other) {
+		t.Errorf("seeds 2024 and 2025 drew the same cards %v", first)
+	}
+
+	// Каждый круг содержит каждую карточку ровно один раз
+	actions := testActions(cards)
+	for round := 0; round < 2; round++ {
+		if !isPermutation(first[round*cards:(round+1)*cards], actions) {
+			t.Errorf("round %d drew %v, want every card exactly once", round+1, first[round*cards:(round+1)*cards])
+		}
+	}
+}
+
+func TestGameServiceDrawTurns(t *testing.T) {
+	service, session := newTestGame(1, testActions(3), "Аня", "Борис", "Вика")
+	var got []string
+	for i := 0; i < 4; i++ {
+		result, err := service.Draw(session.ID, nil, "")
+		if err != nil {
+			t.Fatalf("draw %d: unexpected error: %v", i+1, err)
+		}
+		if _, err := service.Skip(session.ID, nil); err != nil {
+			t.Fatalf("skip %d: unexpected error: %v", i+1, err)
+		}
+		got = append(got, result.Player.Name)
+	}
+	want := []string{"Аня", "Борис", "Вика", "Аня"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("turns went %v, want %v", got, want)
+		}
+	}
+}
+
+func TestGameServiceDrawRejectsPendingCard(t *testing.T) {
+	service, session := newTestGame(1, testActions(3), "Аня")
+	if _, err := service.Draw(session.ID, nil, ""); err != nil {
+		t.Fatalf("first draw: unexpected error: %v", err)
+	}
+	if _, err := service.Draw(session.ID, nil, ""); !errors.Is(err, ErrDrawPending) {
+		t.Fatalf("second draw error = %v, want %v", err, ErrDrawPending)
+	}
+}
+
+func TestGameServiceSinglePlayerSkipsOtherCards(t *testing.T) {
+	actions := testActions(4)
+	actions[1].Text = "Обними {other}"
+	actions[3].Extra = models.ActionExtra{"hint": "{other} выбирает"}
+
+	service, session := newTestGame(5, actions, "Аня")
+	// Два круга по две разыгрываемые карточки: сессия не застревает на карточках с {other}
+	for _, id := range drawAll(t, service, session, 4) {
+		if id == 2 || id == 4 {
+			t.Fatalf("single player drew card %d that needs another player", id)
+		}
+	}
+
+	onlyOther := testActions(1)
+	onlyOther[0].Text = "Поцелуй {other}"
+	service, session = newTestGame(5, onlyOther, "Аня")
+	if _, err := service.Draw(session.ID, nil, ""); !errors.Is(err, ErrNoCardsAvailable) {
+		t.Fatalf("draw error = %v, want %v", err, ErrNoCardsAvailable)
+	}
+}
+
+func TestGameServiceDeckOrderPrefersUnseen(t *testing.T) {
+	actions := testActions(10)
+	history := &fakeHistoryRepo{seen: map[uint]bool{2: true, 3: true, 7: true}}
+	service := &gameService{historyRepo: history}
+	userID := uint(1)
+	session := &models.GameSession{Seed: 11, Round: 1, UserID: &userID, PreferUnseen: true}
+
+	order, err := service.deckOrder(session, actions)
+	if err != nil {
+		t.Fatalf("deckOrder() unexpected error: %v", err)
+	}
+	if !isPermutation(order, actions) {
+		t.Fatalf("deckOrder() = %v, want every card exactly once", order)
+	}
+	if !isPermutation(history.requested, actions) {
+		t.Errorf("seen cards requested for %v, want the deck's cards", history.requested)
+	}
+
+	// Непросмотренные карточки идут первыми в порядке обычного перемешивания
+	shuffled := shuffleDeck(actions, session.Seed, session.Round)
+	var wantUnseen, wantSeen models.IDList
+	for _, id := range shuffled {
+		if history.seen[id] {
+			wantSeen = append(wantSeen, id)
+		} else {
+			wantUnseen = append(wantUnseen, id)
+		}
+	}
+	if want := append(wantUnseen, wantSeen...); !sameIDs(order, want) {
+		t.Errorf("deckOrder() = %v, want %v", order, want)
+	}
+}
+
+func TestGameServiceUnknownSessionReleasesLock(t *testing.T) {
+	service, _ := newTestGame(1, testActions(3), "Аня")
+	for _, id := range []string{"missing-1", "missing-2"} {
+		if _, err := service.Draw(id, nil, ""); !errors.Is(err, ErrGameNotFound) {
+			t.Fatalf("draw %s error = %v, want %v", id, err, ErrGameNotFound)
+		}
+	}
+	service.locks.Range(func(key, value any) bool {
+		t.Errorf("lock for %v is kept after a failed lookup", key)
+		return true
+	})
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+// CollectionHistorySummary представляет сводку того, что пользователь видел в коллекции
+type CollectionHistorySummary struct {
+	CollectionID uint
+	Collection   *models.Collection // nil, если коллекция удалена
+	SeenCount    int
+	TotalCards   int
+	LastSeenAt   time.Time
+}
+
+// SeenCard представляет карточку из истории пользователя
+type SeenCard struct {
+	View   *models.CardView
+	Action *models.Action // nil, если карточка удалена из коллекции
+}
+
+// HistoryService определяет методы сервиса истории просмотров карточек
+type HistoryService interface {
+	GetSummary(userID uint, page, pageSize int) ([]*CollectionHistorySummary, int64, error)
+	GetCollectionHistory(userID, collectionID uint) ([]*SeenCard, error)
+	Reset(userID uint, collectionID *uint) (int64, error)
+}
+
+// historyService реализует интерфейс HistoryService
+type historyService struct {
+	historyRepo       repository.HistoryRepository
+	collectionService CollectionService
+}
+
+// NewHistoryService создает новый экземпляр сервиса истории просмотров
+func NewHistoryService(historyRepo repository.HistoryRepository, collectionService CollectionService) HistoryService {
+	return &historyService{
+		historyRepo:       historyRepo,
+		collectionService: collectionService,
+	}
+}
+
+// GetSummary возвращает коллекции, в которых пользователь видел карточки, начиная с последних
+func (s *historyService) GetSummary(userID uint, page, pageSize int) ([]*CollectionHistorySummary, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	rows, total, err := s.historyRepo.GetUserSummary(userID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	summary := make([]*CollectionHistorySummary, 0, len(rows))
+	for _, row := range rows {
+		item := &CollectionHistorySummary{
+			CollectionID: row.CollectionID,
+			SeenCount:    row.SeenCount,
+			LastSeenAt:   row.LastSeenAt,
+		}
+		if collection, err := s.collectionService.GetByID(row.CollectionID); err == nil {
+			item.Collection = collection
+			item.TotalCards = len(collection.Actions)
+		}
+		summary = append(summary, item)
+	}
+	return summary, total, nil
+}
+
+// GetCollectionHistory возвращает карточки коллекции, которые видел пользователь
+func (s *historyService) GetCollectionHistory(userID, collectionID uint) ([]*SeenCard, error) {
+	views, err := s.historyRepo.GetUserViews(userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*models.Action)
+	if actions, err := s.collectionService.GetActions(collectionID); err == nil {
+		for _, action := range actions {
+			byID[action.ID] = action
+		}
+	}
+
+	cards := make([]*SeenCard, 0, len(views))
+	for _, view := range views {
+		cards = append(cards, &SeenCard{View: view, Action: byID[view.ActionID]})
+	}
+	return cards, nil
+}
+
+// Reset очищает историю пользователя целиком или по одной коллекции
+func (s *historyService) Reset(userID uint, collectionID *uint) (int64, error) {
+	return s.historyRepo.DeleteUserViews(userID, collectionID)
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+// CollectionReaction представляет отметки пользователя на коллекции и ее счетчики
+type CollectionReaction struct {
+	CollectionID  uint
+	Liked         bool
+	Favorited     bool
+	LikeCount     int
+	FavoriteCount int
+}
+
+// LikeService определяет методы сервиса лайков и избранного.
+// Все операции идемпотентны: повторный лайк или удаление отсутствующего не меняют счетчики.
+type LikeService interface {
+	Like(userID, collectionID uint) (*CollectionReaction, error)
+	Unlike(userID, collectionID uint) (*CollectionReaction, error)
+	Favorite(userID, collectionID uint) (*CollectionReaction, error)
+	Unfavorite(userID, collectionID uint) (*CollectionReaction, error)
+	GetFavorites(userID uint, page, pageSize int) ([]*models.Collection, int64, error)
+	GetReactions(userID uint, collectionIDs []uint) (map[uint]*CollectionReaction, error)
+}
+
+// likeService реализует интерфейс LikeService
+type likeService struct {
+	likeRepo            repository.LikeRepository
+	collectionRepo      repository.CollectionRepository
+	notificationService NotificationService
+}
+
+// NewLikeService создает новый экземпляр сервиса лайков и избранного
+func NewLikeService(likeRepo repository.LikeRepository, collectionRepo repository.CollectionRepository, notificationService NotificationService) LikeService {
+	return &likeService{
+		likeRepo:            likeRepo,
+		collectionRepo:      collectionRepo,
+		notificationService: notificationService,
+	}
+}
+
+// Like ставит лайк коллекции и уведомляет автора о новом лайке
+func (s *likeService) Like(userID, collectionID uint) (*CollectionReaction, error) {
+	return s.apply(userID, collectionID, s.likeRepo.Like, models.NotificationLike)
+}
+
+// Unlike снимает лайк с коллекции
+func (s *likeService) Unlike(userID, collectionID uint) (*CollectionReaction, error) {
+	return s.apply(userID, collectionID, s.likeRepo.Unlike, "")
+}
+
+// Favorite добавляет коллекцию в избранное
+func (s *likeService) Favorite(userID, collectionID uint) (*CollectionReaction, error) {
+	return s.apply(userID, collectionID, s.likeRepo.Favorite, "")
+}
+
+// Unfavorite убирает коллекцию из избранного
+func (s *likeService) Unfavorite(userID, collectionID uint) (*CollectionReaction, error) {
+	return s.apply(userID, collectionID, s.likeRepo.Unfavorite, "")
+}
+
+// GetFavorites возвращает избранные коллекции пользователя
+func (s *likeService) GetFavorites(userID uint, page, pageSize int) ([]*models.Collection, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return s.likeRepo.GetFavorites(userID, (page-1)*pageSize, pageSize)
+}
+
+// GetReactions возвращает отметки пользователя для списка коллекций одним запросом на каждый тип
+func (s *likeService) GetReactions(userID uint, collectionIDs []uint) (map[uint]*CollectionReaction, error) {
+	liked, err := s.likeRepo.GetLikedCollectionIDs(userID, collectionIDs)
+	if err != nil {
+		return nil, err
+	}
+	favorited, err := s.likeRepo.GetFavoritedCollectionIDs(userID, collectionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	reactions := make(map[uint]*CollectionReaction, len(collectionIDs))
+	for _, id := range collectionIDs {
+		reactions[id] = &CollectionReaction{
+			CollectionID: id,
+			Liked:        liked[id],
+			Favorited:    favorited[id],
+		}
+	}
+	return reactions, nil
+}
+
+// apply выполняет операцию над отметкой и возвращает актуальное состояние коллекции.
+// Если операция изменила отметку и задан notify, автор коллекции получает уведомление.
+func (s *likeService) apply(userID, collectionID uint, operation func(userID, collectionID uint) (bool, error), notify models.NotificationType) (*CollectionReaction, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	target, err := s.collectionRepo.GetByID(collectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+
+	changed, err := operation(userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if changed && notify != "" {
+		s.notificationService.Notify(NotificationEvent{
+			Type:         notify,
+			RecipientID:  target.UserID,
+			ActorID:      userID,
+			CollectionID: &target.ID,
+		})
+	}
+
+	// Перечитываем коллекцию, чтобы вернуть счетчики с учетом параллельных изменений
+	collection, err := s.collectionRepo.GetByID(collectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+	reactions, err := s.GetReactions(userID, []uint{collectionID})
+	if err != nil {
+		return nil, err
+	}
+
+	reaction := reactions[collectionID]
+	reaction.LikeCount = collection.LikeCount
+	reaction.FavoriteCount = collection.FavoriteCount
+	return reaction, nil
+}
@@ -0,0 +1,401 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+var (
+	ErrNotModerator          = errors.New("user is not a moderator")
+	ErrReportNotFound        = errors.New("report not found")
+	ErrInvalidReport         = errors.New("invalid report")
+	ErrAlreadyReported       = errors.New("you have already reported this")
+	ErrReportRateLimited     = errors.New("too many reports, try again later")
+	ErrReportClaimed         = errors.New("report is handled by another moderator or already resolved")
+	ErrReportNotClaimed      = errors.New("claim the report before resolving it")
+	ErrInvalidModeration     = errors.New("moderation action is not applicable to this report")
+	ErrReportTargetNotFound  = errors.New("reported content not found")
+	ErrInvalidModerationList = errors.New("invalid moderation filter")
+	ErrCollectionNotHidden   = errors.New("collection is not hidden")
+)
+
+const (
+	// MaxReportDetailsLength ограничивает длину пояснения к жалобе и заметки модератора
+	MaxReportDetailsLength = 1000
+	// DefaultSuspendDays - срок блокировки, если модератор его не указал
+	DefaultSuspendDays = 7
+	// MaxSuspendDays ограничивает срок блокировки
+	MaxSuspendDays = 365
+
+	// Не больше reportRateLimit жалоб за reportRateWindow от одного пользователя
+	reportRateLimit  = 10
+	reportRateWindow = time.Hour
+)
+
+// ResolveReportInput описывает решение модератора по жалобе
+type ResolveReportInput struct {
+	Action      models.ModerationAction
+	Note        string
+	SuspendDays int // Для suspend; 0 - DefaultSuspendDays
+}
+
+// ModerationService определяет методы сервиса жалоб и модерации
+type ModerationService interface {
+	Report(reporterID uint, targetType models.ReportTargetType, targetID uint, reason models.ReportReason, details string) (*models.Report, error)
+	ListQueue(moderatorID uint, filter repository.ReportFilter, page, pageSize int) ([]*models.Report, int64, error)
+	Claim(moderatorID, reportID uint) (*models.Report, error)
+	Release(moderatorID, reportID uint) (*models.Report, error)
+	Resolve(moderatorID, reportID uint, input ResolveReportInput) (*models.Report, error)
+	ListAuditLog(moderatorID uint, filter repository.ModerationLogFilter, page, pageSize int) ([]*models.ModerationLog, int64, error)
+	// UnhideCollection отменяет решение hide, например после обжалования автором
+	UnhideCollection(moderatorID, collectionID uint, note string) error
+}
+
+// moderationService реализует интерфейс ModerationService
+type moderationService struct {
+	moderationRepo repository.ModerationRepository
+	userRepo       repository.UserRepository
+	collectionRepo repository.CollectionRepository
+	actionRepo     repository.ActionRepository
+	commentRepo    repository.CommentRepository
+	limiter        *rateLimiter
+}
+
+// NewModerationService создает новый экземпляр сервиса модерации
+func NewModerationService(
+	moderationRepo repository.ModerationRepository,
+	userRepo repository.UserRepository,
+	collectionRepo repository.CollectionRepository,
+	actionRepo repository.ActionRepository,
+	commentRepo repository.CommentRepository,
+) ModerationService {
+	return &moderationService{
+		moderationRepo: moderationRepo,
+		userRepo:       userRepo,
+		collectionRepo: collectionRepo,
+		actionRepo:     actionRepo,
+		commentRepo:    commentRepo,
+		limiter:        newRateLimiter(reportRateLimit, reportRateWindow),
+	}
+}
+
+// Report создает жалобу на коллекцию, карточку, комментарий или пользователя
+func (s *moderationService) Report(reporterID uint, targetType models.ReportTargetType, targetID uint, reason models.ReportReason, details string) (*models.Report, error) {
+	if reporterID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if !reason.IsValid() {
+		return nil, ErrInvalidReport
+	}
+	details = strings.TrimSpace(details)
+	if utf8.RuneCountInString(details) > MaxReportDetailsLength {
+		return nil, ErrInvalidReport
+	}
+
+	targetUserID, err := s.targetAuthor(targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if targetUserID == reporterID {
+		return nil, ErrInvalidReport
+	}
+
+	exists, err := s.moderationRepo.HasUnresolvedReport(&reporterID, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrAlreadyReported
+	}
+	if !s.limiter.Allow(reporterID) {
+		return nil, ErrReportRateLimited
+	}
+
+	report := &models.Report{
+		ReporterID:   &reporterID,
+		TargetType:   targetType,
+		TargetID:     targetID,
+		TargetUserID: &targetUserID,
+		Reason:       reason,
+		Details:      details,
+		Status:       models.ReportOpen,
+	}
+	if err := s.moderationRepo.CreateReport(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ListQueue возвращает очередь жалоб для модератора
+func (s *moderationService) ListQueue(moderatorID uint, filter repository.ReportFilter, page, pageSize int) ([]*models.Report, int64, error) {
+	if err := s.requireModerator(moderatorID); err != nil {
+		return nil, 0, err
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, 0, ErrInvalidModerationList
+	}
+	if filter.TargetType != "" && !isReportTarget(filter.TargetType) {
+		return nil, 0, ErrInvalidModerationList
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return s.moderationRepo.ListReports(filter, (page-1)*pageSize, pageSize)
+}
+
+// Claim берет жалобу в работу, чтобы два модератора не разбирали ее одновременно
+func (s *moderationService) Claim(moderatorID, reportID uint) (*models.Report, error) {
+	if err := s.requireModerator(moderatorID); err != nil {
+		return nil, err
+	}
+	report, err := s.getReport(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed, err := s.moderationRepo.Claim(reportID, moderatorID, newModerationLog(moderatorID, report, models.ModerationClaim, ""))
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrReportClaimed
+	}
+	return s.getReport(reportID)
+}
+
+// Release возвращает жалобу в общую очередь
+func (s *moderationService) Release(moderatorID, reportID uint) (*models.Report, error) {
+	if err := s.requireModerator(moderatorID); err != nil {
+		return nil, err
+	}
+	report, err := s.getReport(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	released, err := s.moderationRepo.Release(reportID, moderatorID, newModerationLog(moderatorID, report, models.ModerationRelease, ""))
+	if err != nil {
+		return nil, err
+	}
+	if !released {
+		return nil, ErrReportNotClaimed
+	}
+	return s.getReport(reportID)
+}
+
+// Resolve применяет решение модератора к объекту жалобы и закрывает все жалобы на него
+func (s *moderationService) Resolve(moderatorID, reportID uint, input ResolveReportInput) (*models.Report, error) {
+	if err := s.requireModerator(moderatorID); err != nil {
+		return nil, err
+	}
+	report, err := s.getReport(reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != models.ReportClaimed || report.AssigneeID == nil || *report.AssigneeID != moderatorID {
+		return nil, ErrReportNotClaimed
+	}
+	if !input.Action.IsResolution() {
+		return nil, ErrInvalidModeration
+	}
+	note := strings.TrimSpace(input.Note)
+	if utf8.RuneCountInString(note) > MaxReportDetailsLength {
+		return nil, ErrInvalidModeration
+	}
+
+	now := time.Now()
+	entry := newModerationLog(moderatorID, report, input.Action, note)
+	if err := s.checkResolution(report, input, now, entry); err != nil {
+		return nil, err
+	}
+
+	report.Status = models.ReportResolved
+	report.Resolution = input.Action
+	report.ResolutionNote = note
+	report.ResolvedByID = &moderatorID
+	report.ResolvedAt = &now
+	// Решение применяется вместе с закрытием жалобы, только если она все еще назначена модератору
+	resolved, err := s.moderationRepo.Resolve(report, entry)
+	if err != nil {
+		return nil, err
+	}
+	if !resolved {
+		return nil, ErrReportNotClaimed
+	}
+	return report, nil
+}
+
+// ListAuditLog возвращает журнал решений модераторов
+func (s *moderationService) ListAuditLog(moderatorID uint, filter repository.ModerationLogFilter, page, pageSize int) ([]*models.ModerationLog, int64, error) {
+	if err := s.requireModerator(moderatorID); err != nil {
+		return nil, 0, err
+	}
+	if filter.TargetType != "" && !isReportTarget(filter.TargetType) {
+		return nil, 0, ErrInvalidModerationList
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return s.moderationRepo.ListLogs(filter, (page-1)*pageSize, pageSize)
+}
+
+// UnhideCollection возвращает скрытую модератором коллекцию в публичный доступ
+func (s *moderationService) UnhideCollection(moderatorID, collectionID uint, note string) error {
+	if err := s.requireModerator(moderatorID); err != nil {
+		return err
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > MaxReportDetailsLength {
+		return ErrInvalidModeration
+	}
+	collection, err := s.collectionRepo.GetByID(collectionID)
+	if err != nil {
+		return ErrReportTargetNotFound
+	}
+
+	unhidden, err := s.moderationRepo.UnhideCollection(collectionID, &models.ModerationLog{
+		ModeratorID:  moderatorID,
+		Action:       models.ModerationUnhide,
+		TargetType:   models.ReportTargetCollection,
+		TargetID:     collectionID,
+		TargetUserID: &collection.UserID,
+		Note:         note,
+	})
+	if err != nil {
+		return err
+	}
+	if !unhidden {
+		return ErrCollectionNotHidden
+	}
+	return nil
+}
+
+// checkResolution проверяет, что решение применимо к объекту жалобы, и дополняет
+// запись журнала. Само решение применяет moderationRepo.Resolve.
+func (s *moderationService) checkResolution(report *models.Report, input ResolveReportInput, now time.Time, entry *models.ModerationLog) error {
+	switch input.Action {
+	case models.ModerationDismiss:
+		return nil
+	case models.ModerationWarn, models.ModerationSuspend:
+		if report.TargetUserID == nil {
+			return ErrInvalidModeration
+		}
+		if _, err := s.userRepo.GetByID(*report.TargetUserID); err != nil {
+			return ErrReportTargetNotFound
+		}
+		if input.Action == models.ModerationWarn {
+			return nil
+		}
+
+		days := input.SuspendDays
+		if days == 0 {
+			days = DefaultSuspendDays
+		}
+		if days < 0 || days > MaxSuspendDays {
+			return ErrInvalidModeration
+		}
+		until := now.AddDate(0, 0, days)
+		entry.SuspendUntil = &until
+		return nil
+	}
+
+	// hide и delete применяются к контенту, а не к пользователю
+	switch report.TargetType {
+	case models.ReportTargetCollection, models.ReportTargetAction:
+		return nil
+	case models.ReportTargetComment:
+		if _, err := s.commentRepo.GetByID(report.TargetID); err != nil {
+			return ErrReportTargetNotFound
+		}
+		return nil
+	}
+	return ErrInvalidModeration
+}
+
+// targetAuthor проверяет существование объекта жалобы и возвращает ID его автора
+func (s *moderationService) targetAuthor(targetType models.ReportTargetType, targetID uint) (uint, error) {
+	switch targetType {
+	case models.ReportTargetCollection:
+		collection, err := s.collectionRepo.GetByID(targetID)
+		if err != nil {
+			return 0, ErrReportTargetNotFound
+		}
+		return collection.UserID, nil
+	case models.ReportTargetAction:
+		action, err := s.actionRepo.GetByID(targetID)
+		if err != nil {
+			return 0, ErrReportTargetNotFound
+		}
+		collection, err := s.collectionRepo.GetByID(action.CollectionID)
+		if err != nil {
+			return 0, ErrReportTargetNotFound
+		}
+		return collection.UserID, nil
+	case models.ReportTargetComment:
+		comment, err := s.commentRepo.GetByID(targetID)
+		if err != nil || comment.IsRemoved() {
+			return 0, ErrReportTargetNotFound
+		}
+		return comment.UserID, nil
+	case models.ReportTargetUser:
+		user, err := s.userRepo.GetByID(targetID)
+		if err != nil {
+			return 0, ErrReportTargetNotFound
+		}
+		return user.ID, nil
+	}
+	return 0, ErrInvalidReport
+}
+
+// requireModerator проверяет, что пользователь может разбирать жалобы
+func (s *moderationService) requireModerator(userID uint) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || !user.IsModerator() {
+		return ErrNotModerator
+	}
+	return nil
+}
+
+// getReport возвращает жалобу или ErrReportNotFound
+func (s *moderationService) getReport(reportID uint) (*models.Report, error) {
+	report, err := s.moderationRepo.GetReport(reportID)
+	if err != nil {
+		return nil, ErrReportNotFound
+	}
+	return report, nil
+}
+
+// isReportTarget проверяет, что на объекты этого типа можно жаловаться
+func isReportTarget(targetType models.ReportTargetType) bool {
+	switch targetType {
+	case models.ReportTargetCollection, models.ReportTargetAction, models.ReportTargetComment, models.ReportTargetUser:
+		return true
+	}
+	return false
+}
+
+// newModerationLog создает запись журнала о действии модератора по жалобе
+func newModerationLog(moderatorID uint, report *models.Report, action models.ModerationAction, note string) *models.ModerationLog {
+	return &models.ModerationLog{
+		ModeratorID:  moderatorID,
+		ReportID:     &report.ID,
+		Action:       action,
+		TargetType:   report.TargetType,
+		TargetID:     report.TargetID,
+		TargetUserID: report.TargetUserID,
+		Note:         note,
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+// fakeModerationUsers отдает пользователей из памяти; остальные методы не используются
+type fakeModerationUsers struct {
+	repository.UserRepository
+	users map[uint]*models.User
+}
+
+func (r *fakeModerationUsers) GetByID(id uint) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return user, nil
+}
+
+// fakeModerationRepo отдает одну жалобу и запоминает решения, которые дошли до Resolve.
+// Жалоба закрывается, только если claimed; так изображается жалоба, которую
+// между проверкой и закрытием забрал другой модератор.
+type fakeModerationRepo struct {
+	repository.ModerationRepository
+	report   *models.Report
+	claimed  bool
+	resolved []*models.ModerationLog
+}
+
+func (r *fakeModerationRepo) GetReport(id uint) (*models.Report, error) {
+	if id != r.report.ID {
+		return nil, errors.New("record not found")
+	}
+	copied := *r.report
+	return &copied, nil
+}
+
+func (r *fakeModerationRepo) Resolve(report *models.Report, entry *models.ModerationLog) (bool, error) {
+	if !r.claimed {
+		return false, nil
+	}
+	r.resolved = append(r.resolved, entry)
+	return true, nil
+}
+
+func TestModerationServiceResolve(t *testing.T) {
+	const moderatorID, authorID = 1, 2
+	assignee := uint(moderatorID)
+	author := uint(authorID)
+
+	tests := []struct {
+		name        string
+		claimed     bool
+		input       ResolveReportInput
+		wantErr     error
+		wantSuspend bool
+	}{
+		{name: "warn", claimed: true, input: ResolveReportInput{Action: models.ModerationWarn}},
+		{name: "suspend", claimed: true, input: ResolveReportInput{Action: models.ModerationSuspend, SuspendDays: 3}, wantSuspend: true},
+		{name: "suspend too long", claimed: true, input: ResolveReportInput{Action: models.ModerationSuspend, SuspendDays: MaxSuspendDays + 1}, wantErr: ErrInvalidModeration},
+		{name: "claim lost before resolve", claimed: false, input: ResolveReportInput{Action: models.ModerationWarn}, wantErr: ErrReportNotClaimed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeModerationRepo{
+				report: &models.Report{
+					ID:           10,
+					TargetType:   models.ReportTargetCollection,
+					TargetID:     5,
+					TargetUserID: &author,
+					Status:       models.ReportClaimed,
+					AssigneeID:   &assignee,
+				},
+				claimed: tt.claimed,
+			}
+			users := &fakeModerationUsers{users: map[uint]*models.User{
+				moderatorID: {ID: moderatorID, Role: models.UserRoleModerator},
+				authorID:    {ID: authorID, Role: models.UserRoleUser},
+			}}
+			service := NewModerationService(repo, users, nil, nil, nil)
+
+			report, err := service.Resolve(moderatorID, repo.report.ID, tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want %v", err, tt.wantErr)
+				}
+				if report != nil {
+					t.Errorf("Resolve() returned report %+v with an error", report)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() unexpected error: %v", err)
+			}
+
+			if report.Status != models.ReportResolved || report.Resolution != tt.input.Action {
+				t.Errorf("report status %s, resolution %s; want resolved with %s", report.Status, report.Resolution, tt.input.Action)
+			}
+			if len(repo.resolved) != 1 {
+				t.Fatalf("Resolve() reached the repository %d times, want once", len(repo.resolved))
+			}
+			if got := repo.resolved[0].SuspendUntil != nil; got != tt.wantSuspend {
+				t.Errorf("log entry has suspend date = %v, want %v", got, tt.wantSuspend)
+			}
+		})
+	}
+}
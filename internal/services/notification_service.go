@@ -0,0 +1,204 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// notificationPushTitle - заголовок push-уведомлений о событиях
+const notificationPushTitle = "Bulb"
+
+// NotificationEvent описывает действие ActorID, о котором нужно уведомить RecipientID
+type NotificationEvent struct {
+	Type         models.NotificationType
+	RecipientID  uint
+	ActorID      uint
+	CollectionID *uint
+	CommentID    *uint // Корень ветки для ответов
+}
+
+// NotificationService определяет методы сервиса уведомлений
+type NotificationService interface {
+	// Notify записывает событие в уведомления получателя и отправляет push, если
+	// в уведомлении появился новый участник. Ошибки не возвращаются: сбой уведомления
+	// не должен отменять действие, которое его вызвало. Действия над собой, отключенные
+	// типы и события между заблокировавшими друг друга пользователями пропускаются.
+	Notify(event NotificationEvent)
+	List(userID uint, unreadOnly bool, page, pageSize int) ([]*models.Notification, int64, error)
+	UnreadCount(userID uint) (int64, error)
+	MarkRead(userID, notificationID uint) error
+	MarkAllRead(userID uint) (int64, error)
+	UpdatePreferences(userID uint, preferences models.NotificationPreferences) (*models.User, error)
+}
+
+// notificationService реализует интерфейс NotificationService
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	blockRepo        repository.BlockRepository
+	pushService      PushService
+}
+
+// NewNotificationService создает новый экземпляр сервиса уведомлений
+func NewNotificationService(notificationRepo repository.NotificationRepository, userRepo repository.UserRepository, blockRepo repository.BlockRepository, pushService PushService) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		blockRepo:        blockRepo,
+		pushService:      pushService,
+	}
+}
+
+// Notify создает уведомление или объединяет событие с непрочитанным уведомлением того же вида
+func (s *notificationService) Notify(event NotificationEvent) {
+	if event.RecipientID == 0 || event.ActorID == 0 || event.RecipientID == event.ActorID {
+		return
+	}
+	recipient, err := s.userRepo.GetByID(event.RecipientID)
+	if err != nil || recipient.Notifications.IsMuted(event.Type) {
+		return
+	}
+	if err := checkNotBlocked(s.blockRepo, event.RecipientID, event.ActorID); err != nil {
+		return
+	}
+
+	notification := &models.Notification{
+		UserID:       event.RecipientID,
+		Type:         event.Type,
+		GroupKey:     notificationGroupKey(event),
+		CollectionID: event.CollectionID,
+		CommentID:    event.CommentID,
+		ActorID:      event.ActorID,
+	}
+	// Ошибку записи репозиторий уже записал в лог
+	added, err := s.notificationRepo.Record(notification)
+	if err != nil || !added {
+		return
+	}
+
+	s.pushService.SendToUser(event.RecipientID, notificationPushTitle, NotificationText(notification), notificationPushData(notification))
+}
+
+// List возвращает уведомления пользователя; unreadOnly оставляет только непрочитанные
+func (s *notificationService) List(userID uint, unreadOnly bool, page, pageSize int) ([]*models.Notification, int64, error) {
+	if userID == 0 {
+		return nil, 0, ErrInvalidUserID
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return s.notificationRepo.List(userID, unreadOnly, (page-1)*pageSize, pageSize)
+}
+
+// UnreadCount возвращает количество непрочитанных уведомлений пользователя
+func (s *notificationService) UnreadCount(userID uint) (int64, error) {
+	if userID == 0 {
+		return 0, ErrInvalidUserID
+	}
+	return s.notificationRepo.CountUnread(userID)
+}
+
+// MarkRead отмечает уведомление прочитанным (повторный запрос ничего не меняет)
+func (s *notificationService) MarkRead(userID, notificationID uint) error {
+	if userID == 0 {
+		return ErrInvalidUserID
+	}
+	found, err := s.notificationRepo.MarkRead(userID, notificationID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead отмечает прочитанными все уведомления пользователя и возвращает их количество
+func (s *notificationService) MarkAllRead(userID uint) (int64, error) {
+	if userID == 0 {
+		return 0, ErrInvalidUserID
+	}
+	return s.notificationRepo.MarkAllRead(userID)
+}
+
+// UpdatePreferences сохраняет настройки уведомлений пользователя
+func (s *notificationService) UpdatePreferences(userID uint, preferences models.NotificationPreferences) (*models.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	user.Notifications = preferences
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// NotificationText возвращает текст уведомления с учетом объединенных участников,
+// например "Anna and 4 others liked your collection"
+func NotificationText(notification *models.Notification) string {
+	actor := notification.Actor.Name
+	if actor == "" {
+		actor = "Someone"
+	}
+	switch others := notification.ActorCount - 1; {
+	case others == 1:
+		actor += " and 1 other"
+	case others > 1:
+		actor += fmt.Sprintf(" and %d others", others)
+	}
+
+	switch notification.Type {
+	case models.NotificationLike:
+		return actor + " liked your collection"
+	case models.NotificationFork:
+		return actor + " mixed your collection into their deck"
+	case models.NotificationComment:
+		return actor + " commented on your collection"
+	case models.NotificationReply:
+		return actor + " replied to your comment"
+	case models.NotificationSuggestion:
+		return actor + " suggested a card for your collection"
+	case models.NotificationFollow:
+		return actor + " started following you"
+	}
+	return actor
+}
+
+// notificationPushData возвращает данные push, по которым приложение откроет уведомление
+func notificationPushData(notification *models.Notification) map[string]string {
+	data := map[string]string{
+		"notificationId": strconv.FormatUint(uint64(notification.ID), 10),
+		"type":           string(notification.Type),
+	}
+	if notification.CollectionID != nil {
+		data["collectionId"] = strconv.FormatUint(uint64(*notification.CollectionID), 10)
+	}
+	if notification.CommentID != nil {
+		data["commentId"] = strconv.FormatUint(uint64(*notification.CommentID), 10)
+	}
+	return data
+}
+
+// notificationGroupKey возвращает ключ, по которому объединяются однотипные события об одном объекте
+func notificationGroupKey(event NotificationEvent) string {
+	key := string(event.Type)
+	if event.CollectionID != nil {
+		key += ":collection:" + strconv.FormatUint(uint64(*event.CollectionID), 10)
+	}
+	if event.CommentID != nil {
+		key += ":comment:" + strconv.FormatUint(uint64(*event.CommentID), 10)
+	}
+	return key
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+var ErrProfileSectionHidden = errors.New("profile section is hidden by user")
+
+// profileCollectionsLimit - сколько последних коллекций автора показывается в профиле.
+// Остальные доступны постранично через GetCollections.
+const profileCollectionsLimit = 10
+
+// CreatorProfile представляет публичный профиль автора с учетом его настроек приватности.
+// Скрытые данные не заполняются: пустые строки, nil и false в Show-полях.
+type CreatorProfile struct {
+	User         *models.User // ImageURL и Description очищены, если скрыты
+	IsOwner      bool         // Профиль запрошен его владельцем
+	FollowedByMe *bool        // Только для авторизованного запроса к чужому профилю
+	ShowJoinDate bool
+	ShowFollows  bool
+	Stats        *models.CreatorStats // nil, если статистика скрыта
+	Collections  []*models.Collection // nil, если коллекции скрыты
+}
+
+// ProfileService определяет методы сервиса публичных профилей авторов
+type ProfileService interface {
+	// GetProfile возвращает профиль userID, каким его видит viewerID (nil - без авторизации).
+	// Коллекции с рейтингом выше maxAgeRating в профиль не попадают.
+	GetProfile(userID uint, viewerID *uint, maxAgeRating int) (*CreatorProfile, error)
+	GetCollections(userID uint, viewerID *uint, page, pageSize int, sort models.CollectionSort, maxAgeRating int) ([]*models.Collection, int64, error)
+	UpdatePrivacy(userID uint, privacy models.ProfilePrivacy) (*models.User, error)
+}
+
+// profileService реализует интерфейс ProfileService
+type profileService struct {
+	userRepo       repository.UserRepository
+	collectionRepo repository.CollectionRepository
+	followRepo     repository.FollowRepository
+	blockRepo      repository.BlockRepository
+}
+
+// NewProfileService создает новый экземпляр сервиса профилей
+func NewProfileService(userRepo repository.UserRepository, collectionRepo repository.CollectionRepository, followRepo repository.FollowRepository, blockRepo repository.BlockRepository) ProfileService {
+	return &profileService{
+		userRepo:       userRepo,
+		collectionRepo: collectionRepo,
+		followRepo:     followRepo,
+		blockRepo:      blockRepo,
+	}
+}
+
+// GetProfile возвращает публичный профиль автора. Заблокированному автором
+// пользователю профиль недоступен (ErrUserNotFound).
+func (s *profileService) GetProfile(userID uint, viewerID *uint, maxAgeRating int) (*CreatorProfile, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if err := checkProfileVisible(s.blockRepo, user.ID, viewerID); err != nil {
+		return nil, err
+	}
+
+	isOwner := viewerID != nil && *viewerID == user.ID
+	privacy := user.Privacy
+	if isOwner {
+		privacy = models.ProfilePrivacy{}
+	}
+
+	// Копия, чтобы скрытые поля не попали в ответ
+	public := *user
+	if privacy.HideAvatar {
+		public.ImageURL = ""
+	}
+	if privacy.HideBio {
+		public.Description = ""
+	}
+
+	profile := &CreatorProfile{
+		User:         &public,
+		IsOwner:      isOwner,
+		ShowJoinDate: !privacy.HideJoinDate,
+		ShowFollows:  !privacy.HideFollows,
+	}
+
+	if viewerID != nil && !isOwner {
+		followed, err := s.followRepo.GetFollowedIDs(*viewerID, []uint{user.ID})
+		if err != nil {
+			return nil, err
+		}
+		following := followed[user.ID]
+		profile.FollowedByMe = &following
+	}
+
+	if !privacy.HideStats {
+		stats, err := s.collectionRepo.GetCreatorStats(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		profile.Stats = stats
+	}
+
+	if !privacy.HideCollections {
+		collections, _, err := s.collectionRepo.ListByUser(user.ID, 0, profileCollectionsLimit, models.CollectionSortNewest, maxAgeRating)
+		if err != nil {
+			return nil, err
+		}
+		profile.Collections = collections
+	}
+
+	return profile, nil
+}
+
+// GetCollections возвращает публичные коллекции автора постранично
+func (s *profileService) GetCollections(userID uint, viewerID *uint, page, pageSize int, sort models.CollectionSort, maxAgeRating int) ([]*models.Collection, int64, error) {
+	if !sort.IsValid() {
+		return nil, 0, ErrInvalidCollectionSort
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, 0, ErrUserNotFound
+	}
+	if err := checkProfileVisible(s.blockRepo, user.ID, viewerID); err != nil {
+		return nil, 0, err
+	}
+	if user.Privacy.HideCollections && (viewerID == nil || *viewerID != user.ID) {
+		return nil, 0, ErrProfileSectionHidden
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if sort == models.CollectionSortDefault {
+		sort = models.CollectionSortNewest
+	}
+
+	return s.collectionRepo.ListByUser(user.ID, (page-1)*pageSize, pageSize, sort, maxAgeRating)
+}
+
+// UpdatePrivacy сохраняет настройки приватности профиля
+func (s *profileService) UpdatePrivacy(userID uint, privacy models.ProfilePrivacy) (*models.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	user.Privacy = privacy
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/push"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+var (
+	ErrInvalidDevicePlatform = errors.New("invalid device platform")
+	ErrInvalidDeviceToken    = errors.New("invalid device token")
+	ErrDeviceTokenNotFound   = errors.New("device token not found")
+)
+
+// maxDeviceTokenLength совпадает с размером столбца токена
+const maxDeviceTokenLength = 512
+
+// PushService определяет методы регистрации устройств и отправки push-уведомлений
+type PushService interface {
+	RegisterDevice(userID uint, platform models.DevicePlatform, token string) (*models.DeviceToken, error)
+	UnregisterDevice(userID uint, token string) error
+	// SendToUser ставит сообщение в очередь доставки на все устройства пользователя.
+	// Ничего не отправляется, если пользователь отключил push в настройках уведомлений.
+	SendToUser(userID uint, title, body string, data map[string]string)
+	// Start обрабатывает очередь доставки до отмены контекста
+	Start(ctx context.Context)
+}
+
+// pushService реализует интерфейс PushService
+type pushService struct {
+	deviceRepo repository.DeviceTokenRepository
+	userRepo   repository.UserRepository
+	senders    push.Senders
+	queue      *push.Queue
+}
+
+// NewPushService создает новый экземпляр сервиса push-уведомлений. Устройства платформ,
+// для которых нет отправителя в senders, регистрируются, но push не получают.
+func NewPushService(
+	deviceRepo repository.DeviceTokenRepository,
+	userRepo repository.UserRepository,
+	senders push.Senders,
+	queueConfig push.QueueConfig,
+) PushService {
+	s := &pushService{
+		deviceRepo: deviceRepo,
+		userRepo:   userRepo,
+		senders:    senders,
+	}
+	s.queue = push.NewQueue(queueConfig, s.pruneToken)
+	return s
+}
+
+// RegisterDevice сохраняет push-токен устройства, на котором пользователь вошел в приложение.
+// Приложение вызывает регистрацию после входа и при каждой смене токена.
+func (s *pushService) RegisterDevice(userID uint, platform models.DevicePlatform, token string) (*models.DeviceToken, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if !platform.IsValid() {
+		return nil, ErrInvalidDevicePlatform
+	}
+	token = strings.TrimSpace(token)
+	if !isValidDeviceToken(platform, token) {
+		return nil, ErrInvalidDeviceToken
+	}
+
+	device := &models.DeviceToken{
+		UserID:     userID,
+		Platform:   platform,
+		Token:      token,
+		LastSeenAt: time.Now(),
+	}
+	if err := s.deviceRepo.Register(device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// UnregisterDevice удаляет токен устройства, например при выходе из аккаунта
+func (s *pushService) UnregisterDevice(userID uint, token string) error {
+	if userID == 0 {
+		return ErrInvalidUserID
+	}
+	removed, err := s.deviceRepo.Unregister(userID, strings.TrimSpace(token))
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrDeviceTokenNotFound
+	}
+	return nil
+}
+
+// isValidDeviceToken проверяет формат токена платформы: APNs выдает токен в виде
+// hex-строки, токен FCM состоит из букв, цифр и символов "-", "_" и ":". Токен
+// передается в адресе запроса APNs, поэтому другие символы не допускаются.
+func isValidDeviceToken(platform models.DevicePlatform, token string) bool {
+	if token == "" || len(token) > maxDeviceTokenLength {
+		return false
+	}
+	if platform == models.DevicePlatformIOS && len(token)%2 != 0 {
+		return false
+	}
+	for _, r := range token {
+		switch {
+		case r >= '0' && r <= '9', r >= 'a' && r <= 'f', r >= 'A' && r <= 'F':
+		case platform == models.DevicePlatformAndroid &&
+			(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-' || r == '_' || r == ':'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// SendToUser ставит push в очередь на каждое устройство пользователя с известной платформой
+func (s *pushService) SendToUser(userID uint, title, body string, data map[string]string) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || user.Notifications.MutePush {
+		return
+	}
+	devices, err := s.deviceRepo.ListByUser(userID)
+	if err != nil {
+		return
+	}
+
+	for _, device := range devices {
+		sender := s.sender(device.Platform)
+		if sender == nil {
+			continue
+		}
+		delivery := push.Delivery{
+			Sender:  sender,
+			Message: push.Message{Token: device.Token, Title: title, Body: body, Data: data},
+		}
+		// Переполнение очереди записывает в лог сама очередь
+		s.queue.Enqueue(delivery)
+	}
+}
+
+// Start обрабатывает очередь доставки до отмены контекста
+func (s *pushService) Start(ctx context.Context) {
+	s.queue.Start(ctx)
+}
+
+// sender возвращает отправителя платформы или nil, если платформа не настроена
+func (s *pushService) sender(platform models.DevicePlatform) push.PushSender {
+	switch platform {
+	case models.DevicePlatformAndroid:
+		return s.senders.FCM
+	case models.DevicePlatformIOS:
+		return s.senders.APNs
+	}
+	return nil
+}
+
+// pruneToken удаляет токен, который сервис доставки отклонил навсегда
+func (s *pushService) pruneToken(token string) {
+	// Ошибку удаления репозиторий уже записал в лог; токен удалится при следующем отказе
+	_ = s.deviceRepo.DeleteByToken(token)
+}
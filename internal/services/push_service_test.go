@@ -0,0 +1,35 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+)
+
+func TestIsValidDeviceToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform models.DevicePlatform
+		token    string
+		want     bool
+	}{
+		{name: "apns hex", platform: models.DevicePlatformIOS, token: strings.Repeat("0a1B", 16), want: true},
+		{name: "apns odd length", platform: models.DevicePlatformIOS, token: "0a1", want: false},
+		{name: "apns not hex", platform: models.DevicePlatformIOS, token: "0a1g", want: false},
+		{name: "apns path", platform: models.DevicePlatformIOS, token: "../../3/device/0a", want: false},
+		{name: "fcm token", platform: models.DevicePlatformAndroid, token: "dQw4w9WgXcQ:APA91bH-x_Yz0", want: true},
+		{name: "fcm with slash", platform: models.DevicePlatformAndroid, token: "dQw4/w9WgXcQ", want: false},
+		{name: "fcm with space", platform: models.DevicePlatformAndroid, token: "dQw4 w9WgXcQ", want: false},
+		{name: "empty", platform: models.DevicePlatformAndroid, token: "", want: false},
+		{name: "too long", platform: models.DevicePlatformAndroid, token: strings.Repeat("a", maxDeviceTokenLength+1), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isValidDeviceToken(tt.platform, tt.token); got != tt.want {
+				t.Errorf("isValidDeviceToken(%s, %q) = %v, want %v", tt.platform, tt.token, got, tt.want)
+			}
+		})
+	}
+}
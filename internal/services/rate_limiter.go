@@ -0,0 +1,64 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter ограничивает количество действий одного пользователя за скользящее окно.
+// Состояние хранится в памяти процесса; пользователи без действий в окне удаляются
+// при вызовах Allow не чаще одного раза за окно.
+type rateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	events    map[uint][]time.Time
+	lastSweep time.Time // Время последней очистки пользователей без действий в окне
+}
+
+// newRateLimiter создает ограничитель на limit действий за window
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:  limit,
+		window: window,
+		events: make(map[uint][]time.Time),
+	}
+}
+
+// Allow регистрирует действие пользователя, если лимит не исчерпан
+func (l *rateLimiter) Allow(userID uint) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-l.window)
+	if now.Sub(l.lastSweep) >= l.window {
+		l.sweep(cutoff)
+		l.lastSweep = now
+	}
+
+	recent := l.events[userID][:0]
+	for _, at := range l.events[userID] {
+		if at.After(cutoff) {
+			recent = append(recent, at)
+		}
+	}
+
+	if len(recent) >= l.limit {
+		l.events[userID] = recent
+		return false
+	}
+	l.events[userID] = append(recent, now)
+	return true
+}
+
+// sweep удаляет пользователей, у которых не осталось действий в окне.
+// Действия хранятся по возрастанию времени, поэтому достаточно проверить последнее.
+func (l *rateLimiter) sweep(cutoff time.Time) {
+	for userID, events := range l.events {
+		if len(events) == 0 || !events[len(events)-1].After(cutoff) {
+			delete(l.events, userID)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+var (
+	ErrReviewNotFound      = errors.New("review not found")
+	ErrInvalidRating       = errors.New("rating must be between 1 and 5")
+	ErrReviewTooLong       = errors.New("review text is too long")
+	ErrOwnCollectionReview = errors.New("owners cannot review their own collections")
+)
+
+// MaxReviewLength ограничивает длину отзыва и ответа владельца в символах
+const MaxReviewLength = 2000
+
+// ReviewService определяет методы сервиса оценок и отзывов
+type ReviewService interface {
+	Save(userID, collectionID uint, rating int, text string) (*models.CollectionReview, bool, error)
+	Delete(userID, collectionID uint) error
+	GetMine(userID, collectionID uint) (*models.CollectionReview, error)
+	List(collectionID uint, viewerID *uint, page, pageSize int) ([]*models.CollectionReview, int64, error)
+	Reply(ownerID, reviewID uint, text string) (*models.CollectionReview, error)
+}
+
+// reviewService реализует интерфейс ReviewService
+type reviewService struct {
+	reviewRepo     repository.ReviewRepository
+	collectionRepo repository.CollectionRepository
+}
+
+// NewReviewService создает новый экземпляр сервиса оценок и отзывов
+func NewReviewService(reviewRepo repository.ReviewRepository, collectionRepo repository.CollectionRepository) ReviewService {
+	return &reviewService{
+		reviewRepo:     reviewRepo,
+		collectionRepo: collectionRepo,
+	}
+}
+
+// Save создает или изменяет отзыв пользователя; второй результат - был ли отзыв создан
+func (s *reviewService) Save(userID, collectionID uint, rating int, text string) (*models.CollectionReview, bool, error) {
+	if userID == 0 {
+		return nil, false, ErrInvalidUserID
+	}
+	if rating < models.MinRating || rating > models.MaxRating {
+		return nil, false, ErrInvalidRating
+	}
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) > MaxReviewLength {
+		return nil, false, ErrReviewTooLong
+	}
+
+	collection, err := s.collectionRepo.GetByID(collectionID)
+	if err != nil {
+		return nil, false, ErrCollectionNotFound
+	}
+	if collection.UserID == userID {
+		return nil, false, ErrOwnCollectionReview
+	}
+
+	review := &models.CollectionReview{
+		CollectionID: collectionID,
+		UserID:       userID,
+		Rating:       rating,
+		Text:         text,
+	}
+	created, err := s.reviewRepo.Save(review)
+	if err != nil {
+		return nil, false, err
+	}
+
+	saved, err := s.reviewRepo.GetByID(review.ID)
+	if err != nil {
+		return nil, false, ErrReviewNotFound
+	}
+	return saved, created, nil
+}
+
+// Delete удаляет отзыв пользователя о коллекции
+func (s *reviewService) Delete(userID, collectionID uint) error {
+	deleted, err := s.reviewRepo.Delete(userID, collectionID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrReviewNotFound
+	}
+	return nil
+}
+
+// GetMine возвращает отзыв пользователя о коллекции
+func (s *reviewService) GetMine(userID, collectionID uint) (*models.CollectionReview, error) {
+	review, err := s.reviewRepo.GetByUserAndCollection(userID, collectionID)
+	if err != nil {
+		return nil, ErrReviewNotFound
+	}
+	return review, nil
+}
+
+// List возвращает отзывы о коллекции с пагинацией, кроме отзывов пользователей, заблокированных viewerID
+func (s *reviewService) List(collectionID uint, viewerID *uint, page, pageSize int) ([]*models.CollectionReview, int64, error) {
+	if _, err := s.collectionRepo.GetByID(collectionID); err != nil {
+		return nil, 0, ErrCollectionNotFound
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return s.reviewRepo.ListByCollection(collectionID, viewerID, (page-1)*pageSize, pageSize)
+}
+
+// Reply сохраняет ответ владельца коллекции на отзыв; пустой текст удаляет ответ
+func (s *reviewService) Reply(ownerID, reviewID uint, text string) (*models.CollectionReview, error) {
+	review, err := s.reviewRepo.GetByID(reviewID)
+	if err != nil {
+		return nil, ErrReviewNotFound
+	}
+
+	collection, err := s.collectionRepo.GetByID(review.CollectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+	if collection.UserID != ownerID {
+		return nil, ErrNotCollectionOwner
+	}
+
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) > MaxReviewLength {
+		return nil, ErrReviewTooLong
+	}
+
+	review.OwnerReply = text
+	review.OwnerRepliedAt = nil
+	if text != "" {
+		now := time.Now()
+		review.OwnerRepliedAt = &now
+	}
+	if err := s.reviewRepo.UpdateReply(review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
@@ -0,0 +1,1114 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/realtime"
+	"github.com/KoLili12/bulb-server/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRoomNotFound       = errors.New("room not found")
+	ErrRoomFinished       = errors.New("room is finished")
+	ErrRoomFull           = errors.New("room is full")
+	ErrNotRoomMember      = errors.New("not a member of this room")
+	ErrNotRoomHost        = errors.New("only the host can do this")
+	ErrNotYourTurn        = errors.New("it is not your turn")
+	ErrRoomNotPlaying     = errors.New("game in this room has not started")
+	ErrRoomAlreadyStarted = errors.New("game in this room has already started")
+	ErrUnknownRoomCommand = errors.New("unknown room command")
+	ErrMemberNameTaken    = errors.New("member name is already taken")
+	ErrGuestCannotInvite  = errors.New("only registered members can invite users")
+	ErrAlreadyRoomMember  = errors.New("user is already in this room")
+	ErrInviteNotAllowed   = errors.New("user accepts room invites only from people they follow")
+	ErrInviteRateLimited  = errors.New("too many invites, try again later")
+)
+
+const (
+	roomCodeLength   = 6
+	roomCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // Без похожих символов 0/O и 1/I
+	maxRoomMembers   = MaxRenderPlayers
+	roomInviteTitle  = "Bulb"
+
+	// Не больше roomInviteRateLimit приглашений за roomInviteRateWindow от одного пользователя
+	roomInviteRateLimit  = 30
+	roomInviteRateWindow = time.Hour
+)
+
+// Типы событий комнаты
+const (
+	RoomEventState        = "state"
+	RoomEventMemberJoined = "member_joined"
+	RoomEventMemberLeft   = "member_left"
+	RoomEventPresence     = "presence"
+	RoomEventGameStarted  = "game_started"
+	RoomEventTypeChosen   = "card_type_chosen"
+	RoomEventCardDrawn    = "card_drawn"
+	RoomEventPlayerAction = "player_action"
+	RoomEventGameEnded    = "game_ended"
+)
+
+// Команды, которые клиент отправляет в комнату
+const (
+	RoomCommandStart    = "start"
+	RoomCommandChoose   = "choose"
+	RoomCommandDraw     = "draw"
+	RoomCommandComplete = "complete"
+	RoomCommandSkip     = "skip"
+	RoomCommandReact    = "react"
+	RoomCommandEnd      = "end"
+	RoomCommandLeave    = "leave"
+	RoomCommandSync     = "sync"
+	RoomCommandReset    = "reset_history" // Очищает историю просмотров комнаты (но не ее участников)
+)
+
+// RoomCommand представляет команду участника комнаты
+type RoomCommand struct {
+	Type     string            `json:"type"`
+	CardType string            `json:"cardType,omitempty"` // Для choose и draw
+	Reaction string            `json:"reaction,omitempty"` // Для react
+	Rules    *models.GameRules `json:"rules,omitempty"`    // Для start: правила подсчета очков
+	// Для start: сначала выдавать карточки, которых комната и ее участники еще не видели
+	PreferUnseen bool `json:"preferUnseen,omitempty"`
+	// Для start: перемешивать колоду с учетом качества карточек
+	QualityWeighted bool `json:"qualityWeighted,omitempty"`
+}
+
+// Следующие структуры передаются клиентам в событиях комнаты как есть,
+// поэтому содержат JSON-теги.
+
+// RoomMemberState представляет участника в состоянии комнаты
+type RoomMemberState struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	IsHost    bool   `json:"isHost"`
+	IsGuest   bool   `json:"isGuest"`
+	Connected bool   `json:"connected"`
+}
+
+// RoomCard представляет вытянутую карточку в событиях комнаты
+type RoomCard struct {
+	ActionID     uint               `json:"actionId"`
+	Type         models.ActionType  `json:"type"`
+	Text         string             `json:"text"`
+	Extra        models.ActionExtra `json:"extra,omitempty"`
+	Difficulty   int                `json:"difficulty"`
+	Spiciness    int                `json:"spiciness"`
+	TimerSeconds int                `json:"timerSeconds"`
+	Points       int                `json:"points"`
+}
+
+// RoomDrawState описывает ход в событиях комнаты
+type RoomDrawState struct {
+	MemberID uint      `json:"memberId"`
+	Player   string    `json:"player"`
+	Turn     int       `json:"turn"`
+	Round    int       `json:"round"`
+	NewRound bool      `json:"newRound"`
+	Card     *RoomCard `json:"card"`
+}
+
+// RoomPlayerAction описывает действие участника (выбор типа, выполнение, пропуск, реакция)
+type RoomPlayerAction struct {
+	MemberID uint   `json:"memberId"`
+	Action   string `json:"action"`
+	CardType string `json:"cardType,omitempty"`
+	Reaction string `json:"reaction,omitempty"`
+	// Для complete и skip: игрок, чья карточка, и изменение его счета
+	PlayerMemberID uint      `json:"playerMemberId,omitempty"`
+	Points         int       `json:"points,omitempty"`
+	Score          int       `json:"score,omitempty"`
+	Penalty        *RoomCard `json:"penalty,omitempty"`
+}
+
+// RoomScore представляет строку таблицы результатов комнаты
+type RoomScore struct {
+	MemberID  uint   `json:"memberId,omitempty"` // Пустой, если участник покинул комнату
+	Player    string `json:"player"`
+	Score     int    `json:"score"`
+	Completed int    `json:"completed"`
+	Skipped   int    `json:"skipped"`
+	Rank      int    `json:"rank"`
+}
+
+// RoomState представляет полное состояние комнаты для синхронизации клиента
+type RoomState struct {
+	Code            string            `json:"code"`
+	Status          models.RoomStatus `json:"status"`
+	CollectionID    uint              `json:"collectionId"`
+	HostMemberID    uint              `json:"hostMemberId"`
+	Members         []RoomMemberState `json:"members"`
+	CurrentMemberID uint              `json:"currentMemberId,omitempty"`
+	ChosenType      models.ActionType `json:"chosenType,omitempty"`
+	Turn            int               `json:"turn"`
+	Round           int               `json:"round"`
+	RemainingCards  int               `json:"remainingCards"`
+	LastDraw        *RoomDrawState    `json:"lastDraw,omitempty"`
+	Rules           *models.GameRules `json:"rules,omitempty"`
+	Scoreboard      []RoomScore       `json:"scoreboard,omitempty"`
+}
+
+// RoomService определяет методы сервиса игровых комнат
+type RoomService interface {
+	// Create и Join не пускают в комнату, коллекция которой выше maxAgeRating
+	Create(collectionID uint, userID *uint, name string, maxAgeRating int) (*models.Room, *models.RoomMember, error)
+	Join(code string, userID *uint, name string, maxAgeRating int) (*models.RoomMember, error)
+	Authenticate(code string, memberToken string) (*models.RoomMember, error)
+	State(code string) (*RoomState, error)
+	// PublicState возвращает состояние комнаты без токена участника; комната
+	// с коллекцией выше maxAgeRating считается ненайденной
+	PublicState(code string, maxAgeRating int) (*RoomState, error)
+	Execute(code string, memberID uint, cmd RoomCommand) error
+	Connect(code string, memberID uint, lastEventID uint64) (*RoomConnection, error)
+	// Invite отправляет пользователю push с приглашением в комнату от ее участника.
+	// Пригласить можно только пользователя, который подписан на приглашающего.
+	Invite(code string, memberID, userID uint) error
+	// RemoveBlocked выводит blockedID из незавершенных комнат, где он играет вместе с blockerID
+	RemoveBlocked(blockerID, blockedID uint) error
+}
+
+// RoomConnection представляет подписку участника на события комнаты
+type RoomConnection struct {
+	Events <-chan realtime.Event
+	// Missed содержит события после переданного lastEventID, если поток удалось возобновить
+	Missed []realtime.Event
+	// Resumed равен false, если клиенту нужно отправить полное состояние комнаты
+	Resumed bool
+	// LastEventID - ID последнего события комнаты на момент подписки;
+	// используется как ID события с полным состоянием
+	LastEventID uint64
+	// Close отписывает участника и должна быть вызвана при отключении
+	Close func()
+}
+
+// roomService реализует интерфейс RoomService
+type roomService struct {
+	roomRepo          repository.RoomRepository
+	userRepo          repository.UserRepository
+	historyRepo       repository.HistoryRepository
+	blockRepo         repository.BlockRepository
+	followRepo        repository.FollowRepository
+	collectionService CollectionService
+	gameService       GameService
+	pushService       PushService
+	broker            realtime.Broker
+	inviteLimiter     *rateLimiter
+
+	locks sync.Map
+
+	mu       sync.Mutex
+	presence map[string]map[uint]int               // Количество активных подключений участника
+	chosen   map[string]map[uint]models.ActionType // Выбранный участником тип карточки на текущий ход
+}
+
+// NewRoomService создает новый экземпляр сервиса игровых комнат
+func NewRoomService(
+	roomRepo repository.RoomRepository,
+	userRepo repository.UserRepository,
+	historyRepo repository.HistoryRepository,
+	blockRepo repository.BlockRepository,
+	followRepo repository.FollowRepository,
+	collectionService CollectionService,
+	gameService GameService,
+	pushService PushService,
+	broker realtime.Broker,
+) RoomService {
+	return &roomService{
+		roomRepo:          roomRepo,
+		userRepo:          userRepo,
+		historyRepo:       historyRepo,
+		blockRepo:         blockRepo,
+		followRepo:        followRepo,
+		collectionService: collectionService,
+		gameService:       gameService,
+		pushService:       pushService,
+		broker:            broker,
+		inviteLimiter:     newRateLimiter(roomInviteRateLimit, roomInviteRateWindow),
+		presence:          make(map[string]map[uint]int),
+		chosen:            make(map[string]map[uint]models.ActionType),
+	}
+}
+
+// Create создает комнату по коллекции; создатель становится ведущим
+func (s *roomService) Create(collectionID uint, userID *uint, name string, maxAgeRating int) (*models.Room, *models.RoomMember, error) {
+	if err := s.checkCollection(collectionID, userID, maxAgeRating); err != nil {
+		return nil, nil, err
+	}
+
+	name, err := s.memberName(userID, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	code, err := s.generateCode()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	host := &models.RoomMember{
+		UserID:   userID,
+		Name:     name,
+		Token:    uuid.New().String(),
+		IsHost:   true,
+		JoinedAt: now,
+	}
+	room := &models.Room{
+		Code:         code,
+		CollectionID: collectionID,
+		Status:       models.RoomStatusLobby,
+		Members:      []*models.RoomMember{host},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.roomRepo.Create(room); err != nil {
+		return nil, nil, err
+	}
+
+	return room, host, nil
+}
+
+// Join добавляет участника в комнату. Авторизованный пользователь, уже состоящий
+// в комнате, получает своего прежнего участника (переподключение). Войти нельзя,
+// если с кем-то из зарегистрированных участников комнаты есть блокировка.
+// Гостей блокировка не касается: у гостя нет аккаунта, поэтому его нельзя
+// заблокировать и нельзя проверить его блокировки.
+func (s *roomService) Join(code string, userID *uint, name string, maxAgeRating int) (*models.RoomMember, error) {
+	code = normalizeRoomCode(code)
+	unlock := s.lock(code)
+	defer unlock()
+
+	room, err := s.getLockedRoom(code)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCollection(room.CollectionID, userID, maxAgeRating); err != nil {
+		return nil, ErrRoomNotFound
+	}
+	if room.Status == models.RoomStatusFinished {
+		return nil, ErrRoomFinished
+	}
+
+	if userID != nil {
+		for _, member := range room.Members {
+			if member.UserID != nil && *member.UserID == *userID && member.LeftAt == nil {
+				return member, nil
+			}
+		}
+	}
+
+	name, err = s.memberName(userID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	active := activeMembers(room)
+	if len(active) >= maxRoomMembers {
+		return nil, ErrRoomFull
+	}
+	if userID != nil {
+		memberUserIDs := make([]uint, 0, len(active))
+		for _, member := range active {
+			if member.UserID != nil {
+				memberUserIDs = append(memberUserIDs, *member.UserID)
+			}
+		}
+		blocked, err := s.blockRepo.IsBlockedWithAny(*userID, memberUserIDs)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, ErrUserBlocked
+		}
+	}
+	for _, member := range active {
+		if strings.EqualFold(member.Name, name) {
+			return nil, ErrMemberNameTaken
+		}
+	}
+
+	member := &models.RoomMember{
+		RoomID:   room.ID,
+		UserID:   userID,
+		Name:     name,
+		Token:    uuid.New().String(),
+		JoinedAt: time.Now(),
+	}
+	if err := s.roomRepo.AddMember(member); err != nil {
+		return nil, err
+	}
+
+	// Во время игры новый участник встает в конец очереди ходов
+	if room.Status == models.RoomStatusPlaying && room.GameSessionID != nil {
+		spec := GamePlayerSpec{Name: name, UserID: userID, MemberID: &member.ID}
+		if _, err := s.gameService.AddPlayer(*room.GameSessionID, nil, spec); err != nil {
+			// Участник без игрока не сможет ходить: отменяем вход
+			now := time.Now()
+			member.LeftAt = &now
+			if updateErr := s.roomRepo.UpdateMember(member); updateErr != nil {
+				return nil, updateErr
+			}
+			return nil, err
+		}
+	}
+
+	s.publish(code, RoomEventMemberJoined, s.memberState(code, member))
+	return member, nil
+}
+
+// Invite приглашает пользователя в комнату. Приглашать могут только зарегистрированные
+// участники; приглашение нельзя отправить пользователю, с которым есть блокировка.
+// Push не отправляется, если приглашенный отключил push в настройках уведомлений.
+func (s *roomService) Invite(code string, memberID, userID uint) error {
+	code = normalizeRoomCode(code)
+	room, err := s.getRoom(code)
+	if err != nil {
+		return err
+	}
+	if room.Status == models.RoomStatusFinished {
+		return ErrRoomFinished
+	}
+
+	var inviter *models.RoomMember
+	for _, member := range activeMembers(room) {
+		if member.ID == memberID {
+			inviter = member
+		}
+		if member.UserID != nil && *member.UserID == userID {
+			return ErrAlreadyRoomMember
+		}
+	}
+	if inviter == nil {
+		return ErrNotRoomMember
+	}
+	if inviter.UserID == nil {
+		return ErrGuestCannotInvite
+	}
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return ErrUserNotFound
+	}
+	if err := checkNotBlocked(s.blockRepo, *inviter.UserID, userID); err != nil {
+		return err
+	}
+	// Push о приглашении получают только от тех, на кого пользователь подписан сам
+	followed, err := s.followRepo.GetFollowedIDs(userID, []uint{*inviter.UserID})
+	if err != nil {
+		return err
+	}
+	if !followed[*inviter.UserID] {
+		return ErrInviteNotAllowed
+	}
+	if !s.inviteLimiter.Allow(*inviter.UserID) {
+		return ErrInviteRateLimited
+	}
+
+	s.pushService.SendToUser(userID, roomInviteTitle, inviter.Name+" invites you to play", map[string]string{
+		"type":     "room_invite",
+		"roomCode": code,
+	})
+	return nil
+}
+
+// Authenticate находит участника комнаты по токену переподключения
+func (s *roomService) Authenticate(code string, memberToken string) (*models.RoomMember, error) {
+	member, err := s.roomRepo.GetMemberByToken(memberToken)
+	if err != nil {
+		return nil, ErrNotRoomMember
+	}
+
+	room, err := s.getRoom(normalizeRoomCode(code))
+	if err != nil {
+		return nil, err
+	}
+	if member.RoomID != room.ID || member.LeftAt != nil {
+		return nil, ErrNotRoomMember
+	}
+	return member, nil
+}
+
+// State возвращает полное состояние комнаты
+func (s *roomService) State(code string) (*RoomState, error) {
+	code = normalizeRoomCode(code)
+	room, err := s.getRoom(code)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildState(room)
+}
+
+// PublicState возвращает состояние комнаты, если ее коллекцию можно показать в запросе
+func (s *roomService) PublicState(code string, maxAgeRating int) (*RoomState, error) {
+	code = normalizeRoomCode(code)
+	room, err := s.getRoom(code)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCollection(room.CollectionID, nil, maxAgeRating); err != nil {
+		return nil, ErrRoomNotFound
+	}
+	return s.buildState(room)
+}
+
+// Connect регистрирует подключение участника и подписывает его на события комнаты.
+// Если передан lastEventID, пропущенные события возвращаются из журнала комнаты.
+// К завершенной комнате подключиться нельзя: ее события уже удалены из брокера.
+func (s *roomService) Connect(code string, memberID uint, lastEventID uint64) (*RoomConnection, error) {
+	code = normalizeRoomCode(code)
+	// Под блокировкой подписка не создаст заново тему комнаты, которую закрывает endGame
+	unlock := s.lock(code)
+	defer unlock()
+
+	room, err := s.getLockedRoom(code)
+	if err != nil {
+		return nil, err
+	}
+	if room.Status == models.RoomStatusFinished {
+		return nil, ErrRoomFinished
+	}
+	member := findMember(room, memberID)
+	if member == nil || member.LeftAt != nil {
+		return nil, ErrNotRoomMember
+	}
+
+	sub := s.broker.SubscribeSince(code, lastEventID)
+	conn := &RoomConnection{
+		Events:      sub.Events,
+		LastEventID: sub.LastID,
+	}
+	// Без lastEventID журнал не воспроизводится: клиент получает полное состояние
+	if lastEventID > 0 && sub.Complete {
+		conn.Missed = sub.Missed
+		conn.Resumed = true
+	}
+
+	s.changePresence(code, member, 1)
+
+	conn.Close = func() {
+		sub.Unsubscribe()
+		s.changePresence(code, member, -1)
+	}
+	return conn, nil
+}
+
+// Execute выполняет команду участника комнаты и рассылает результат всем участникам
+func (s *roomService) Execute(code string, memberID uint, cmd RoomCommand) error {
+	code = normalizeRoomCode(code)
+	unlock := s.lock(code)
+	defer unlock()
+
+	room, err := s.getLockedRoom(code)
+	if err != nil {
+		return err
+	}
+	member := findMember(room, memberID)
+	if member == nil || member.LeftAt != nil {
+		return ErrNotRoomMember
+	}
+	isHost := member.ID == room.HostMemberID
+
+	switch cmd.Type {
+	case RoomCommandStart:
+		if !isHost {
+			return ErrNotRoomHost
+		}
+		return s.startGame(room, cmd)
+	case RoomCommandChoose:
+		return s.chooseType(room, member, models.ActionType(cmd.CardType))
+	case RoomCommandDraw:
+		return s.draw(room, member, isHost, models.ActionType(cmd.CardType))
+	case RoomCommandComplete, RoomCommandSkip:
+		return s.resolveDraw(room, member, isHost, cmd.Type)
+	case RoomCommandReact:
+		s.publish(code, RoomEventPlayerAction, RoomPlayerAction{
+			MemberID: member.ID,
+			Action:   cmd.Type,
+			Reaction: cmd.Reaction,
+		})
+		return nil
+	case RoomCommandEnd:
+		if !isHost {
+			return ErrNotRoomHost
+		}
+		return s.endGame(room)
+	case RoomCommandLeave:
+		return s.leave(room, member)
+	case RoomCommandReset:
+		if !isHost {
+			return ErrNotRoomHost
+		}
+		return s.historyRepo.DeleteRoomViews(room.ID)
+	case RoomCommandSync:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownRoomCommand, cmd.Type)
+	}
+}
+
+// startGame создает игровую сессию с участниками комнаты в порядке входа
+func (s *roomService) startGame(room *models.Room, cmd RoomCommand) error {
+	if room.Status != models.RoomStatusLobby {
+		return ErrRoomAlreadyStarted
+	}
+
+	players := make([]GamePlayerSpec, 0, len(room.Members))
+	for _, member := range activeMembers(room) {
+		players = append(players, GamePlayerSpec{Name: member.Name, UserID: member.UserID, MemberID: &member.ID})
+	}
+
+	// Участники вошли в комнату с рейтингом ее коллекции: коллекция штрафов не может быть выше
+	collection, err := s.collectionService.GetByID(room.CollectionID)
+	if err != nil {
+		return err
+	}
+	session, err := s.gameService.Start(room.CollectionID, nil, GameOptions{
+		Players:         players,
+		Rules:           cmd.Rules,
+		RoomID:          &room.ID,
+		PreferUnseen:    cmd.PreferUnseen,
+		QualityWeighted: cmd.QualityWeighted,
+		MaxAgeRating:    collection.AgeRating,
+	})
+	if err != nil {
+		return err
+	}
+
+	room.GameSessionID = &session.ID
+	room.Status = models.RoomStatusPlaying
+	room.UpdatedAt = time.Now()
+	if err := s.roomRepo.Update(room); err != nil {
+		return err
+	}
+
+	state, err := s.buildState(room)
+	if err != nil {
+		return err
+	}
+	s.publish(room.Code, RoomEventGameStarted, state)
+	return nil
+}
+
+// chooseType запоминает выбор типа карточки участником, чей сейчас ход
+func (s *roomService) chooseType(room *models.Room, member *models.RoomMember, actionType models.ActionType) error {
+	if room.Status != models.RoomStatusPlaying {
+		return ErrRoomNotPlaying
+	}
+	if !models.IsValidActionType(actionType) {
+		return fmt.Errorf("%w: %s", ErrInvalidActionType, actionType)
+	}
+
+	current, err := s.currentMember(room)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.ID != member.ID {
+		return ErrNotYourTurn
+	}
+
+	s.mu.Lock()
+	s.chosen[room.Code] = map[uint]models.ActionType{member.ID: actionType}
+	s.mu.Unlock()
+
+	s.publish(room.Code, RoomEventTypeChosen, RoomPlayerAction{
+		MemberID: member.ID,
+		Action:   RoomCommandChoose,
+		CardType: string(actionType),
+	})
+	return nil
+}
+
+// draw тянет карточку для текущего игрока. Тянуть может сам игрок или ведущий
+// (например, если игрок отключился).
+func (s *roomService) draw(room *models.Room, member *models.RoomMember, isHost bool, actionType models.ActionType) error {
+	if room.Status != models.RoomStatusPlaying || room.GameSessionID == nil {
+		return ErrRoomNotPlaying
+	}
+
+	current, err := s.currentMember(room)
+	if err != nil {
+		return err
+	}
+	if !isHost && (current == nil || current.ID != member.ID) {
+		return ErrNotYourTurn
+	}
+
+	// Если тип не указан явно, используем выбор текущего игрока
+	s.mu.Lock()
+	if actionType == "" && current != nil {
+		actionType = s.chosen[room.Code][current.ID]
+	}
+	delete(s.chosen, room.Code)
+	s.mu.Unlock()
+
+	result, err := s.gameService.Draw(*room.GameSessionID, nil, actionType)
+	if err != nil {
+		return err
+	}
+
+	drawState := &RoomDrawState{
+		Player:   result.Player.Name,
+		Turn:     result.Draw.Turn,
+		Round:    result.Draw.Round,
+		NewRound: result.NewRound,
+		Card:     newRoomCard(result.Action, result.Draw.Text, result.Extra),
+	}
+	if current != nil {
+		drawState.MemberID = current.ID
+	}
+	s.publish(room.Code, RoomEventCardDrawn, drawState)
+	return nil
+}
+
+// resolveDraw отмечает последнюю карточку выполненной или пропущенной. Это может
+// сделать игрок, которому досталась карточка, или ведущий.
+func (s *roomService) resolveDraw(room *models.Room, member *models.RoomMember, isHost bool, command string) error {
+	if room.Status != models.RoomStatusPlaying || room.GameSessionID == nil {
+		return ErrRoomNotPlaying
+	}
+
+	session, err := s.gameService.GetByID(*room.GameSessionID)
+	if err != nil {
+		return err
+	}
+	_, player, err := pendingDraw(session)
+	if err != nil {
+		return err
+	}
+	owner := memberForPlayer(room, player)
+	if !isHost && (owner == nil || owner.ID != member.ID) {
+		return ErrNotYourTurn
+	}
+
+	var result *TurnResult
+	if command == RoomCommandComplete {
+		result, err = s.gameService.Complete(session.ID, nil)
+	} else {
+		result, err = s.gameService.Skip(session.ID, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	event := RoomPlayerAction{
+		MemberID: member.ID,
+		Action:   command,
+		Points:   result.Draw.Points,
+		Score:    result.Player.Score,
+	}
+	if owner != nil {
+		event.PlayerMemberID = owner.ID
+	}
+	if result.Penalty != nil {
+		event.Penalty = newRoomCard(result.Penalty, result.Draw.PenaltyText, nil)
+	}
+	s.publish(room.Code, RoomEventPlayerAction, event)
+	return nil
+}
+
+// endGame завершает игру в комнате
+func (s *roomService) endGame(room *models.Room) error {
+	if room.Status == models.RoomStatusFinished {
+		return ErrRoomFinished
+	}
+	if room.GameSessionID != nil {
+		if _, err := s.gameService.End(*room.GameSessionID, nil); err != nil && err != ErrGameFinished {
+			return err
+		}
+	}
+
+	room.Status = models.RoomStatusFinished
+	room.UpdatedAt = time.Now()
+	if err := s.roomRepo.Update(room); err != nil {
+		return err
+	}
+	defer s.closeRoom(room.Code)
+
+	state, err := s.buildState(room)
+	if err != nil {
+		return err
+	}
+	s.publish(room.Code, RoomEventGameEnded, state)
+	return nil
+}
+
+// closeRoom освобождает все, что сервис хранит в памяти для завершенной комнаты.
+// Каналы подписчиков закрываются после game_ended, поэтому открытые WebSocket и
+// SSE соединения получают последнее событие и завершаются.
+func (s *roomService) closeRoom(code string) {
+	s.locks.Delete(code)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.presence, code)
+	delete(s.chosen, code)
+	s.broker.Close(code)
+}
+
+// leave выводит участника из комнаты; если ушел ведущий, ведущим становится следующий участник
+func (s *roomService) leave(room *models.Room, member *models.RoomMember) error {
+	now := time.Now()
+	member.LeftAt = &now
+	if err := s.roomRepo.UpdateMember(member); err != nil {
+		return err
+	}
+	s.publish(room.Code, RoomEventMemberLeft, s.memberState(room.Code, member))
+
+	// Игрок ушедшего участника больше не получает ходов, его очки остаются в таблице
+	if room.Status == models.RoomStatusPlaying && room.GameSessionID != nil {
+		session, err := s.gameService.GetByID(*room.GameSessionID)
+		if err != nil {
+			return err
+		}
+		for _, player := range session.Players {
+			if player.MemberID != nil && *player.MemberID == member.ID {
+				if err := s.gameService.RemovePlayer(session.ID, nil, player.ID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if member.ID != room.HostMemberID {
+		return nil
+	}
+	remaining := activeMembers(room)
+	if len(remaining) == 0 {
+		return s.endGame(room)
+	}
+
+	member.IsHost = false
+	if err := s.roomRepo.UpdateMember(member); err != nil {
+		return err
+	}
+	newHost := remaining[0]
+	newHost.IsHost = true
+	if err := s.roomRepo.UpdateMember(newHost); err != nil {
+		return err
+	}
+	room.HostMemberID = newHost.ID
+	room.UpdatedAt = now
+	if err := s.roomRepo.Update(room); err != nil {
+		return err
+	}
+
+	state, err := s.buildState(room)
+	if err != nil {
+		return err
+	}
+	s.publish(room.Code, RoomEventState, state)
+	return nil
+}
+
+// RemoveBlocked выводит заблокированного пользователя из общих с заблокировавшим комнат
+func (s *roomService) RemoveBlocked(blockerID, blockedID uint) error {
+	codes, err := s.roomRepo.GetSharedRoomCodes(blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+	for _, code := range codes {
+		if err := s.removeUser(code, blockedID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeUser выводит участника пользователя userID из комнаты, если он еще в ней
+func (s *roomService) removeUser(code string, userID uint) error {
+	unlock := s.lock(code)
+	defer unlock()
+
+	room, err := s.getLockedRoom(code)
+	if err != nil {
+		return err
+	}
+	if room.Status == models.RoomStatusFinished {
+		return nil
+	}
+	for _, member := range activeMembers(room) {
+		if member.UserID != nil && *member.UserID == userID {
+			return s.leave(room, member)
+		}
+	}
+	return nil
+}
+
+// buildState собирает полное состояние комнаты
+func (s *roomService) buildState(room *models.Room) (*RoomState, error) {
+	state := &RoomState{
+		Code:         room.Code,
+		Status:       room.Status,
+		CollectionID: room.CollectionID,
+		HostMemberID: room.HostMemberID,
+	}
+	for _, member := range activeMembers(room) {
+		state.Members = append(state.Members, s.memberState(room.Code, member))
+	}
+
+	if room.GameSessionID == nil {
+		return state, nil
+	}
+	session, err := s.gameService.GetByID(*room.GameSessionID)
+	if err != nil {
+		return nil, err
+	}
+	state.Turn = session.Turn
+	state.Round = session.Round
+	state.RemainingCards = s.gameService.RemainingCards(session)
+	state.Rules = &session.Rules
+	for _, entry := range s.gameService.Scoreboard(session) {
+		score := RoomScore{
+			Player:    entry.Player.Name,
+			Score:     entry.Player.Score,
+			Completed: entry.Player.Completed,
+			Skipped:   entry.Player.Skipped,
+			Rank:      entry.Rank,
+		}
+		if member := memberForPlayer(room, entry.Player); member != nil {
+			score.MemberID = member.ID
+		}
+		state.Scoreboard = append(state.Scoreboard, score)
+	}
+
+	if room.Status == models.RoomStatusPlaying {
+		if current := memberForPlayer(room, s.gameService.CurrentPlayer(session)); current != nil {
+			state.CurrentMemberID = current.ID
+			s.mu.Lock()
+			state.ChosenType = s.chosen[room.Code][current.ID]
+			s.mu.Unlock()
+		}
+	}
+
+	if n := len(session.Draws); n > 0 {
+		last := session.Draws[n-1]
+		drawState := &RoomDrawState{
+			Turn:  last.Turn,
+			Round: last.Round,
+			Card:  &RoomCard{ActionID: last.ActionID, Type: last.Type, Text: last.Text},
+		}
+		for _, player := range session.Players {
+			if player.ID == last.PlayerID {
+				drawState.Player = player.Name
+				if member := memberForPlayer(room, player); member != nil {
+					drawState.MemberID = member.ID
+				}
+			}
+		}
+		state.LastDraw = drawState
+	}
+
+	return state, nil
+}
+
+// checkCollection проверяет, что коллекцию комнаты можно показать пользователю с ограничением maxAgeRating
+func (s *roomService) checkCollection(collectionID uint, userID *uint, maxAgeRating int) error {
+	collection, err := s.collectionService.GetByID(collectionID)
+	if err != nil {
+		return err
+	}
+	visibility := models.CollectionVisibility{MaxAgeRating: maxAgeRating, ViewerID: userID}
+	if !visibility.Allows(collection) {
+		return ErrCollectionNotFound
+	}
+	return nil
+}
+
+// currentMember возвращает участника, чей сейчас ход (nil, если он покинул комнату)
+func (s *roomService) currentMember(room *models.Room) (*models.RoomMember, error) {
+	if room.GameSessionID == nil {
+		return nil, ErrRoomNotPlaying
+	}
+	session, err := s.gameService.GetByID(*room.GameSessionID)
+	if err != nil {
+		return nil, err
+	}
+	return memberForPlayer(room, s.gameService.CurrentPlayer(session)), nil
+}
+
+// memberState возвращает публичное состояние участника
+func (s *roomService) memberState(code string, member *models.RoomMember) RoomMemberState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.memberStateLocked(code, member)
+}
+
+// memberStateLocked возвращает публичное состояние участника; вызывается под мьютексом
+func (s *roomService) memberStateLocked(code string, member *models.RoomMember) RoomMemberState {
+	connected := s.presence[code][member.ID] > 0
+	return RoomMemberState{
+		ID:        member.ID,
+		Name:      member.Name,
+		IsHost:    member.IsHost,
+		IsGuest:   member.UserID == nil,
+		Connected: connected,
+	}
+}
+
+// changePresence изменяет счетчик подключений участника и, если его статус изменился,
+// рассылает событие presence. Событие отправляется под мьютексом, чтобы отключение
+// после closeRoom не создало заново тему завершенной комнаты.
+func (s *roomService) changePresence(code string, member *models.RoomMember, delta int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.setPresence(code, member.ID, delta) {
+		s.publish(code, RoomEventPresence, s.memberStateLocked(code, member))
+	}
+}
+
+// setPresence изменяет счетчик подключений участника и сообщает, изменился ли его статус;
+// вызывается под мьютексом
+func (s *roomService) setPresence(code string, memberID uint, delta int) bool {
+	members, ok := s.presence[code]
+	if !ok {
+		members = make(map[uint]int)
+		s.presence[code] = members
+	}
+	before := members[memberID]
+	after := before + delta
+	if after <= 0 {
+		delete(members, memberID)
+		after = 0
+	} else {
+		members[memberID] = after
+	}
+	if len(members) == 0 {
+		delete(s.presence, code)
+	}
+	return (before > 0) != (after > 0)
+}
+
+// publish отправляет событие всем подписчикам комнаты
+func (s *roomService) publish(code string, eventType string, data interface{}) realtime.Event {
+	return s.broker.Publish(code, realtime.Event{Type: eventType, Data: data})
+}
+
+// memberName определяет имя участника: для пользователя по умолчанию берется имя из профиля
+func (s *roomService) memberName(userID *uint, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" && userID != nil {
+		user, err := s.userRepo.GetByID(*userID)
+		if err != nil {
+			return "", ErrInvalidUserID
+		}
+		name = strings.TrimSpace(user.Name)
+	}
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required for guests", ErrInvalidPlayers)
+	}
+	return name, nil
+}
+
+// generateCode подбирает свободный код комнаты
+func (s *roomService) generateCode() (string, error) {
+	alphabetSize := big.NewInt(int64(len(roomCodeAlphabet)))
+	for attempt := 0; attempt < 10; attempt++ {
+		var code strings.Builder
+		for i := 0; i < roomCodeLength; i++ {
+			n, err := rand.Int(rand.Reader, alphabetSize)
+			if err != nil {
+				return "", err
+			}
+			code.WriteByte(roomCodeAlphabet[n.Int64()])
+		}
+		if _, err := s.roomRepo.GetByCode(code.String()); err != nil {
+			return code.String(), nil
+		}
+	}
+	return "", errors.New("failed to generate unique room code")
+}
+
+// getRoom загружает комнату по коду
+func (s *roomService) getRoom(code string) (*models.Room, error) {
+	room, err := s.roomRepo.GetByCode(code)
+	if err != nil {
+		return nil, ErrRoomNotFound
+	}
+	return room, nil
+}
+
+// getLockedRoom загружает комнату, блокировка которой уже захвачена. Блокировка
+// ненайденной или завершенной комнаты удаляется: такая комната больше не изменится.
+func (s *roomService) getLockedRoom(code string) (*models.Room, error) {
+	room, err := s.getRoom(code)
+	if err != nil || room.Status == models.RoomStatusFinished {
+		s.locks.Delete(code)
+	}
+	return room, err
+}
+
+// lock захватывает блокировку комнаты и возвращает функцию для ее освобождения
+func (s *roomService) lock(code string) func() {
+	value, _ := s.locks.LoadOrStore(code, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// normalizeRoomCode приводит код комнаты к каноническому виду
+func normalizeRoomCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// activeMembers возвращает участников, не покинувших комнату
+func activeMembers(room *models.Room) []*models.RoomMember {
+	members := make([]*models.RoomMember, 0, len(room.Members))
+	for _, member := range room.Members {
+		if member.LeftAt == nil {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// findMember ищет участника комнаты по ID
+func findMember(room *models.Room, memberID uint) *models.RoomMember {
+	for _, member := range room.Members {
+		if member.ID == memberID {
+			return member
+		}
+	}
+	return nil
+}
+
+// memberForPlayer возвращает участника комнаты, который ходит за игрока сессии.
+// Вышедшие игроки и ушедшие участники не сопоставляются; по имени сопоставляются
+// только игроки сессий, начатых до привязки к участникам.
+func memberForPlayer(room *models.Room, player *models.GamePlayer) *models.RoomMember {
+	if player == nil || player.LeftAt != nil {
+		return nil
+	}
+	for _, member := range activeMembers(room) {
+		if player.MemberID != nil {
+			if *player.MemberID == member.ID {
+				return member
+			}
+			continue
+		}
+		if strings.EqualFold(member.Name, player.Name) {
+			return member
+		}
+	}
+	return nil
+}
+
+// newRoomCard преобразует карточку для передачи в событиях комнаты
+func newRoomCard(action *models.Action, text string, extra models.ActionExtra) *RoomCard {
+	return &RoomCard{
+		ActionID:     action.ID,
+		Type:         action.Type,
+		Text:         text,
+		Extra:        extra,
+		Difficulty:   action.Difficulty,
+		Spiciness:    action.Spiciness,
+		TimerSeconds: action.TimerSeconds,
+		Points:       action.Points,
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/realtime"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+// fakeRoomRepo хранит одну комнату в памяти; остальные методы не используются
+type fakeRoomRepo struct {
+	repository.RoomRepository
+	room *models.Room
+}
+
+func (r *fakeRoomRepo) GetByCode(code string) (*models.Room, error) {
+	if code != r.room.Code {
+		return nil, errors.New("record not found")
+	}
+	return r.room, nil
+}
+
+func (r *fakeRoomRepo) Update(room *models.Room) error {
+	return nil
+}
+
+func TestRoomServiceEndGameClosesTopic(t *testing.T) {
+	const code = "ABCD"
+	host := &models.RoomMember{ID: 1, Name: "Аня", IsHost: true}
+	guest := &models.RoomMember{ID: 2, Name: "Борис"}
+	room := &models.Room{ID: 1, Code: code, HostMemberID: host.ID, Status: models.RoomStatusLobby, Members: []*models.RoomMember{host, guest}}
+	broker := realtime.NewMemoryBroker()
+	service := NewRoomService(&fakeRoomRepo{room: room}, nil, nil, nil, nil, nil, nil, nil, broker)
+
+	conn, err := service.Connect(code, guest.ID, 0)
+	if err != nil {
+		t.Fatalf("Connect() unexpected error: %v", err)
+	}
+	if err := service.Execute(code, host.ID, RoomCommand{Type: RoomCommandEnd}); err != nil {
+		t.Fatalf("end game: unexpected error: %v", err)
+	}
+
+	// Подписчик получает game_ended, после чего канал закрывается
+	var last string
+	for event := range conn.Events {
+		last = event.Type
+	}
+	if last != RoomEventGameEnded {
+		t.Errorf("last event = %q, want %q", last, RoomEventGameEnded)
+	}
+	// Отключение после завершения не публикует presence в закрытую комнату
+	conn.Close()
+
+	sub := broker.SubscribeSince(code, 0)
+	defer sub.Unsubscribe()
+	if sub.LastID != 0 || len(sub.Missed) > 0 {
+		t.Errorf("topic of the ended room is kept: last event %d, log %d events", sub.LastID, len(sub.Missed))
+	}
+
+	if _, err := service.Connect(code, guest.ID, 0); !errors.Is(err, ErrRoomFinished) {
+		t.Errorf("Connect() to ended room error = %v, want %v", err, ErrRoomFinished)
+	}
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+)
+
+var (
+	ErrSuggestionNotFound      = errors.New("suggestion not found")
+	ErrSuggestionReviewed      = errors.New("suggestion has already been reviewed")
+	ErrOwnCollectionSuggest    = errors.New("owners add cards to their collections directly")
+	ErrSuggestionRateLimited   = errors.New("too many suggestions, try again later")
+	ErrInvalidSuggestionStatus = errors.New("invalid suggestion status")
+	ErrRejectReasonTooLong     = errors.New("reject reason is too long")
+)
+
+const (
+	// MaxRejectReasonLength ограничивает длину причины отказа в символах
+	MaxRejectReasonLength = 500
+
+	// Не больше suggestionRateLimit предложений за suggestionRateWindow от одного пользователя
+	suggestionRateLimit  = 20
+	suggestionRateWindow = time.Hour
+)
+
+// SuggestionService определяет методы сервиса предложенных карточек
+type SuggestionService interface {
+	Suggest(userID, collectionID uint, action *models.Action) (*models.CardSuggestion, error)
+	Accept(ownerID, suggestionID uint) (*models.CardSuggestion, error)
+	Reject(ownerID, suggestionID uint, reason string) (*models.CardSuggestion, error)
+	GetInbox(ownerID uint, collectionID *uint, status models.SuggestionStatus, page, pageSize int) ([]*models.CardSuggestion, int64, error)
+	GetMine(userID uint, status models.SuggestionStatus, page, pageSize int) ([]*models.CardSuggestion, int64, error)
+}
+
+// suggestionService реализует интерфейс SuggestionService
+type suggestionService struct {
+	suggestionRepo      repository.SuggestionRepository
+	collectionRepo      repository.CollectionRepository
+	blockRepo           repository.BlockRepository
+	collectionService   CollectionService
+	notificationService NotificationService
+	limiter             *rateLimiter
+
+	// locks сериализует решения по одному предложению; запись удаляется, когда решение принято
+	locks sync.Map
+}
+
+// NewSuggestionService создает новый экземпляр сервиса предложенных карточек
+func NewSuggestionService(
+	suggestionRepo repository.SuggestionRepository,
+	collectionRepo repository.CollectionRepository,
+	blockRepo repository.BlockRepository,
+	collectionService CollectionService,
+	notificationService NotificationService,
+) SuggestionService {
+	return &suggestionService{
+		suggestionRepo:      suggestionRepo,
+		collectionRepo:      collectionRepo,
+		blockRepo:           blockRepo,
+		collectionService:   collectionService,
+		notificationService: notificationService,
+		limiter:             newRateLimiter(suggestionRateLimit, suggestionRateWindow),
+	}
+}
+
+// Suggest предлагает карточку в чужую коллекцию. Карточка проверяется так же,
+// как при добавлении владельцем, чтобы принятие не завершилось ошибкой валидации.
+func (s *suggestionService) Suggest(userID, collectionID uint, action *models.Action) (*models.CardSuggestion, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	collection, err := s.collectionRepo.GetByID(collectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+	if collection.IsVirtual {
+		return nil, ErrVirtualCollection
+	}
+	if collection.UserID == userID {
+		return nil, ErrOwnCollectionSuggest
+	}
+	if err := checkNotBlocked(s.blockRepo, userID, collection.UserID); err != nil {
+		return nil, err
+	}
+
+	applyActionDefaults(action)
+	if err := validateAction(action); err != nil {
+		return nil, err
+	}
+
+	if !s.limiter.Allow(userID) {
+		return nil, ErrSuggestionRateLimited
+	}
+
+	suggestion := &models.CardSuggestion{
+		CollectionID: collectionID,
+		UserID:       userID,
+		Text:         action.Text,
+		Type:         action.Type,
+		Extra:        action.Extra,
+		Difficulty:   action.Difficulty,
+		Spiciness:    action.Spiciness,
+		TimerSeconds: action.TimerSeconds,
+		Points:       action.Points,
+		Status:       models.SuggestionPending,
+	}
+	if err := s.suggestionRepo.Create(suggestion); err != nil {
+		return nil, err
+	}
+	s.notificationService.Notify(NotificationEvent{
+		Type:         models.NotificationSuggestion,
+		RecipientID:  collection.UserID,
+		ActorID:      userID,
+		CollectionID: &collection.ID,
+	})
+	return s.get(suggestion.ID)
+}
+
+// Accept добавляет предложенную карточку в коллекцию через collectionService.AddAction
+func (s *suggestionService) Accept(ownerID, suggestionID uint) (*models.CardSuggestion, error) {
+	unlock := s.lock(suggestionID)
+	defer unlock()
+
+	suggestion, err := s.reviewable(ownerID, suggestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Сначала переводим предложение в принятые: карточка добавляется только тем
+	// запросом, который успел это сделать
+	now := time.Now()
+	suggestion.Status = models.SuggestionAccepted
+	suggestion.ReviewedAt = &now
+	if err := s.review(suggestion); err != nil {
+		return nil, err
+	}
+
+	action := suggestion.ToAction()
+	if err := s.collectionService.AddAction(suggestion.CollectionID, action); err != nil {
+		// Карточку не удалось добавить: предложение снова ждет рассмотрения
+		suggestion.Status = models.SuggestionPending
+		suggestion.ReviewedAt = nil
+		if updateErr := s.suggestionRepo.Update(suggestion); updateErr != nil {
+			return nil, updateErr
+		}
+		return nil, err
+	}
+
+	suggestion.ActionID = &action.ID
+	if err := s.suggestionRepo.Update(suggestion); err != nil {
+		return nil, err
+	}
+	return suggestion, nil
+}
+
+// Reject отклоняет предложение с необязательной причиной, которую увидит автор
+func (s *suggestionService) Reject(ownerID, suggestionID uint, reason string) (*models.CardSuggestion, error) {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > MaxRejectReasonLength {
+		return nil, ErrRejectReasonTooLong
+	}
+
+	unlock := s.lock(suggestionID)
+	defer unlock()
+
+	suggestion, err := s.reviewable(ownerID, suggestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	suggestion.Status = models.SuggestionRejected
+	suggestion.RejectReason = reason
+	suggestion.ReviewedAt = &now
+	if err := s.review(suggestion); err != nil {
+		return nil, err
+	}
+	return suggestion, nil
+}
+
+// review сохраняет решение по предложению или возвращает ErrSuggestionReviewed,
+// если решение уже принято
+func (s *suggestionService) review(suggestion *models.CardSuggestion) error {
+	updated, err := s.suggestionRepo.Review(suggestion)
+	if err != nil {
+		return err
+	}
+	// Решение принято этим или другим запросом: блокировка предложения больше не нужна.
+	// Повторное решение по нему отсекается условием в Review.
+	s.locks.Delete(suggestion.ID)
+	if !updated {
+		return ErrSuggestionReviewed
+	}
+	return nil
+}
+
+// GetInbox возвращает предложения к коллекциям владельца
+func (s *suggestionService) GetInbox(ownerID uint, collectionID *uint, status models.SuggestionStatus, page, pageSize int) ([]*models.CardSuggestion, int64, error) {
+	if status != "" && !status.IsValid() {
+		return nil, 0, ErrInvalidSuggestionStatus
+	}
+	if collectionID != nil {
+		collection, err := s.collectionRepo.GetByID(*collectionID)
+		if err != nil {
+			return nil, 0, ErrCollectionNotFound
+		}
+		if collection.UserID != ownerID {
+			return nil, 0, ErrNotCollectionOwner
+		}
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return s.suggestionRepo.GetInbox(ownerID, collectionID, status, (page-1)*pageSize, pageSize)
+}
+
+// GetMine возвращает предложения пользователя вместе с их статусами
+func (s *suggestionService) GetMine(userID uint, status models.SuggestionStatus, page, pageSize int) ([]*models.CardSuggestion, int64, error) {
+	if status != "" && !status.IsValid() {
+		return nil, 0, ErrInvalidSuggestionStatus
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return s.suggestionRepo.GetByUserID(userID, status, (page-1)*pageSize, pageSize)
+}
+
+// reviewable возвращает предложение, по которому владелец коллекции еще не принял решение
+func (s *suggestionService) reviewable(ownerID, suggestionID uint) (*models.CardSuggestion, error) {
+	suggestion, err := s.get(suggestionID)
+	if err != nil {
+		s.locks.Delete(suggestionID)
+		return nil, err
+	}
+	collection, err := s.collectionRepo.GetByID(suggestion.CollectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+	if collection.UserID != ownerID {
+		return nil, ErrNotCollectionOwner
+	}
+	if suggestion.Status != models.SuggestionPending {
+		s.locks.Delete(suggestionID)
+		return nil, ErrSuggestionReviewed
+	}
+	return suggestion, nil
+}
+
+// get возвращает предложение или ErrSuggestionNotFound
+func (s *suggestionService) get(suggestionID uint) (*models.CardSuggestion, error) {
+	suggestion, err := s.suggestionRepo.GetByID(suggestionID)
+	if err != nil {
+		return nil, ErrSuggestionNotFound
+	}
+	return suggestion, nil
+}
+
+// lock захватывает блокировку предложения и возвращает функцию для ее освобождения
+func (s *suggestionService) lock(suggestionID uint) func() {
+	value, _ := s.locks.LoadOrStore(suggestionID, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
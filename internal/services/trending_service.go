@@ -0,0 +1,135 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/KoLili12/bulb-server/internal/models"
+	"github.com/KoLili12/bulb-server/internal/repository"
+	"github.com/KoLili12/bulb-server/pkg/config"
+)
+
+// maxTrendingRows ограничивает количество коллекций, сохраняемых в рейтинге одного периода
+const maxTrendingRows = 1000
+
+// TrendingService определяет методы пересчета популярных коллекций
+type TrendingService interface {
+	Refresh() error
+	RefreshInterval() time.Duration
+}
+
+// trendingService реализует интерфейс TrendingService
+type trendingService struct {
+	trendingRepo repository.TrendingRepository
+	config       config.TrendingConfig
+}
+
+// NewTrendingService создает новый экземпляр сервиса популярных коллекций
+func NewTrendingService(trendingRepo repository.TrendingRepository, cfg config.TrendingConfig) TrendingService {
+	// Нулевое или отрицательное смещение дало бы деление на ноль для самых свежих событий
+	if cfg.AgeOffsetHours <= 0 {
+		cfg.AgeOffsetHours = 2
+	}
+	if cfg.Gravity < 0 {
+		cfg.Gravity = 0
+	}
+	if cfg.RefreshMinutes <= 0 {
+		cfg.RefreshMinutes = 10
+	}
+
+	return &trendingService{
+		trendingRepo: trendingRepo,
+		config:       cfg,
+	}
+}
+
+// RefreshInterval возвращает, как часто нужно пересчитывать рейтинг
+func (s *trendingService) RefreshInterval() time.Duration {
+	return time.Duration(s.config.RefreshMinutes) * time.Minute
+}
+
+// Refresh пересчитывает рейтинг популярности для всех периодов
+func (s *trendingService) Refresh() error {
+	now := time.Now()
+	for _, window := range models.TrendingWindows {
+		if err := s.refreshWindow(window, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshWindow рассчитывает и сохраняет рейтинг одного периода
+func (s *trendingService) refreshWindow(window models.TrendingWindow, now time.Time) error {
+	decay := repository.TrendingDecay{
+		Now:         now,
+		OffsetHours: s.config.AgeOffsetHours,
+		Gravity:     s.config.Gravity,
+	}
+	if duration := window.Duration(); duration > 0 {
+		since := now.Add(-duration)
+		decay.Since = &since
+	} else {
+		// За все время события не затухают: рейтинг отражает суммарную популярность
+		decay.Gravity = 0
+	}
+
+	plays, err := s.trendingRepo.GetPlaySignals(decay)
+	if err != nil {
+		return err
+	}
+	forks, err := s.trendingRepo.GetForkSignals(decay)
+	if err != nil {
+		return err
+	}
+	likes, err := s.trendingRepo.GetLikeSignals(decay)
+	if err != nil {
+		return err
+	}
+
+	scores := make(map[uint]*models.TrendingScore)
+	score := func(collectionID uint) *models.TrendingScore {
+		entry, ok := scores[collectionID]
+		if !ok {
+			entry = &models.TrendingScore{Window: window, CollectionID: collectionID, ComputedAt: now}
+			scores[collectionID] = entry
+		}
+		return entry
+	}
+	for _, signal := range plays {
+		entry := score(signal.CollectionID)
+		entry.Plays = signal.Count
+		entry.Score += s.config.PlayWeight * signal.Weight
+	}
+	for _, signal := range forks {
+		entry := score(signal.CollectionID)
+		entry.Forks = signal.Count
+		entry.Score += s.config.ForkWeight * signal.Weight
+	}
+	for _, signal := range likes {
+		entry := score(signal.CollectionID)
+		entry.Likes = signal.Count
+		entry.Score += s.config.LikeWeight * signal.Weight
+	}
+
+	ranked := make([]*models.TrendingScore, 0, len(scores))
+	for _, entry := range scores {
+		if entry.Score > 0 {
+			ranked = append(ranked, entry)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].CollectionID < ranked[j].CollectionID
+	})
+	if len(ranked) > maxTrendingRows {
+		ranked = ranked[:maxTrendingRows]
+	}
+	for i, entry := range ranked {
+		entry.Rank = i + 1
+	}
+
+	return s.trendingRepo.ReplaceWindow(window, ranked)
+}
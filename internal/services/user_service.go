@@ -13,8 +13,13 @@ var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrEmailAlreadyExists = errors.New("email already exists")
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrUserSuspended      = errors.New("user is suspended")
+	ErrInvalidBirthDate   = errors.New("invalid birth date")
 )
 
+// maxUserAge ограничивает возраст по дате рождения, чтобы отсечь опечатки в годе
+const maxUserAge = 120
+
 // UserService определяет методы сервиса пользователей
 type UserService interface {
 	Create(user *models.User) error
@@ -40,6 +45,10 @@ func NewUserService(userRepo repository.UserRepository) UserService {
 
 // Create создает нового пользователя
 func (s *userService) Create(user *models.User) error {
+	if err := validateBirthDate(user, time.Now()); err != nil {
+		return err
+	}
+
 	// Проверяем, не существует ли уже пользователь с таким email
 	existingUser, err := s.userRepo.GetByEmail(user.Email)
 	if err == nil && existingUser != nil {
@@ -88,6 +97,10 @@ func (s *userService) Update(user *models.User) error {
 		return ErrUserNotFound
 	}
 
+	if err := validateBirthDate(user, time.Now()); err != nil {
+		return err
+	}
+
 	// Если email был изменен, проверяем его уникальность
 	if user.Email != existingUser.Email {
 		userWithSameEmail, err := s.userRepo.GetByEmail(user.Email)
@@ -153,5 +166,22 @@ func (s *userService) Authenticate(email, password string) (*models.User, error)
 		return nil, ErrInvalidCredentials
 	}
 
+	// Заблокированный модератором пользователь не может войти до окончания блокировки
+	if user.IsSuspended(time.Now()) {
+		return nil, ErrUserSuspended
+	}
+
 	return user, nil
 }
+
+// validateBirthDate проверяет, что дата рождения не в будущем и правдоподобна
+func validateBirthDate(user *models.User, now time.Time) error {
+	if user.BirthDate == nil {
+		return nil
+	}
+	age, _ := user.Age(now)
+	if user.BirthDate.After(now) || age > maxUserAge {
+		return ErrInvalidBirthDate
+	}
+	return nil
+}
@@ -5,15 +5,19 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/viper"
 )
 
 // Config содержит все конфигурационные параметры приложения
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	JWT           JWTConfig
+	Trending      TrendingConfig
+	ContentFilter ContentFilterConfig
+	Push          PushConfig
 }
 
 // ServerConfig содержит настройки HTTP-сервера
@@ -38,6 +42,61 @@ type JWTConfig struct {
 	ExpiresIn int // время жизни токена в часах
 }
 
+// TrendingConfig содержит веса и параметры затухания для расчета популярных коллекций.
+// Вклад каждого события делится на (возраст в часах + AgeOffsetHours)^Gravity.
+type TrendingConfig struct {
+	PlayWeight     float64
+	LikeWeight     float64
+	ForkWeight     float64
+	Gravity        float64
+	AgeOffsetHours float64
+	RefreshMinutes int // Как часто пересчитывается таблица популярных коллекций
+}
+
+// ContentFilterConfig содержит настройки фильтра нецензурной лексики в коллекциях и карточках
+type ContentFilterConfig struct {
+	Mode                string   // off, reject, flag или adult
+	WordLists           []string // Пути к дополнительным словарям
+	DisableDefaultLists bool     // Не использовать встроенные словари
+}
+
+// PushConfig содержит настройки доставки push-уведомлений.
+// Платформа без ключей не получает push, но устройства на ней регистрируются.
+type PushConfig struct {
+	FCMCredentialsFile string // JSON-ключ сервисного аккаунта Firebase (Android)
+	APNsKeyFile        string // Ключ .p8 для Apple Push Notification service (iOS)
+	APNsKeyID          string
+	APNsTeamID         string
+	APNsTopic          string // Bundle ID приложения
+	APNsSandbox        bool   // Окружение разработки Apple
+	Workers            int    // Одновременных отправок
+	QueueSize          int    // Сколько сообщений может ждать отправки
+	MaxAttempts        int    // Попыток на сообщение при временных ошибках
+	RetryDelaySeconds  int    // Пауза перед первым повтором, дальше удваивается
+}
+
+// defaultPushConfig возвращает параметры очереди push-уведомлений по умолчанию
+func defaultPushConfig() PushConfig {
+	return PushConfig{
+		Workers:           4,
+		QueueSize:         1000,
+		MaxAttempts:       4,
+		RetryDelaySeconds: 5,
+	}
+}
+
+// defaultTrendingConfig возвращает параметры расчета популярности по умолчанию
+func defaultTrendingConfig() TrendingConfig {
+	return TrendingConfig{
+		PlayWeight:     1,
+		LikeWeight:     3,
+		ForkWeight:     5,
+		Gravity:        1.8,
+		AgeOffsetHours: 2,
+		RefreshMinutes: 10,
+	}
+}
+
 // LoadConfig загружает конфигурацию из файла config.yml в указанной директории
 func LoadConfig(path string) (*Config, error) {
 	// Определяем среду выполнения
@@ -68,6 +127,19 @@ func loadFromFile(path string) (*Config, error) {
 	viper.SetDefault("server.port", "8080")
 	viper.SetDefault("server.mode", "debug")
 	viper.SetDefault("jwt.expiresin", 24)
+	trending := defaultTrendingConfig()
+	viper.SetDefault("trending.playweight", trending.PlayWeight)
+	viper.SetDefault("trending.likeweight", trending.LikeWeight)
+	viper.SetDefault("trending.forkweight", trending.ForkWeight)
+	viper.SetDefault("trending.gravity", trending.Gravity)
+	viper.SetDefault("trending.ageoffsethours", trending.AgeOffsetHours)
+	viper.SetDefault("trending.refreshminutes", trending.RefreshMinutes)
+	viper.SetDefault("contentfilter.mode", "adult")
+	push := defaultPushConfig()
+	viper.SetDefault("push.workers", push.Workers)
+	viper.SetDefault("push.queuesize", push.QueueSize)
+	viper.SetDefault("push.maxattempts", push.MaxAttempts)
+	viper.SetDefault("push.retrydelayseconds", push.RetryDelaySeconds)
 
 	// Чтение файла конфигурации
 	if err := viper.ReadInConfig(); err != nil {
@@ -114,6 +186,37 @@ func loadFromEnv() (*Config, error) {
 			Secret:    os.Getenv("JWT_SECRET"),
 			ExpiresIn: expiresIn,
 		},
+		Trending: defaultTrendingConfig(),
+		ContentFilter: ContentFilterConfig{
+			Mode:                getEnvOrDefault("CONTENT_FILTER_MODE", "adult"),
+			DisableDefaultLists: os.Getenv("CONTENT_FILTER_DISABLE_DEFAULT_LISTS") == "true",
+		},
+	}
+	if lists := os.Getenv("CONTENT_FILTER_WORD_LISTS"); lists != "" {
+		config.ContentFilter.WordLists = strings.Split(lists, ",")
+	}
+
+	// Push-уведомления: ключи платформ и необязательные параметры очереди
+	config.Push = defaultPushConfig()
+	config.Push.FCMCredentialsFile = os.Getenv("PUSH_FCM_CREDENTIALS_FILE")
+	config.Push.APNsKeyFile = os.Getenv("PUSH_APNS_KEY_FILE")
+	config.Push.APNsKeyID = os.Getenv("PUSH_APNS_KEY_ID")
+	config.Push.APNsTeamID = os.Getenv("PUSH_APNS_TEAM_ID")
+	config.Push.APNsTopic = os.Getenv("PUSH_APNS_TOPIC")
+	config.Push.APNsSandbox = os.Getenv("PUSH_APNS_SANDBOX") == "true"
+	config.Push.Workers = getEnvInt("PUSH_WORKERS", config.Push.Workers)
+	config.Push.QueueSize = getEnvInt("PUSH_QUEUE_SIZE", config.Push.QueueSize)
+	config.Push.MaxAttempts = getEnvInt("PUSH_MAX_ATTEMPTS", config.Push.MaxAttempts)
+	config.Push.RetryDelaySeconds = getEnvInt("PUSH_RETRY_DELAY_SECONDS", config.Push.RetryDelaySeconds)
+
+	// Параметры популярности необязательны и переопределяются по одному
+	config.Trending.PlayWeight = getEnvFloat("TRENDING_PLAY_WEIGHT", config.Trending.PlayWeight)
+	config.Trending.LikeWeight = getEnvFloat("TRENDING_LIKE_WEIGHT", config.Trending.LikeWeight)
+	config.Trending.ForkWeight = getEnvFloat("TRENDING_FORK_WEIGHT", config.Trending.ForkWeight)
+	config.Trending.Gravity = getEnvFloat("TRENDING_GRAVITY", config.Trending.Gravity)
+	config.Trending.AgeOffsetHours = getEnvFloat("TRENDING_AGE_OFFSET_HOURS", config.Trending.AgeOffsetHours)
+	if refresh, err := strconv.Atoi(os.Getenv("TRENDING_REFRESH_MINUTES")); err == nil && refresh > 0 {
+		config.Trending.RefreshMinutes = refresh
 	}
 
 	// Логируем что получили (без паролей)
@@ -150,4 +253,20 @@ func getEnvOrDefault(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// getEnvInt возвращает положительное целое значение переменной окружения или значение по умолчанию
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvFloat возвращает числовое значение переменной окружения или значение по умолчанию
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return defaultValue
+}